@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+// markdownDocsTemplate renders the command reference as markdown, using the
+// same template functions as the kingpin usage and man page templates
+const markdownDocsTemplate = `{{define "FormatCommand" -}}
+{{if .FlagSummary}} {{.FlagSummary}}{{end -}}
+{{range .Args}}{{if not .Hidden}} {{if not .Required}}[{{end}}{{if .PlaceHolder}}{{.PlaceHolder}}{{else}}<{{.Name}}>{{end}}{{if .Value|IsCumulative}}...{{end}}{{if not .Required}}]{{end}}{{end}}{{end -}}
+{{end -}}
+
+{{define "FormatFlags" -}}
+| Flag | Description |
+| ---- | ----------- |
+{{range .Flags -}}
+{{if not .Hidden -}}
+| ` + "`" + `{{if .Short}}-{{.Short|Char}}, {{end}}--{{.Name}}{{if not .IsBoolFlag}}={{.FormatPlaceHolder}}{{end}}` + "`" + ` | {{.Help}} |
+{{end -}}
+{{end -}}
+{{end -}}
+
+# {{.App.Name}}
+
+{{.App.Help}}
+
+## Usage
+
+` + "```" + `
+{{.App.Name}}{{template "FormatCommand" .App}}{{if .App.Commands}} <command> [<args> ...]{{end}}
+` + "```" + `
+
+## Global flags
+
+{{template "FormatFlags" .App}}
+## Commands
+{{range .App.FlattenedCommands -}}
+{{if not .Hidden}}
+### {{.FullCommand}}
+
+{{.Help}}
+
+` + "```" + `
+{{$.App.Name}} {{.FullCommand}}{{template "FormatCommand" .}}
+` + "```" + `
+{{if .Flags}}
+{{template "FormatFlags" .}}{{end -}}
+{{end -}}
+{{end -}}
+`
+
+func handleDocs(w io.Writer) error {
+	template := markdownDocsTemplate
+	if *docsFormat == "man" {
+		template = kingpin.ManPageTemplate
+	}
+
+	context, err := app.ParseContext(nil)
+	if err != nil {
+		return fmt.Errorf("Error generating documentation: %v", err)
+	}
+
+	app.UsageWriter(w)
+	defer app.UsageWriter(os.Stderr)
+	if err := app.UsageForContextWithTemplate(context, 2, template); err != nil {
+		return fmt.Errorf("Error generating documentation: %v", err)
+	}
+	return nil
+}
@@ -1,23 +1,54 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/peiman/changie/internal/calver"
 	"github.com/peiman/changie/internal/changelog"
 	"github.com/peiman/changie/internal/git"
+	"github.com/peiman/changie/internal/logger"
 	"github.com/peiman/changie/internal/semver"
+	"go.uber.org/zap"
 )
 
 // Interfaces for dependency injection
 type ChangelogManager interface {
 	InitProject(string) error
-	UpdateChangelog(string, string, string) error
+	UpdateChangelog(string, string, changelog.UpdateOptions) error
 	AddChangelogSection(string, string, string) (bool, error)
 	GetChangelogContent() (string, error)
+	OpenChangelog() (io.ReadCloser, error)
+	WrapChangelog(string, int, bool) (bool, error)
+	ReorderChangelog(string, bool, bool) (bool, error)
+	FixDates(string, string, bool) (changelog.DatesResult, error)
+	SetSectionEntries(string, string, []string) error
+	ReplaceOrAddEntry(string, string, string, string) (bool, error)
+	MigrateChangelog(string, bool) (bool, error)
+	FixLinks(string) ([]string, error)
+	FixMissingLinks(string, changelog.UpdateOptions) ([]string, error)
+	ArchiveChangelog(string, int, bool) ([]changelog.Archive, error)
+	MoveVersion(string, string, string, bool) error
+	LintChangelog(string, changelog.LintOptions, bool, bool) (changelog.LintResult, error)
+	SetUnreleasedDate(string, string) error
+	BackupChangelog(string, int) (string, error)
+	RenameChangelog(string, string) (int, error)
 }
 
 type GitManager interface {
@@ -26,23 +57,146 @@ type GitManager interface {
 	HasUncommittedChanges() (bool, error)
 	PushChanges() error
 	GetVersion() (string, error)
+	CreateBranch(string) error
+	TagVersionWithMessage(string, string) error
+	ListTags() ([]string, error)
+	TagExists(string) (bool, error)
+	DeleteTag(string, string) error
+	VerifyTag(string) (bool, string, error)
+	VerifyCommit(string) (bool, string, error)
+	PushTag(string, string) error
+	IsDetachedHead() (bool, error)
+	IsShallow() (bool, error)
+	IsRepository() (bool, error)
+	GetHeadCommit() (string, error)
+	GetLastTag() (string, error)
+	RefExists(string) bool
+	GetCommitsBetween(string, string) ([]git.Commit, error)
+	GetRemoteURL(string) (string, error)
+	DefaultBranch(string) (string, error)
 }
 
 type SemverManager interface {
 	BumpMajor(string) (string, error)
 	BumpMinor(string) (string, error)
 	BumpPatch(string) (string, error)
+	BumpToConstraint(string, string) (string, error)
 }
 
 // Default implementations
 type DefaultChangelogManager struct{}
 
-func (m DefaultChangelogManager) InitProject(file string) error { return changelog.InitProject(file) }
-func (m DefaultChangelogManager) UpdateChangelog(file, version, provider string) error {
-	return changelog.UpdateChangelog(file, version, provider)
+func (m DefaultChangelogManager) InitProject(file string) error {
+	return changelog.InitProjectWithTitle(file, *initTitle)
+}
+func (m DefaultChangelogManager) UpdateChangelog(file, version string, opts changelog.UpdateOptions) error {
+	return changelog.UpdateChangelogWithOptions(file, version, opts)
 }
 func (m DefaultChangelogManager) AddChangelogSection(file, section, content string) (bool, error) {
-	return changelog.AddChangelogSection(file, section, content)
+	return changelog.AddChangelogSectionWithOptions(file, section, content, changelog.AddOptions{
+		WrapWidth: *wrapWidth,
+	})
+}
+
+func (m DefaultChangelogManager) SetSectionEntries(file, section string, entries []string) error {
+	return changelog.SetSectionEntriesWithOptions(file, section, entries, changelog.AddOptions{
+		WrapWidth: *wrapWidth,
+	})
+}
+
+func (m DefaultChangelogManager) ReplaceOrAddEntry(file, section, match, content string) (bool, error) {
+	return changelog.ReplaceOrAddEntryWithOptions(file, section, match, content, changelog.AddOptions{
+		WrapWidth: *wrapWidth,
+	})
+}
+
+func (m DefaultChangelogManager) WrapChangelog(file string, width int, check bool) (bool, error) {
+	return changelog.WrapChangelog(file, width, check)
+}
+
+func (m DefaultChangelogManager) ReorderChangelog(file string, all, check bool) (bool, error) {
+	return changelog.ReorderChangelog(file, all, check)
+}
+func (m DefaultChangelogManager) FixDates(file, format string, check bool) (changelog.DatesResult, error) {
+	return changelog.FixDates(file, format, check)
+}
+
+func (m DefaultChangelogManager) LintChangelog(file string, opts changelog.LintOptions, fix, check bool) (changelog.LintResult, error) {
+	return changelog.LintChangelogFile(file, opts, fix, check)
+}
+
+func (m DefaultChangelogManager) FixLinks(file string) ([]string, error) {
+	return changelog.FixLinks(file)
+}
+func (m DefaultChangelogManager) FixMissingLinks(file string, opts changelog.UpdateOptions) ([]string, error) {
+	return changelog.FixMissingLinks(file, opts)
+}
+
+// linkOptions returns the options that version links are built with. Links
+// point to --base-url, or to the origin remote when its URL can be parsed, and
+// use the existing tags, which may or may not have a "v" prefix. On Azure
+// DevOps, Unreleased is compared with the default branch of the remote.
+func linkOptions(gitManager GitManager, provider string) changelog.UpdateOptions {
+	opts := changelog.UpdateOptions{Provider: provider, LinkStyle: *linkStyle}
+	if *baseURL != "" {
+		opts.RepositoryURL = *baseURL
+	} else if url, err := gitManager.GetRemoteURL(defaultRemote); err == nil {
+		if repo, err := git.ParseRepositoryURL(url); err == nil {
+			opts.RepositoryURL = repo.WebURL()
+			if repo.Provider != "" && !remoteRepositoryProviderSet {
+				opts.Provider = repo.Provider
+			}
+		}
+	}
+
+	if branch, err := gitManager.DefaultBranch(defaultRemote); err == nil {
+		opts.DefaultBranch = branch
+	}
+
+	if tags, err := gitManager.ListTags(); err == nil {
+		opts.Tags = semver.VersionTags(tags)
+	} else {
+		opts.Tags = map[string]string{}
+	}
+	return opts
+}
+
+func (m DefaultChangelogManager) MoveVersion(file, version, target string, after bool) error {
+	return changelog.MoveVersion(file, version, target, after)
+}
+
+func (m DefaultChangelogManager) ArchiveChangelog(file string, year int, dryRun bool) ([]changelog.Archive, error) {
+	return changelog.ArchiveChangelog(file, year, dryRun)
+}
+
+func (m DefaultChangelogManager) BackupChangelog(file string, keep int) (string, error) {
+	return changelog.BackupFile(file, keep)
+}
+
+// RenameChangelog moves a tracked changelog with git mv, so the rename is
+// staged, and any other changelog with a plain rename
+func (m DefaultChangelogManager) RenameChangelog(from, to string) (int, error) {
+	move := os.Rename
+	if git.IsTracked(from) {
+		move = git.MoveFile
+	}
+	return changelog.RenameFile(from, to, move)
+}
+
+func (m DefaultChangelogManager) MigrateChangelog(file string, check bool) (bool, error) {
+	return changelog.MigrateChangelog(file, check)
+}
+
+func (m DefaultChangelogManager) SetUnreleasedDate(file, date string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read changelog: %v", err)
+	}
+	updated, err := changelog.SetUnreleasedDate(string(content), date)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(updated), 0644)
 }
 
 func (m DefaultChangelogManager) GetChangelogContent() (string, error) {
@@ -53,18 +207,105 @@ func (m DefaultChangelogManager) GetChangelogContent() (string, error) {
 	return string(content), nil
 }
 
+func (m DefaultChangelogManager) OpenChangelog() (io.ReadCloser, error) {
+	file, err := os.Open(*changeLogFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open changelog: %v", err)
+	}
+	return file, nil
+}
+
 type DefaultGitManager struct{}
 
 func (m DefaultGitManager) CommitChangelog(file, version string) error {
-	return git.CommitChangelog(file, version)
+	return git.CommitChangelogWithOptions(file, version, git.CommitOptions{
+		NoVerify:    *noVerify,
+		ExtraFiles:  *extraCommitFiles,
+		AuthorName:  *authorName,
+		AuthorEmail: *authorEmail,
+		Sign:        *signCommit,
+		CoAuthors:   *coAuthors,
+		AllowEmpty:  *emptyCommit == "allow",
+	})
 }
 func (m DefaultGitManager) TagVersion(version string) error { return git.TagVersion(version) }
 func (m DefaultGitManager) GetVersion() (string, error)     { return git.GetVersion() }
 func (m DefaultGitManager) HasUncommittedChanges() (bool, error) {
-	return git.HasUncommittedChanges()
+	// Changelog backups are left untracked next to the changelog, see --backup
+	return git.HasUncommittedChangesExcept(append([]string{*changeLogFile + ".bak-*"}, *extraCommitFiles...))
 }
 func (m DefaultGitManager) PushChanges() error {
-	return git.PushChanges()
+	return git.PushChangesWithRetries(pushRetryOptions())
+}
+func (m DefaultGitManager) CreateBranch(name string) error {
+	return git.CreateBranch(name)
+}
+func (m DefaultGitManager) ListTags() ([]string, error) {
+	return git.ListTags()
+}
+func (m DefaultGitManager) TagExists(tag string) (bool, error) {
+	return git.TagExists(tag)
+}
+func (m DefaultGitManager) PushTag(remote, tag string) error {
+	return git.PushTagWithRetries(remote, tag, pushRetryOptions())
+}
+
+// pushRetryOptions retries pushes --push-retries times, reporting each retry on stderr
+func pushRetryOptions() git.RetryOptions {
+	return git.RetryOptions{
+		Retries: *pushRetries,
+		Backoff: 2 * time.Second,
+		OnRetry: func(retry int, delay time.Duration, err error) {
+			fmt.Fprintf(os.Stderr, "Push failed with a network error, retrying in %s (retry %d of %d): %s\n", delay, retry, *pushRetries, lastLine(err.Error()))
+		},
+	}
+}
+
+// lastLine returns the last non-empty line of text, such as the fatal message of git output
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+func (m DefaultGitManager) GetRemoteURL(remote string) (string, error) {
+	return git.GetRemoteURL(remote)
+}
+func (m DefaultGitManager) DefaultBranch(remote string) (string, error) {
+	return git.DefaultBranch(remote)
+}
+func (m DefaultGitManager) TagVersionWithMessage(version, message string) error {
+	return git.TagVersionWithMessage(version, message)
+}
+func (m DefaultGitManager) IsDetachedHead() (bool, error) {
+	return git.IsDetachedHead()
+}
+func (m DefaultGitManager) IsShallow() (bool, error) {
+	return git.IsShallow()
+}
+func (m DefaultGitManager) IsRepository() (bool, error) {
+	return git.IsRepository()
+}
+func (m DefaultGitManager) GetHeadCommit() (string, error) {
+	return git.GetHeadCommit()
+}
+func (m DefaultGitManager) GetLastTag() (string, error) {
+	return git.GetLastTag()
+}
+func (m DefaultGitManager) RefExists(ref string) bool {
+	return git.RefExists(ref)
+}
+func (m DefaultGitManager) GetCommitsBetween(from, to string) ([]git.Commit, error) {
+	return git.GetCommitsBetween(from, to)
+}
+func (m DefaultGitManager) DeleteTag(tag, remote string) error {
+	return git.DeleteTag(tag, remote)
+}
+
+func (m DefaultGitManager) VerifyTag(tag string) (bool, string, error) {
+	return git.VerifyTag(tag)
+}
+
+func (m DefaultGitManager) VerifyCommit(ref string) (bool, string, error) {
+	return git.VerifyCommit(ref)
 }
 
 type DefaultSemverManager struct{}
@@ -78,17 +319,72 @@ func (m DefaultSemverManager) BumpMinor(version string) (string, error) {
 func (m DefaultSemverManager) BumpPatch(version string) (string, error) {
 	return semver.BumpPatch(version)
 }
+func (m DefaultSemverManager) BumpToConstraint(version, constraint string) (string, error) {
+	return semver.BumpToConstraint(version, constraint)
+}
 
 var (
 	app                        = kingpin.New("changie", "A version and change log manager for releases. Made for projects using Git, SemVer and Keep a Changelog.")
 	initCommand                = app.Command("init", "Initiate project directory for SemVer and Keep a Changelog.")
+	initTitle                  = initCommand.Flag("title", "Title of the new changelog, e.g. a translation of Changelog.").Default(changelog.DefaultTitle).String()
 	majorCommand               = app.Command("major", "Release a major version. Bump the first version number.")
 	minorCommand               = app.Command("minor", "Release a minor version. Bump the second version number.")
 	patchCommand               = app.Command("patch", "Release a patch version. Bump the third version number.")
-	remoteRepositoryProvider   = app.Flag("rrp", "Remote repository provider, github or bitbucket.").Short('r').Default("github").Enum("github", "bitbucket")
+	bumpCommand                = app.Command("bump", "Release a major, minor or patch version, or the smallest version greater than the current one that satisfies a version range.")
+	bumpType                   = bumpCommand.Arg("type", "Part of the version to bump: major, minor or patch, or calver for a YYYY.0M.MICRO calendar version.").String()
+	bumpTo                     = bumpCommand.Flag("to", "Version range to satisfy instead of a bump type, e.g. \">=2.0.0 <3.0.0\".").String()
+	remoteRepositoryProvider   = app.Flag("rrp", "Remote repository provider, github or bitbucket. Detected from the origin remote by default.").Short('r').Default("github").IsSetByUser(&remoteRepositoryProviderSet).Enum("github", "bitbucket")
 	autoPush                   = app.Flag("auto-push", "Automatically push changes and tags after version bump").Bool()
+	pushRetries                = app.Flag("push-retries", "Retry pushing this many times after network failures, waiting 2s, 4s, 8s and so on between attempts.").Default("0").Int()
+	tagsOnly                   = app.Flag("tags-only", "Automatically push only the new tag after version bump, not the commits").Bool()
+	expectedRemoteURL          = app.Flag("expected-remote-url", "Abort an --auto-push or --tags-only release unless the origin remote is this repository, e.g. https://github.com/peiman/changie.").String()
+	releaseBranch              = app.Flag("release-branch", "Create and check out a release/<version> branch for the release commit and tag.").Bool()
+	extraCommitFiles           = app.Flag("add", "Additional file to stage in the release commit, can be repeated").Strings()
+	authorName                 = app.Flag("author-name", "Author and committer name of the release commit, requires --author-email.").String()
+	authorEmail                = app.Flag("author-email", "Author and committer email of the release commit, requires --author-name.").String()
+	tagNotes                   = app.Flag("tag-notes", "Create an annotated tag with the release notes of the version as its message.").Bool()
+	excludeSections            = app.Flag("exclude-section", "Leave a section out of release notes, e.g. Internal, can be repeated. The changelog and its stats keep the section.").Strings()
+	notesFile                  = app.Flag("notes-file", "After tagging, write the release notes of the new version to a file named by --notes-file-template, e.g. for gh release create --notes-file.").Bool()
+	notesFileTemplate          = app.Flag("notes-file-template", "Name of the --notes-file, with {{.Tag}} and {{.Version}}.").Default(changelog.DefaultNotesFile).String()
+	coAuthors                  = app.Flag("co-author", "Co-author of the release commit as \"Name <email>\", added as a Co-authored-by trailer, can be repeated").Strings()
+	notesHeader                = app.Flag("notes-header", "Template printed before release notes, with {{.Version}} and {{.Date}}. Use @file to read it from a file.").String()
+	notesFooter                = app.Flag("notes-footer", "Template printed after release notes, with {{.Version}} and {{.Date}}. Use @file to read it from a file.").String()
+	signCommit                 = app.Flag("sign-commit", "GPG-sign the release commit.").Bool()
+	noVerify                   = app.Flag("no-verify", "Skip git hooks when committing the changelog").Bool()
+	emptyCommit                = app.Flag("empty-commit", "What to do when nothing changed for the release commit: fail with an error, skip the commit and only tag, or allow an empty commit.").Default("error").Enum("error", "skip", "allow")
+	versionScheme              = app.Flag("version-scheme", "Versioning scheme of the project: semver for MAJOR.MINOR.PATCH or calver for YYYY.0M.MICRO.").Default("semver").Enum("semver", "calver")
+	tagPrefix                  = app.Flag("prefix", "Tag the new version with a v prefix, e.g. v1.2.0. The changelog header keeps the version without the prefix. Use --no-prefix for a tag without a prefix, the default.").IsSetByUser(&tagPrefixSet).Bool()
+	versionSource              = app.Flag("version-source", "Read the current version from git tags or from the latest changelog release.").Default("git").Enum("git", "changelog")
+	summaryFile                = app.Flag("summary-file", "Write the result of the release as JSON to this file, e.g. release-summary.json.").String()
+	idempotent                 = app.Flag("idempotent", "Do nothing if the latest tag and changelog release are already the result of this bump, so retried releases don't bump twice.").Bool()
+	requireSync                = app.Flag("require-sync", "Abort the release unless the latest changelog version matches the latest git tag, also with --version-source changelog.").Bool()
+	emptyPlaceholder           = app.Flag("empty-release-placeholder", "Entry to add when releasing an empty Unreleased section, e.g. \"No notable changes.\"").String()
+	emptyPlaceholderSection    = app.Flag("empty-release-section", "Section of the --empty-release-placeholder entry.").Default("Changed").Enum(changelog.ValidSections()...)
+	backupEnabled              = app.Flag("backup", "Back up the changelog to <file>.bak-<time> before commands that rewrite it, such as wrap, reorder-sections, fix-dates, set-section, validate --fix, archive and move-version. Use --no-backup to skip the backup.").Default("true").Bool()
+	backupKeep                 = app.Flag("backup-keep", "Number of changelog backups to keep, older backups are removed. 0 keeps all backups.").Default("5").Int()
+	skipChangelog              = app.Flag("no-changelog", "Only tag the new version, without updating or committing the changelog").Bool()
 	changelogCommand           = app.Command("changelog", "Change log commands.")
+	jsonOutput                 = app.Flag("json", "Print machine-readable JSON output.").Bool()
+	progressStderr             = app.Flag("progress-stderr", "Print the progress messages of version bumps to stderr, keeping only the final release message on stdout.").Bool()
+	printTag                   = app.Flag("print-tag", "Print only the created tag to stdout after a version bump, with all other messages on stderr.").Bool()
+	outputFile                 = app.Flag("output-file", "Write the output of read commands (tag list, changelog diff-versions, changelog entries-between-tags, changelog show, changelog grep, changelog entries, changelog stats, changelog graph, changelog compare-to-git, docs) to this file instead of stdout.").String()
 	changeLogFile              = app.Flag("file", "Change log file name.").Short('f').Default("CHANGELOG.md").String()
+	canonicalOrder             = app.Flag("canonical-order", "Reorder the sections of a new release into the Keep a Changelog order.").Bool()
+	strict                     = app.Flag("strict", "Abort the release if the changelog has duplicate version headers or the repository is a shallow clone, and fail changelog compare-to-git if the tags and the changelog versions differ.").Bool()
+	baseURL                    = app.Flag("base-url", "Web URL of the repository for changelog links, e.g. https://github.mycorp.com/team/project. Overrides the URL detected from the origin remote.").String()
+	versionHeader              = app.Flag("version-header", "Template of the header of released versions, with {{.Version}}, {{.Date}} and {{.Channel}}.").Default(changelog.DefaultVersionHeader).String()
+	releaseChannel             = app.Flag("channel", "Release channel available as {{.Channel}} in --version-header.").Default("stable").String()
+	linkStyle                  = app.Flag("link-style", "Link released versions to a comparison with the previous version or to their release tag.").Default("compare").Enum("compare", "tag")
+	useEmoji                   = changelogCommand.Flag("emoji", "Prefix the entry with the emoji for its section.").Bool()
+	sectionEmoji               = changelogCommand.Flag("section-emoji", "Override the emoji for a section, e.g. Fixed=🚑️.").StringMap()
+	entryTemplates             = changelogCommand.Flag("entry-template", "Template of new entries in a section, e.g. Fixed=\"{{.Text}} (fixes #{{.Issue}})\", with {{.Text}}, {{.Issue}} and {{.Section}}, can be repeated.").StringMap()
+	entryIssue                 = changelogCommand.Flag("issue", "Issue of the entry, available as {{.Issue}} in --entry-template.").String()
+	replaceEntry               = changelogCommand.Flag("replace", "Replace the existing entry that matches the new one, ignoring emoji and metadata, instead of skipping it as a duplicate.").Bool()
+	replaceMatch               = changelogCommand.Flag("match", "Text of the entry to replace with --replace, e.g. its old wording. Defaults to the new entry.").String()
+	entryMeta                  = changelogCommand.Flag("meta", "Annotate the entry with metadata, e.g. severity=high, can be repeated.").StringMap()
+	commitsSince               = changelogCommand.Flag("since", "List commits after this tag or ref instead of the latest tag.").String()
+	commitsUntil               = changelogCommand.Flag("until", "List commits up to this tag or ref.").Default("HEAD").String()
+	wrapWidth                  = changelogCommand.Flag("wrap-width", "Wrap the entry text at the given column, 0 disables wrapping.").Default("0").Int()
 	changelogAddCommand        = changelogCommand.Command("added", "Add an added section to changelog.")
 	changelogAddContent        = changelogAddCommand.Arg("content", "Content to add to the changelog").Required().String()
 	changelogChangedCommand    = changelogCommand.Command("changed", "Add a changed section to changelog.")
@@ -101,24 +397,140 @@ var (
 	changelogFixedContent      = changelogFixedCommand.Arg("content", "Content to add to the changelog").Required().String()
 	changelogSecurityCommand   = changelogCommand.Command("security", "Add a security section to changelog.")
 	changelogSecurityContent   = changelogSecurityCommand.Arg("content", "Content to add to the changelog").Required().String()
+	changelogWrapCommand       = changelogCommand.Command("wrap", "Rewrap all changelog entries at the configured width.")
+	changelogWrapWidth         = changelogWrapCommand.Flag("width", "Wrap at this column instead of --wrap-width, 0 unwraps entries.").IsSetByUser(&changelogWrapWidthSet).Int()
+	changelogWrapCheck         = changelogWrapCommand.Flag("check", "Only check whether entries are wrapped, without changing the file.").Bool()
+	changelogReorderCommand    = changelogCommand.Command("reorder-sections", "Sort the Unreleased sections into the Keep a Changelog order.")
+	changelogReorderAll        = changelogReorderCommand.Flag("all", "Sort the sections of all versions, not only Unreleased.").Bool()
+	changelogReorderCheck      = changelogReorderCommand.Flag("check", "Only check whether the sections are in order, without changing the file.").Bool()
+	changelogFixDatesCommand   = changelogCommand.Command("fix-dates", "Rewrite the release dates of the version headers in one format, e.g. 2024-1-1 as 2024-01-01.")
+	changelogFixDatesFormat    = changelogFixDatesCommand.Flag("format", "Go time layout of the release dates.").Default(changelog.DefaultDateFormat).String()
+	changelogFixDatesCheck     = changelogFixDatesCommand.Flag("check", "Only check whether the dates are in the format, without changing the file.").Bool()
+	changelogSetCommand        = changelogCommand.Command("set-section", "Replace the entries of a section of the Unreleased section, e.g. when regenerating it.")
+	changelogSetSection        = changelogSetCommand.Arg("section", "Section to replace, e.g. Added or added").Required().String()
+	changelogSetEntries        = changelogSetCommand.Arg("entries", "Entries of the section, in order").Strings()
+	changelogSetRemoveEmpty    = changelogSetCommand.Flag("remove-empty", "Remove the section when no entries are given.").Bool()
+	changelogAssembleCommand   = changelogCommand.Command("assemble", "Add changelog fragment files (<id>.<section>.md) to the Unreleased section.")
+	changelogAssembleDir       = changelogAssembleCommand.Flag("dir", "Directory containing the changelog fragments.").Default("changes").String()
+	changelogAssembleDelete    = changelogAssembleCommand.Flag("delete", "Delete the fragment files after adding them.").Bool()
+	changelogDiffCommand       = changelogCommand.Command("diff-versions", "Print the release notes of all versions after <from> up to and including <to>.")
+	changelogDiffFrom          = changelogDiffCommand.Arg("from", "Oldest version of the range").Required().String()
+	changelogDiffTo            = changelogDiffCommand.Arg("to", "Newest version of the range").Required().String()
+	changelogDiffIncludeFrom   = changelogDiffCommand.Flag("include-from", "Also include the <from> version.").Bool()
+	changelogShowCommand       = changelogCommand.Command("show", "Print the release notes of a version, with the notes header and footer.")
+	changelogShowVersion       = changelogShowCommand.Arg("version", "Version to show, the latest release by default").String()
+	changelogShowUnreleased    = changelogShowCommand.Flag("unreleased", "Show the Unreleased section, e.g. as draft release notes.").Bool()
+	changelogFirstCommand      = changelogCommand.Command("first-release", "Release the first version of a project that has no releases yet.")
+	changelogFirstVersion      = changelogFirstCommand.Arg("version", "Version of the first release, instead of --initial-version.").String()
+	initialVersion             = changelogFirstCommand.Flag("initial-version", "Version of the first release. With --version-scheme calver, the first version of this month by default.").Default("0.1.0").IsSetByUser(&initialVersionSet).String()
+	changelogDateCommand       = changelogCommand.Command("set-unreleased-date", "Set the scheduled release date on the Unreleased section.")
+	changelogDate              = changelogDateCommand.Arg("date", "Scheduled release date (YYYY-MM-DD)").String()
+	changelogDateClear         = changelogDateCommand.Flag("clear", "Remove the scheduled release date.").Bool()
+	changelogSectionsCommand   = changelogCommand.Command("sections", "List the accepted changelog sections with their emoji and aliases.")
+	changelogCommitsCommand    = changelogCommand.Command("commits", "List the commits since the latest tag, for writing changelog entries.")
+	changelogCountCommand      = changelogCommand.Command("count-since", "Count the commits since the latest tag.")
+	changelogCountByType       = changelogCountCommand.Flag("by-type", "Also count the commits by Conventional Commits type.").Bool()
+	changelogCountMerges       = changelogCountCommand.Flag("include-merges", "Also count merge commits.").Bool()
+	changelogValidateCommand   = changelogCommand.Command("validate", "Check the changelog for structural problems, such as duplicate versions.")
+	changelogValidateStrict    = changelogValidateCommand.Flag("keepachangelog-strict", "Also check the Keep a Changelog 1.1.0 rules: introduction, links, dates, sections and their order.").Bool()
+	changelogValidateLinks     = changelogValidateCommand.Flag("require-links", "Also check that every version, including Unreleased, has a link.").Bool()
+	changelogValidateFix       = changelogValidateCommand.Flag("fix", "Add the missing version links, built like the links of a release, before validating. Implies --require-links.").Bool()
+	changelogMaxUnreleased     = changelogValidateCommand.Flag("max-unreleased", "Fail when Unreleased has more than this many entries across all sections, a sign that a release is overdue.").IsSetByUser(&maxUnreleasedSet).Int()
+	githubAnnotations          = app.Flag("github", "Print the issues of changelog validate and lint as GitHub Actions annotations. Enabled automatically when GITHUB_ACTIONS is true, use --no-github to disable.").IsSetByUser(&githubAnnotationsSet).Bool()
+	changelogLintCommand       = changelogCommand.Command("lint", "Check the changelog entries for style issues.")
+	changelogLintFix           = changelogLintCommand.Flag("fix", "Apply the safe corrections: capitalization, trailing punctuation, double spaces and blank lines.").Bool()
+	changelogLintCheck         = changelogLintCommand.Flag("check", "With --fix, only report the corrections without changing the file.").Bool()
+	changelogLintPunctuation   = changelogLintCommand.Flag("punctuation", "Trailing punctuation of entries: keep as is, none to strip a trailing period, or period to require one.").Default("keep").Enum("keep", "none", "period")
+	changelogFixLinksCommand   = changelogCommand.Command("fix-links", "Remove links to versions that have no section in the changelog.")
+	changelogVerifyCommand     = changelogCommand.Command("verify-links", "Check that the links at the end of the changelog are valid URLs.")
+	changelogVerifyOnline      = changelogVerifyCommand.Flag("online", "Also request each link to check that it resolves.").Bool()
+	changelogVerifyTimeout     = changelogVerifyCommand.Flag("timeout", "Timeout of each request with --online.").Default("10s").Duration()
+	changelogMoveCommand       = changelogCommand.Command("move-version", "Move a version block before or after another version.")
+	changelogMoveVersion       = changelogMoveCommand.Arg("version", "Version to move.").Required().String()
+	changelogMoveBefore        = changelogMoveCommand.Flag("before", "Move the version right before this version.").String()
+	changelogMoveAfter         = changelogMoveCommand.Flag("after", "Move the version right after this version.").String()
+	changelogArchiveCommand    = changelogCommand.Command("archive", "Move older releases to archive files to keep the changelog short.")
+	changelogArchiveBy         = changelogArchiveCommand.Flag("by", "Split the archives by year.").Default("year").Enum("year")
+	changelogArchiveDryRun     = changelogArchiveCommand.Flag("dry-run", "Only print the versions that would be archived, without changing any files.").Bool()
+	changelogGrepCommand       = changelogCommand.Command("grep", "Search the entries of all versions for a regular expression.")
+	changelogGrepPattern       = changelogGrepCommand.Arg("pattern", "Regular expression to search for, in Go syntax.").Required().String()
+	changelogGrepUnreleased    = changelogGrepCommand.Flag("unreleased-only", "Only search the Unreleased section.").Bool()
+	changelogGrepIgnoreCase    = changelogGrepCommand.Flag("ignore-case", "Match the pattern case-insensitively.").Short('i').Bool()
+	changelogBetweenCommand    = changelogCommand.Command("entries-between-tags", "Print the entries of all versions after <from> up to and including <to> as one list, grouped by section.")
+	changelogBetweenFrom       = changelogBetweenCommand.Arg("from", "Older tag or version of the range").Required().String()
+	changelogBetweenTo         = changelogBetweenCommand.Arg("to", "Newer tag or version of the range").Required().String()
+	changelogBetweenInclFrom   = changelogBetweenCommand.Flag("include-from", "Also include the entries of <from>.").Bool()
+	changelogBetweenExclTo     = changelogBetweenCommand.Flag("exclude-to", "Leave out the entries of <to>.").Bool()
+	changelogEntriesCommand    = changelogCommand.Command("entries", "List all entries with their version, date and section, one per line.")
+	changelogEntriesNoStream   = changelogEntriesCommand.Flag("no-stream", "Read the whole changelog before printing, and print --json as a single array instead of one object per line.").Bool()
+	changelogStatsCommand      = changelogCommand.Command("stats", "Count the entries per section of the Unreleased section.")
+	changelogStatsAll          = changelogStatsCommand.Flag("all", "Count the entries of every version, with totals across all versions.").Bool()
+	changelogBackupCommand     = changelogCommand.Command("backup", "Back up the changelog to a timestamped file.")
+	changelogGraphCommand      = changelogCommand.Command("graph", "Print a histogram of releases per month or quarter.")
+	changelogGraphPeriod       = changelogGraphCommand.Flag("period", "Group releases by month or quarter.").Default("month").Enum("month", "quarter")
+	changelogRenameCommand     = changelogCommand.Command("rename-file", "Rename the changelog file, with git mv if it is tracked, and update the links to itself.")
+	changelogRenameTarget      = changelogRenameCommand.Arg("new-name", "New name of the changelog file, e.g. HISTORY.md").Required().String()
+	changelogNeedsCommand      = changelogCommand.Command("needs-release", "Exit with 0 if the Unreleased section has entries to release, and with 1 otherwise.")
+	changelogNeedsMinEntries   = changelogNeedsCommand.Flag("min-entries", "Number of Unreleased entries needed for a release.").Default("1").Int()
+	changelogCompareCommand    = changelogCommand.Command("compare-to-git", "List the version tags and the changelog versions side by side, with tags missing a changelog section and versions missing a tag.")
+	migrateCommand             = app.Command("migrate", "Update the changelog header to the current Keep a Changelog template.")
+	migrateCheck               = migrateCommand.Flag("check", "Only check whether the header is up to date, without changing the file.").Bool()
+	versionCommand             = app.Command("version", "Print the version of changie.")
+	versionBuildInfo           = versionCommand.Flag("build-info", "Also print the commit, build date, Go version and platform of the changie binary.").Bool()
+	docsCommand                = app.Command("docs", "Generate the command reference documentation.")
+	docsFormat                 = docsCommand.Flag("format", "Documentation format, markdown or man.").Default("markdown").Enum("markdown", "man")
+	tagCommand                 = app.Command("tag", "Version tag commands.")
+	tagListCommand             = tagCommand.Command("list", "List version tags sorted by semantic version, newest first.")
+	tagListLimit               = tagListCommand.Flag("limit", "Maximum number of tags to list.").Int()
+	tagListAll                 = tagListCommand.Flag("all", "Also list tags that are not valid versions.").Bool()
+	tagDeleteCommand           = tagCommand.Command("delete", "Delete a version tag locally and optionally from the remote.")
+	tagDeleteVersion           = tagDeleteCommand.Arg("version", "Tag to delete").Required().String()
+	tagDeleteRemote            = tagDeleteCommand.Flag("remote", "Also delete the tag from the origin remote.").Bool()
+	tagDeleteYes               = tagDeleteCommand.Flag("yes", "Delete without asking for confirmation.").Short('y').Bool()
+	tagVerifyCommand           = tagCommand.Command("verify", "Verify the GPG or SSH signature of a version tag.")
+	tagVerifyVersion           = tagVerifyCommand.Arg("version", "Tag to verify").Required().String()
+	tagVerifyCommit            = tagVerifyCommand.Flag("commit", "Also verify the signature of the release commit the tag points to.").Bool()
+)
+
+const defaultRemote = "origin"
+
+var changelogWrapWidthSet bool
+var remoteRepositoryProviderSet bool
+var initialVersionSet bool
+var maxUnreleasedSet bool
+var tagPrefixSet bool
+var githubAnnotationsSet bool
+
+// githubActions is true when changie runs in a GitHub Actions workflow
+var githubActions = os.Getenv("GITHUB_ACTIONS") == "true"
+
+// Build information of the changie binary, set when releasing with
+// -ldflags "-X main.buildVersion=1.2.3 -X main.buildCommit=abc1234 -X main.buildDate=2024-01-01"
+var (
+	buildVersion string
+	buildCommit  string
+	buildDate    string
 )
 
 var isGitInstalled = git.IsInstalled
 var isTestMode bool
 var exitFunction = os.Exit
+var stdin io.Reader = os.Stdin
 
 func handleError(err error) {
 	if err != nil {
-		fmt.Printf("Debug: handleError called with error: %v\n", err)
+		logger.Debug("handleError called", zap.Error(err))
 		fmt.Fprintln(os.Stderr, err)
 		exitFunction(1)
 	}
 }
 
-func checkVersionMismatch(gitManager GitManager, changelogManager ChangelogManager, printWarning bool) error {
-	gitVersion, err := gitManager.GetVersion()
+// checkVersionMismatch compares the latest changelog version with the latest
+// tag reachable from HEAD, so commits after the tag don't cause a mismatch
+func checkVersionMismatch(gitManager GitManager, changelogManager ChangelogManager) error {
+	tag, err := gitManager.GetLastTag()
 	if err != nil {
-		return fmt.Errorf("Error getting project version: %v", err)
+		return fmt.Errorf("Error getting latest tag: %v", err)
 	}
 
 	changelogContent, err := changelogManager.GetChangelogContent()
@@ -131,18 +543,249 @@ func checkVersionMismatch(gitManager GitManager, changelogManager ChangelogManag
 		return fmt.Errorf("Error getting changelog version: %v", err)
 	}
 
-	if gitVersion != changelogVersion {
-		err := fmt.Errorf("Version mismatch: Git tag version %s does not match changelog version %s", gitVersion, changelogVersion)
-		if printWarning {
-			fmt.Println("Warning:", err)
-		}
-		return err
+	if tag == "" {
+		return fmt.Errorf("Version mismatch: No git tag found for changelog version %s", changelogVersion)
+	}
+	if !versionsMatch(tag, changelogVersion) {
+		return fmt.Errorf("Version mismatch: Git tag version %s does not match changelog version %s", tag, changelogVersion)
 	}
 
 	return nil
 }
 
+// validateAuthor checks that the release commit author is either fully given or not at all
+func validateAuthor(name, email string) error {
+	if name == "" && email == "" {
+		return nil
+	}
+	if name == "" || email == "" {
+		return fmt.Errorf("Error: --author-name and --author-email must be used together.")
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return fmt.Errorf("Error: Invalid author email: %s", email)
+	}
+	return nil
+}
+
+// validateBaseURL checks that the changelog base URL is an absolute web URL
+func validateBaseURL(url string) error {
+	if url == "" {
+		return nil
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return fmt.Errorf("Error: Invalid base URL %s, it must start with https:// or http://.", url)
+	}
+	return nil
+}
+
+// validateCoAuthors checks that every co-author is given as "Name <email>"
+func validateCoAuthors(coAuthors []string) error {
+	for _, coAuthor := range coAuthors {
+		address, err := mail.ParseAddress(coAuthor)
+		if err != nil || address.Name == "" || !strings.HasSuffix(coAuthor, "<"+address.Address+">") {
+			return fmt.Errorf("Error: Invalid co-author %s, expected \"Name <email>\".", coAuthor)
+		}
+	}
+	return nil
+}
+
+// alreadyReleased reports whether the latest tag is the current version, was
+// produced by this bump from the previous tag, and has a changelog section.
+// This is the state a retried bump finds after the first run succeeded.
+func alreadyReleased(currentVersion string, bumpFunc func(string) (string, error), changelogManager ChangelogManager, gitManager GitManager) (bool, error) {
+	tags, err := gitManager.ListTags()
+	if err != nil {
+		return false, fmt.Errorf("Error listing tags: %v", err)
+	}
+
+	var versions []string
+	for _, tag := range tags {
+		if _, err := semver.ParseVersion(tag); err == nil {
+			versions = append(versions, tag)
+		}
+	}
+	if len(versions) < 2 {
+		return false, nil
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		result, _ := semver.Compare(versions[i], versions[j])
+		return result > 0
+	})
+
+	latest, previous := versions[0], versions[1]
+	expected, err := bumpFunc(previous)
+	if err != nil || !versionsMatch(latest, expected) || !versionsMatch(latest, currentVersion) {
+		return false, nil
+	}
+
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return false, fmt.Errorf("Error reading changelog: %v", err)
+	}
+	return changelog.Parse(content).Version(strings.TrimPrefix(latest, "v")) != nil, nil
+}
+
+// releaseType names the kind of release between two versions
+func releaseType(from, to string) string {
+	v1, err1 := semver.ParseVersion(from)
+	v2, err2 := semver.ParseVersion(to)
+	switch {
+	case err1 != nil || err2 != nil:
+		return "new"
+	case v1[0] != v2[0]:
+		return "major"
+	case v1[1] != v2[1]:
+		return "minor"
+	default:
+		return "patch"
+	}
+}
+
+// releaseTag returns the tag of a new version. With --prefix or --no-prefix
+// it has or doesn't have a "v" prefix, otherwise it is the version itself.
+// The changelog keeps the version without the prefix either way.
+func releaseTag(version string) string {
+	if !tagPrefixSet {
+		return version
+	}
+	version = strings.TrimPrefix(version, "v")
+	if *tagPrefix {
+		return "v" + version
+	}
+	return version
+}
+
+// versionsMatch compares two versions semantically, so a "v" prefix doesn't
+// cause a mismatch, and falls back to comparing the strings
+func versionsMatch(v1, v2 string) bool {
+	if result, err := semver.Compare(v1, v2); err == nil {
+		return result == 0
+	}
+	return v1 == v2
+}
+
+// bumpOutput is the JSON output of the version bump commands
+type bumpOutput struct {
+	Success         bool           `json:"success"`
+	PreviousVersion string         `json:"previous_version"`
+	Version         string         `json:"version"`
+	Tag             string         `json:"tag"` // Version tag, with a v prefix if --prefix was used
+	BumpType        string         `json:"bump_type"`
+	Date            string         `json:"date,omitempty"`
+	Commit          string         `json:"commit,omitempty"` // Release commit the version tag points to
+	AlreadyReleased bool           `json:"already_released,omitempty"`
+	Pushed          bool           `json:"pushed"`
+	NotesFile       string         `json:"notes_file,omitempty"` // Release notes file written with --notes-file
+	Entries         int            `json:"entries"`
+	Sections        []sectionCount `json:"sections"`        // Entries moved from Unreleased into the release
+	Warnings        []string       `json:"warnings"`        // Non-fatal issues, also printed at the end of the human output
+	WarningDetails  []bumpWarning  `json:"warning_details"` // The warnings with a code, in the same order
+}
+
+// bumpWarning is a non-fatal issue of a release with a stable code, so
+// automation can decide which issues are acceptable
+type bumpWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"` // Underlying error, if any
+}
+
+// shallowCloneMessage explains why a release from a shallow clone may start
+// from the wrong version
+const shallowCloneMessage = "The repository is a shallow clone, so tags may be missing and the current version may be wrong. Run git fetch --tags --unshallow to fetch all tags."
+
+// Codes of the non-fatal issues of a release
+const (
+	warningChangelogSkipped    = "changelog_skipped"
+	warningNoEntries           = "no_entries"
+	warningRepoInfoUnavailable = "repo_info_unavailable"
+	warningTagNotesUnavailable = "tag_notes_unavailable"
+	warningCommitUnavailable   = "commit_unavailable"
+	warningCommitSkipped       = "commit_skipped"
+	warningShallowClone        = "shallow_clone"
+	warningSummaryNotWritten   = "summary_not_written"
+	warningNotesFileNotWritten = "notes_file_not_written"
+)
+
+// addWarning records a non-fatal issue of the release. The underlying error may be nil.
+func (o *bumpOutput) addWarning(code, message string, err error) {
+	warning := bumpWarning{Code: code, Message: message}
+	if err != nil {
+		warning.Error = err.Error()
+	}
+	o.Warnings = append(o.Warnings, message)
+	o.WarningDetails = append(o.WarningDetails, warning)
+}
+
+// sectionCount is the number of entries of a changelog section in a release
+type sectionCount struct {
+	Section string `json:"section"`
+	Entries int    `json:"entries"`
+}
+
 func handleVersionBump(bumpType string, changelogManager ChangelogManager, gitManager GitManager, semverManager SemverManager) error {
+	// With --json, progress messages go to stderr so stdout only has the result.
+	// With --print-tag, stdout only has the tag.
+	// With --progress-stderr, stdout only has the final release message.
+	if *jsonOutput && *printTag {
+		return fmt.Errorf("Error: --print-tag can't be combined with --json.")
+	}
+	if err := checkVersionScheme(bumpType); err != nil {
+		return err
+	}
+	out := io.Writer(os.Stdout)
+	resultOut := io.Writer(os.Stdout)
+	if *jsonOutput || *printTag {
+		out, resultOut = os.Stderr, os.Stderr
+	} else if *progressStderr {
+		out = os.Stderr
+	}
+
+	if err := validateAuthor(*authorName, *authorEmail); err != nil {
+		return err
+	}
+
+	if err := validateCoAuthors(*coAuthors); err != nil {
+		return err
+	}
+
+	if err := validateBaseURL(*baseURL); err != nil {
+		return err
+	}
+
+	notesTemplate, err := loadNotesTemplate()
+	if err != nil {
+		return err
+	}
+	if _, err := changelog.ParseHeaderTemplate(*versionHeader); err != nil {
+		return fmt.Errorf("Error: Invalid version header: %v", err)
+	}
+	var notesFileName *changelog.NotesFileTemplate
+	if *notesFile {
+		if notesFileName, err = changelog.ParseNotesFileTemplate(*notesFileTemplate); err != nil {
+			return fmt.Errorf("Error: Invalid --notes-file-template: %v", err)
+		}
+	}
+
+	if *skipChangelog && len(*extraCommitFiles) > 0 {
+		return fmt.Errorf("Error: --add can't be used with --no-changelog, no release commit is created to add the files to.")
+	}
+	for _, file := range *extraCommitFiles {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("Error: Additional commit file %s does not exist.", file)
+		}
+	}
+
+	if !*releaseBranch {
+		detached, err := gitManager.IsDetachedHead()
+		if err != nil {
+			return fmt.Errorf("Error checking current branch: %v", err)
+		}
+		if detached {
+			return fmt.Errorf("Error: You are in detached HEAD state. Please checkout a branch before releasing.")
+		}
+	}
+
 	hasUncommittedChanges, err := gitManager.HasUncommittedChanges()
 	if err != nil {
 		return fmt.Errorf("Error checking for uncommitted changes: %v", err)
@@ -151,15 +794,68 @@ func handleVersionBump(bumpType string, changelogManager ChangelogManager, gitMa
 		return fmt.Errorf("Error: Uncommitted changes found. Please commit or stash your changes before bumping the version.")
 	}
 
-	if err := checkVersionMismatch(gitManager, changelogManager, !isTestMode); err != nil {
-		return err
+	if *expectedRemoteURL != "" && (*autoPush || *tagsOnly) {
+		if err := checkRemoteURL(gitManager); err != nil {
+			return err
+		}
 	}
 
-	gitVersion, err := gitManager.GetVersion()
-	if err != nil {
-		return fmt.Errorf("Error getting project version: %v", err)
+	// In a shallow clone, the latest tag may be missing from the history
+	shallow := false
+	if bumpType == "first" || *versionSource == "git" {
+		shallow, err = gitManager.IsShallow()
+		if err != nil {
+			return fmt.Errorf("Error checking for a shallow clone: %v", err)
+		}
+		if shallow && *strict {
+			return fmt.Errorf("Error: %s", shallowCloneMessage)
+		}
+	}
+
+	var currentVersion string
+	if bumpType == "first" {
+		if err := checkFirstRelease(changelogManager, gitManager); err != nil {
+			return err
+		}
+	} else if *versionSource == "changelog" {
+		if *skipChangelog {
+			return fmt.Errorf("Error: --no-changelog can't be used when the version is read from the changelog.")
+		}
+		changelogContent, err := changelogManager.GetChangelogContent()
+		if err != nil {
+			return fmt.Errorf("Error reading changelog: %v", err)
+		}
+		currentVersion, err = changelog.GetLatestChangelogVersion(changelogContent)
+		if err != nil {
+			return fmt.Errorf("Error getting changelog version: %v", err)
+		}
+		if *versionScheme == "calver" {
+			if _, err := calver.ParseVersion(currentVersion); err != nil {
+				return fmt.Errorf("Error: Latest changelog version %s is not a valid calendar version: %v", currentVersion, err)
+			}
+		} else if _, err := semver.ParseVersion(currentVersion); err != nil {
+			return fmt.Errorf("Error: Latest changelog version %s is not a valid semantic version: %v", currentVersion, err)
+		}
+		fmt.Fprintf(out, "Current version from changelog: %s\n", currentVersion)
+
+		if *requireSync {
+			if err := checkVersionMismatch(gitManager, changelogManager); err != nil {
+				return err
+			}
+		}
+	} else {
+		if !*skipChangelog {
+			if err := checkVersionMismatch(gitManager, changelogManager); err != nil {
+				return err
+			}
+		}
+
+		currentVersion, err = gitManager.GetVersion()
+		if err != nil {
+			return fmt.Errorf("Error getting project version: %v", err)
+		}
+		fmt.Fprintf(out, "Current version from git tags: %s\n", currentVersion)
 	}
-	fmt.Printf("Current version from git tags: %s\n", gitVersion)
 
 	var bumpFunc func(string) (string, error)
 	switch bumpType {
@@ -169,87 +865,1960 @@ func handleVersionBump(bumpType string, changelogManager ChangelogManager, gitMa
 		bumpFunc = semverManager.BumpMinor
 	case "patch":
 		bumpFunc = semverManager.BumpPatch
+	case "constraint":
+		bumpFunc = func(version string) (string, error) {
+			return semverManager.BumpToConstraint(version, *bumpTo)
+		}
+	case "first":
+		bumpFunc = func(string) (string, error) {
+			return firstReleaseVersion()
+		}
+	case "calver":
+		bumpFunc = func(version string) (string, error) {
+			return calver.Next(version, time.Now())
+		}
 	default:
 		return fmt.Errorf("Invalid bump type: %s", bumpType)
 	}
 
-	newVersion, err := bumpFunc(gitVersion)
+	if *idempotent {
+		released, err := alreadyReleased(currentVersion, bumpFunc, changelogManager, gitManager)
+		if err != nil {
+			return err
+		}
+		if released {
+			fmt.Fprintf(resultOut, "Already at target version %s, nothing to do.\n", currentVersion)
+			if *printTag {
+				fmt.Println(currentVersion)
+			}
+			if *jsonOutput {
+				return printJSON(bumpOutput{
+					Success:         true,
+					PreviousVersion: currentVersion,
+					Version:         currentVersion,
+					Tag:             currentVersion,
+					BumpType:        bumpType,
+					AlreadyReleased: true,
+					Sections:        []sectionCount{},
+					Warnings:        []string{},
+					WarningDetails:  []bumpWarning{},
+				})
+			}
+			return nil
+		}
+	}
+
+	newVersion, err := bumpFunc(currentVersion)
 	if err != nil {
 		return fmt.Errorf("Error bumping version: %v", err)
 	}
+	if tagPrefixSet {
+		newVersion = strings.TrimPrefix(newVersion, "v")
+	}
+	tag := releaseTag(newVersion)
 
-	fmt.Printf("New version: %s\n", newVersion)
+	fmt.Fprintf(out, "New version: %s\n", newVersion)
+	result := bumpOutput{Success: true, PreviousVersion: currentVersion, Version: newVersion, Tag: tag, BumpType: bumpType, Date: time.Now().Format("2006-01-02"), Sections: []sectionCount{}, Warnings: []string{}, WarningDetails: []bumpWarning{}}
 
-	changelogFilePath := filepath.Join(".", *changeLogFile)
-	fmt.Printf("Updating changelog file: %s\n", changelogFilePath)
+	if shallow {
+		result.addWarning(warningShallowClone, shallowCloneMessage, nil)
+	}
 
-	if err := changelogManager.UpdateChangelog(changelogFilePath, newVersion, *remoteRepositoryProvider); err != nil {
-		return fmt.Errorf("Error updating changelog: %v", err)
+	if *releaseBranch {
+		branch := "release/" + newVersion
+		fmt.Fprintf(out, "Creating release branch: %s\n", branch)
+		if err := gitManager.CreateBranch(branch); err != nil {
+			return fmt.Errorf("Error creating release branch: %v", err)
+		}
 	}
 
-	if err := gitManager.CommitChangelog(changelogFilePath, newVersion); err != nil {
-		return fmt.Errorf("Error committing changelog: %v", err)
+	if *skipChangelog {
+		result.addWarning(warningChangelogSkipped, "Skipping changelog update. No release commit will be created, only the tag.", nil)
+	} else {
+		changelogContent, err := changelogManager.GetChangelogContent()
+		if err != nil {
+			return fmt.Errorf("Error reading changelog: %v", err)
+		}
+		if unreleased := changelog.Parse(changelogContent).Version("Unreleased"); unreleased != nil {
+			for _, section := range unreleased.Sections {
+				if len(section.Entries) > 0 {
+					result.Sections = append(result.Sections, sectionCount{Section: section.Name, Entries: len(section.Entries)})
+				}
+			}
+			result.Entries = unreleased.EntryCount()
+		}
+		if result.Entries == 0 && *emptyPlaceholder != "" {
+			fmt.Fprintf(out, "Adding placeholder to the empty release: %s\n", *emptyPlaceholder)
+			if _, err := changelogManager.AddChangelogSection(filepath.Join(".", *changeLogFile), *emptyPlaceholderSection, *emptyPlaceholder); err != nil {
+				return fmt.Errorf("Error adding release placeholder: %v", err)
+			}
+			result.Entries = 1
+			result.Sections = append(result.Sections, sectionCount{Section: *emptyPlaceholderSection, Entries: 1})
+		}
+		if result.Entries == 0 {
+			result.addWarning(warningNoEntries, "The release has no changelog entries.", nil)
+		} else {
+			var summary []string
+			for _, count := range result.Sections {
+				summary = append(summary, fmt.Sprintf("%d %s", count.Entries, count.Section))
+			}
+			fmt.Fprintf(out, "Release contents: %s\n", strings.Join(summary, ", "))
+		}
+
+		if *baseURL != "" {
+			fmt.Fprintf(out, "Changelog links use the base URL: %s\n", *baseURL)
+		} else if url, err := gitManager.GetRemoteURL(defaultRemote); err != nil {
+			result.addWarning(warningRepoInfoUnavailable, fmt.Sprintf("Could not get the URL of the %s remote, changelog links use the default repository URL.", defaultRemote), err)
+		} else if _, err := git.ParseRepositoryURL(url); err != nil {
+			result.addWarning(warningRepoInfoUnavailable, fmt.Sprintf("Could not detect the repository from %s, changelog links use the default repository URL.", url), err)
+		}
+
+		changelogFilePath := filepath.Join(".", *changeLogFile)
+		fmt.Fprintf(out, "Updating changelog file: %s\n", changelogFilePath)
+
+		opts := linkOptions(gitManager, *remoteRepositoryProvider)
+		opts.CanonicalOrder = *canonicalOrder
+		opts.Strict = *strict
+		opts.VersionHeader = *versionHeader
+		opts.Channel = *releaseChannel
+		// Links also use the tag the new version is about to get
+		opts.Tags[strings.TrimPrefix(newVersion, "v")] = tag
+		if err := changelogManager.UpdateChangelog(changelogFilePath, newVersion, opts); err != nil {
+			return fmt.Errorf("Error updating changelog: %v", err)
+		}
+
+		if len(*extraCommitFiles) > 0 {
+			fmt.Fprintf(out, "Staging additional files: %s\n", strings.Join(*extraCommitFiles, ", "))
+		}
+
+		if err := gitManager.CommitChangelog(changelogFilePath, newVersion); err != nil {
+			if *emptyCommit != "skip" || !errors.Is(err, git.ErrNothingToCommit) {
+				return fmt.Errorf("Error committing changelog: %v", err)
+			}
+			result.addWarning(warningCommitSkipped, "Nothing changed for the release commit, so only the tag was created.", err)
+		}
 	}
 
-	fmt.Printf("Tagging version: %s\n", newVersion)
-	if err := gitManager.TagVersion(newVersion); err != nil {
+	fmt.Fprintf(out, "Tagging version: %s\n", tag)
+	if *tagNotes {
+		message, err := tagMessage(newVersion, changelogManager, notesTemplate)
+		if err != nil {
+			result.addWarning(warningTagNotesUnavailable, fmt.Sprintf("Could not read the release notes of %s, the tag only has the version as its message: %v", newVersion, err), err)
+		}
+		if err := gitManager.TagVersionWithMessage(tag, message); err != nil {
+			return fmt.Errorf("Error tagging version: %v", err)
+		}
+	} else if err := gitManager.TagVersion(tag); err != nil {
 		return fmt.Errorf("Error tagging version: %v", err)
 	}
 
-	fmt.Printf("%s release %s done.\n", bumpType, newVersion)
+	// The version is tagged at this point, so a failed notes file is only a warning
+	if notesFileName != nil {
+		if file, err := writeNotesFile(notesFileName, tag, newVersion, changelogManager, notesTemplate); err != nil {
+			result.addWarning(warningNotesFileNotWritten, fmt.Sprintf("Could not write the release notes of %s to a file: %v", newVersion, err), err)
+		} else {
+			result.NotesFile = file
+			fmt.Fprintf(out, "Wrote release notes to %s\n", file)
+		}
+	}
+
+	if bumpType == "constraint" {
+		bumpType = releaseType(currentVersion, newVersion)
+		result.BumpType = bumpType
+	}
+	fmt.Fprintf(resultOut, "%s release %s done.\n", bumpType, newVersion)
+	if bumpType == "first" {
+		if *versionScheme == "calver" {
+			fmt.Fprintln(out, "Release the next versions with changie bump calver.")
+		} else {
+			fmt.Fprintln(out, "Release the next versions with changie major, minor or patch.")
+		}
+	}
+
+	if commit, err := gitManager.GetHeadCommit(); err != nil {
+		result.addWarning(warningCommitUnavailable, fmt.Sprintf("Could not get the release commit: %v", err), err)
+	} else {
+		result.Commit = commit
+	}
 
-	if *autoPush {
-		fmt.Println("Pushing changes and tags...")
+	if *tagsOnly {
+		fmt.Fprintf(out, "Pushing tag %s...\n", tag)
+		if err := gitManager.PushTag(defaultRemote, tag); err != nil {
+			return fmt.Errorf("Error pushing tag: %v", err)
+		}
+		fmt.Fprintf(out, "Automatically pushed tag %s to %s. Commits were not pushed.\n", tag, defaultRemote)
+		result.Pushed = true
+	} else if *autoPush {
+		fmt.Fprintln(out, "Pushing changes and tags...")
 		if err := gitManager.PushChanges(); err != nil {
 			return fmt.Errorf("Error pushing changes: %v", err)
 		}
-		fmt.Println("Automatically pushed changes and tags to remote repository.")
+		fmt.Fprintln(out, "Automatically pushed changes and tags to remote repository.")
+		result.Pushed = true
 	} else {
-		fmt.Println("Don't forget to git push and git push --tags.")
+		fmt.Fprintln(out, "Don't forget to git push and git push --tags.")
 	}
 
+	// The release is done at this point, so a failed summary is only a warning
+	if *summaryFile != "" {
+		if err := writeSummaryFile(*summaryFile, result); err != nil {
+			result.addWarning(warningSummaryNotWritten, fmt.Sprintf("Could not write the release summary to %s: %v", *summaryFile, err), err)
+		} else {
+			fmt.Fprintf(out, "Wrote release summary to %s\n", *summaryFile)
+		}
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	if *printTag {
+		fmt.Println(tag)
+	}
+	if *jsonOutput {
+		return printJSON(result)
+	}
 	return nil
 }
 
-func handleChangelogUpdate(section, content string, changelogManager ChangelogManager) error {
-	isDuplicate, err := changelogManager.AddChangelogSection(*changeLogFile, section, content)
+// checkRemoteURL verifies that the origin remote is the repository given with
+// --expected-remote-url, so a release is never pushed to a fork or mirror.
+// It runs before anything is changed.
+func checkRemoteURL(gitManager GitManager) error {
+	url, err := gitManager.GetRemoteURL(defaultRemote)
 	if err != nil {
-		return fmt.Errorf("Error adding changelog section: %v", err)
+		return fmt.Errorf("Error getting the URL of the %s remote: %v", defaultRemote, err)
+	}
+	if !git.SameRepository(url, *expectedRemoteURL) {
+		return fmt.Errorf("Error: The %s remote is %s, not the expected %s. Nothing was released.", defaultRemote, url, *expectedRemoteURL)
 	}
+	return nil
+}
 
-	if isDuplicate {
-		fmt.Printf("%s section: %s (duplicate entry, not added)\n", section, content)
-	} else {
-		fmt.Printf("%s section: %s\n", section, content)
+// firstReleaseVersion returns the version of the first release, given as the
+// argument of changelog first-release or with --initial-version. With
+// --version-scheme calver, it defaults to the first version of this month.
+func firstReleaseVersion() (string, error) {
+	version := *initialVersion
+	if *changelogFirstVersion != "" {
+		if initialVersionSet && *initialVersion != *changelogFirstVersion {
+			return "", fmt.Errorf("the version argument %s and --initial-version %s differ", *changelogFirstVersion, *initialVersion)
+		}
+		version = *changelogFirstVersion
+	}
+	if *versionScheme == "calver" {
+		if !initialVersionSet && *changelogFirstVersion == "" {
+			return calver.Next("", time.Now())
+		}
+		if _, err := calver.ParseVersion(version); err != nil {
+			return "", fmt.Errorf("%s is not a valid calendar version: %v", version, err)
+		}
+		return version, nil
+	}
+	if _, err := semver.ParseVersion(version); err != nil {
+		return "", fmt.Errorf("%s is not a valid semantic version: %v", version, err)
 	}
+	return version, nil
+}
 
+// checkVersionScheme makes sure the bump type fits the --version-scheme.
+// Major, minor, patch and range bumps need semantic versions, and calver
+// bumps need calendar versions. A first release works with both.
+func checkVersionScheme(bumpType string) error {
+	switch {
+	case *versionScheme == "calver" && bumpType == "constraint":
+		return fmt.Errorf("Error: bump --to can't be used with --version-scheme calver. Use changie bump calver.")
+	case *versionScheme == "calver" && bumpType != "calver" && bumpType != "first":
+		return fmt.Errorf("Error: %s releases can't be used with --version-scheme calver. Use changie bump calver.", bumpType)
+	case *versionScheme == "semver" && bumpType == "calver":
+		return fmt.Errorf("Error: calver releases require --version-scheme calver.")
+	}
 	return nil
 }
 
-func run(changelogManager ChangelogManager, gitManager GitManager, semverManager SemverManager) error {
-	fmt.Println("Debug: Entering run function")
+// checkFirstRelease makes sure that neither the changelog nor the git tags
+// have a released version yet
+func checkFirstRelease(changelogManager ChangelogManager, gitManager GitManager) error {
+	if _, err := firstReleaseVersion(); err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
 
-	if !isGitInstalled() {
-		return fmt.Errorf("Error: Git is not installed.")
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+	for _, v := range changelog.Parse(content).Versions {
+		if v.Name != "Unreleased" {
+			return fmt.Errorf("Error: %s already has the release %s. Use changie major, minor or patch for the next release.", *changeLogFile, v.Name)
+		}
 	}
 
-	// Get the git tag
-	version, err := gitManager.GetVersion()
-	fmt.Printf("Debug: GetVersion result: version=%s, err=%v\n", version, err)
+	tags, err := gitManager.ListTags()
 	if err != nil {
-		return fmt.Errorf("Error getting project version: %w", err)
+		return fmt.Errorf("Error listing tags: %v", err)
 	}
-	app.Version(version)
+	for _, tag := range tags {
+		if _, err := semver.ParseVersion(tag); err == nil {
+			return fmt.Errorf("Error: The version tag %s already exists. Use changie major, minor or patch for the next release.", tag)
+		}
+	}
+	return nil
+}
 
-	command, err := app.Parse(os.Args[1:])
+// tagMessage returns the annotated tag message of a release, the version as the
+// subject and its release notes as the body. On error, the message is only the version.
+func tagMessage(version string, changelogManager ChangelogManager, notesTemplate *changelog.NotesTemplate) (string, error) {
+	content, err := changelogManager.GetChangelogContent()
 	if err != nil {
-		return fmt.Errorf("Error parsing command: %w", err)
+		return version + "\n", err
+	}
+	notes, err := releaseNotes(content, version, notesTemplate)
+	if err != nil {
+		return version + "\n", err
 	}
+	if notes == "" {
+		return version + "\n", nil
+	}
+	return version + "\n\n" + notes, nil
+}
 
-	switch command {
-	case initCommand.FullCommand():
+// writeNotesFile writes the release notes of the version, with the notes
+// header and footer, to the file named by the notes file template and returns
+// the file name
+func writeNotesFile(name *changelog.NotesFileTemplate, tag, version string, changelogManager ChangelogManager, notesTemplate *changelog.NotesTemplate) (string, error) {
+	file, err := name.Render(changelog.NotesFileData{Tag: tag, Version: version})
+	if err != nil {
+		return "", err
+	}
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return "", err
+	}
+	notes, err := releaseNotes(content, version, notesTemplate)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, []byte(notes), 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// loadNotesTemplate parses the --notes-header and --notes-footer templates,
+// reading them from a file when the value starts with @
+func loadNotesTemplate() (*changelog.NotesTemplate, error) {
+	var texts [2]string
+	for i, value := range []string{*notesHeader, *notesFooter} {
+		if !strings.HasPrefix(value, "@") {
+			texts[i] = value
+			continue
+		}
+		data, err := os.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("Error reading release notes template: %v", err)
+		}
+		texts[i] = string(data)
+	}
+
+	notesTemplate, err := changelog.ParseNotesTemplate(texts[0], texts[1])
+	if err != nil {
+		return nil, fmt.Errorf("Error: Invalid release notes template: %v", err)
+	}
+	return notesTemplate, nil
+}
+
+// releaseNotes returns the release notes of a version with the notes header
+// and footer, without the sections excluded with --exclude-section
+func releaseNotes(content, version string, notesTemplate *changelog.NotesTemplate) (string, error) {
+	content = changelog.ExcludeSections(content, *excludeSections)
+	notes, err := changelog.GetVersionSection(content, version)
+	if err != nil {
+		return "", err
+	}
+	data := changelog.NotesData{Version: version, Date: changelog.Parse(content).Version(version).Date}
+	return notesTemplate.Render(notes, data)
+}
+
+func handleChangelogShow(w io.Writer, changelogManager ChangelogManager) error {
+	notesTemplate, err := loadNotesTemplate()
+	if err != nil {
+		return err
+	}
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	version := *changelogShowVersion
+	switch {
+	case *changelogShowUnreleased && version != "":
+		return fmt.Errorf("Error: --unreleased can't be combined with a version.")
+	case *changelogShowUnreleased:
+		version = "Unreleased"
+	case version == "":
+		if version, err = changelog.GetLatestChangelogVersion(content); err != nil {
+			return fmt.Errorf("Error getting latest version from changelog: %v", err)
+		}
+	}
+
+	if *jsonOutput {
+		v := changelog.Parse(content).Version(version)
+		if v == nil {
+			return fmt.Errorf("Error getting release notes: version %s not found in changelog", version)
+		}
+		v.RemoveSections(*excludeSections)
+		return fprintJSON(w, newVersionOutput(v))
+	}
+
+	notes, err := releaseNotes(content, version, notesTemplate)
+	if err != nil {
+		return fmt.Errorf("Error getting release notes: %v", err)
+	}
+	if notes == "" && version == "Unreleased" {
+		fmt.Fprintln(w, "The Unreleased section has no entries.")
+		return nil
+	}
+	fmt.Fprint(w, notes)
+	return nil
+}
+
+// writeSummaryFile writes the result of a release as JSON to file
+func writeSummaryFile(file string, result bumpOutput) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// changelogOutput is the JSON output of the changelog section commands
+type changelogOutput struct {
+	Success       bool              `json:"success"`
+	Section       string            `json:"section"`
+	Content       string            `json:"content"`
+	ChangelogFile string            `json:"changelog_file"`
+	Duplicate     bool              `json:"duplicate"`
+	Replaced      bool              `json:"replaced,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+func handleChangelogUpdate(section, content string, changelogManager ChangelogManager) error {
+	if *replaceMatch != "" && !*replaceEntry {
+		return fmt.Errorf("Error: --match is only used with --replace.")
+	}
+	result, err := addChangelogEntry(section, content, *replaceMatch, changelogManager)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return printJSON(result)
+	}
+	printChangelogEntry(result)
+	return nil
+}
+
+// addChangelogEntry adds the entry to the section. With --replace, the entry
+// that matches match, or the new entry when match is empty, is replaced.
+func addChangelogEntry(section, content, match string, changelogManager ChangelogManager) (changelogOutput, error) {
+	templates, err := changelog.ParseEntryTemplates(*entryTemplates)
+	if err != nil {
+		return changelogOutput{}, fmt.Errorf("Error: Invalid entry template: %v", err)
+	}
+	content, err = templates.Render(section, changelog.EntryData{Text: content, Issue: *entryIssue, Section: section})
+	if err != nil {
+		return changelogOutput{}, fmt.Errorf("Error: %v", err)
+	}
+	if *useEmoji {
+		content = changelog.AddEmojiPrefix(section, content, *sectionEmoji)
+	}
+	if err := changelog.ValidateMetadata(*entryMeta); err != nil {
+		return changelogOutput{}, fmt.Errorf("Error: %v", err)
+	}
+	content = changelog.AddMetadata(content, *entryMeta)
+
+	var isDuplicate, replaced bool
+	if *replaceEntry {
+		if match == "" {
+			match = content
+		}
+		replaced, err = changelogManager.ReplaceOrAddEntry(*changeLogFile, section, match, content)
+	} else {
+		isDuplicate, err = changelogManager.AddChangelogSection(*changeLogFile, section, content)
+	}
+	if err != nil {
+		return changelogOutput{}, fmt.Errorf("Error adding changelog section: %v", err)
+	}
+
+	_, metadata := changelog.SplitMetadata(content)
+
+	return changelogOutput{
+		Success:       true,
+		Section:       section,
+		Content:       content,
+		ChangelogFile: *changeLogFile,
+		Duplicate:     isDuplicate,
+		Replaced:      replaced,
+		Metadata:      metadata,
+	}, nil
+}
+
+func printChangelogEntry(result changelogOutput) {
+	switch {
+	case result.Duplicate:
+		fmt.Printf("%s section: %s (duplicate entry, not added)\n", result.Section, result.Content)
+	case result.Replaced:
+		fmt.Printf("%s section: %s (replaced existing entry)\n", result.Section, result.Content)
+	default:
+		fmt.Printf("%s section: %s\n", result.Section, result.Content)
+	}
+}
+
+func handleChangelogWrap(width int, changelogManager ChangelogManager) error {
+	if !*changelogWrapCheck {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	changed, err := changelogManager.WrapChangelog(*changeLogFile, width, *changelogWrapCheck)
+	if err != nil {
+		return fmt.Errorf("Error wrapping changelog: %v", err)
+	}
+
+	switch {
+	case *changelogWrapCheck && changed:
+		return fmt.Errorf("Error: %s is not wrapped at %d columns. Run changie changelog wrap to fix it.", *changeLogFile, width)
+	case *changelogWrapCheck:
+		fmt.Printf("%s is wrapped at %d columns.\n", *changeLogFile, width)
+	case changed:
+		fmt.Printf("Wrapped entries in %s at %d columns.\n", *changeLogFile, width)
+	default:
+		fmt.Printf("Entries in %s are already wrapped at %d columns.\n", *changeLogFile, width)
+	}
+	return nil
+}
+
+func handleChangelogReorder(changelogManager ChangelogManager) error {
+	if !*changelogReorderCheck {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	changed, err := changelogManager.ReorderChangelog(*changeLogFile, *changelogReorderAll, *changelogReorderCheck)
+	if err != nil {
+		return fmt.Errorf("Error reordering changelog sections: %v", err)
+	}
+
+	switch {
+	case *changelogReorderCheck && changed:
+		return fmt.Errorf("Error: The sections in %s are not in the Keep a Changelog order. Run changie changelog reorder-sections to fix it.", *changeLogFile)
+	case *changelogReorderCheck:
+		fmt.Printf("The sections in %s are in the Keep a Changelog order.\n", *changeLogFile)
+	case changed:
+		fmt.Printf("Reordered the sections in %s.\n", *changeLogFile)
+	default:
+		fmt.Printf("The sections in %s are already in the Keep a Changelog order.\n", *changeLogFile)
+	}
+	return nil
+}
+
+func handleChangelogFixDates(changelogManager ChangelogManager) error {
+	if !*changelogFixDatesCheck {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	result, err := changelogManager.FixDates(*changeLogFile, *changelogFixDatesFormat, *changelogFixDatesCheck)
+	if err != nil {
+		return fmt.Errorf("Error fixing changelog dates: %v", err)
+	}
+
+	prefix := "Fixed"
+	if *changelogFixDatesCheck {
+		prefix = "Would fix"
+	}
+	for _, fix := range result.Fixes {
+		fmt.Printf("%s: %s\n", prefix, fix)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	switch {
+	case *changelogFixDatesCheck && len(result.Fixes) > 0:
+		return fmt.Errorf("Error: %s has %d dates that aren't in the %s format. Run changie changelog fix-dates to fix them.", *changeLogFile, len(result.Fixes), *changelogFixDatesFormat)
+	case len(result.Fixes) > 0:
+		fmt.Printf("Fixed %d dates in %s.\n", len(result.Fixes), *changeLogFile)
+	default:
+		fmt.Printf("The dates in %s are in the %s format.\n", *changeLogFile, *changelogFixDatesFormat)
+	}
+	return nil
+}
+
+func handleChangelogSetSection(changelogManager ChangelogManager) error {
+	section := *changelogSetSection
+	for _, name := range changelog.ValidSections() {
+		if strings.EqualFold(name, section) {
+			section = name
+		}
+	}
+	entries := *changelogSetEntries
+	if len(entries) == 0 && !*changelogSetRemoveEmpty {
+		return fmt.Errorf("Error: No entries given for the %s section. Use --remove-empty to remove the section.", section)
+	}
+
+	if err := backupChangelog(changelogManager); err != nil {
+		return err
+	}
+	if err := changelogManager.SetSectionEntries(*changeLogFile, section, entries); err != nil {
+		return fmt.Errorf("Error setting changelog section: %v", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("Removed the %s section from Unreleased in %s.\n", section, *changeLogFile)
+	} else {
+		fmt.Printf("Set the %s section of Unreleased in %s to %d entries.\n", section, *changeLogFile, len(entries))
+	}
+	return nil
+}
+
+// sectionInfo is the JSON output of a section listed by the changelog sections command
+type sectionInfo struct {
+	Name    string   `json:"name"`
+	Emoji   string   `json:"emoji"`
+	Aliases []string `json:"aliases"` // Names accepted for the section, e.g. in commands and fragment files
+}
+
+func handleChangelogSections() error {
+	sections := []sectionInfo{}
+	for _, name := range changelog.ValidSections() {
+		emoji, ok := (*sectionEmoji)[name]
+		if !ok {
+			emoji = changelog.DefaultSectionEmoji[name]
+		}
+		sections = append(sections, sectionInfo{Name: name, Emoji: emoji, Aliases: []string{strings.ToLower(name)}})
+	}
+
+	if *jsonOutput {
+		return printJSON(sections)
+	}
+	for _, s := range sections {
+		fmt.Printf("%-10s  emoji %s  aliases: %s\n", s.Name, s.Emoji, strings.Join(s.Aliases, ", "))
+	}
+	return nil
+}
+
+// commitRange returns the range of commits to list, --since or the latest tag
+// up to --until. An empty start means all commits.
+func commitRange(gitManager GitManager) (string, string, error) {
+	since := *commitsSince
+	if since == "" {
+		tag, err := gitManager.GetLastTag()
+		if err != nil {
+			return "", "", fmt.Errorf("Error getting latest tag: %v", err)
+		}
+		since = tag
+	} else if !gitManager.RefExists(since) {
+		return "", "", fmt.Errorf("Error: --since %s does not exist.", since)
+	}
+	if !gitManager.RefExists(*commitsUntil) {
+		return "", "", fmt.Errorf("Error: --until %s does not exist.", *commitsUntil)
+	}
+	return since, *commitsUntil, nil
+}
+
+// commitOutput is the JSON output of a listed commit
+type commitOutput struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Merge   bool   `json:"merge"`
+}
+
+func handleChangelogCommits(w io.Writer, gitManager GitManager) error {
+	from, to, err := commitRange(gitManager)
+	if err != nil {
+		return err
+	}
+	commits, err := gitManager.GetCommitsBetween(from, to)
+	if err != nil {
+		return fmt.Errorf("Error listing commits: %v", err)
+	}
+
+	if *jsonOutput {
+		output := []commitOutput{}
+		for _, c := range commits {
+			output = append(output, commitOutput{Hash: c.Hash, Subject: c.Subject, Merge: c.Merge})
+		}
+		return fprintJSON(w, output)
+	}
+
+	switch {
+	case len(commits) == 0 && from == "":
+		fmt.Fprintf(w, "No commits found up to %s\n", to)
+		return nil
+	case len(commits) == 0:
+		fmt.Fprintf(w, "No commits found between %s and %s\n", from, to)
+		return nil
+	}
+	for _, c := range commits {
+		hash := c.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		fmt.Fprintf(w, "%s %s\n", hash, c.Subject)
+	}
+	return nil
+}
+
+// conventionalTypeRegex matches the type of a Conventional Commits subject, e.g. "feat(cli)!: ..."
+var conventionalTypeRegex = regexp.MustCompile(`^([a-zA-Z]+)(?:\([^)]*\))?!?: `)
+
+// countOutput is the JSON output of the changelog count-since command
+type countOutput struct {
+	Since  string         `json:"since"` // Empty when there is no previous tag
+	Total  int            `json:"total"`
+	ByType map[string]int `json:"by_type"`
+}
+
+func handleChangelogCount(gitManager GitManager) error {
+	from, to, err := commitRange(gitManager)
+	if err != nil {
+		return err
+	}
+	commits, err := gitManager.GetCommitsBetween(from, to)
+	if err != nil {
+		return fmt.Errorf("Error listing commits: %v", err)
+	}
+
+	result := countOutput{Since: from, ByType: map[string]int{}}
+	for _, c := range commits {
+		if c.Merge && !*changelogCountMerges {
+			continue
+		}
+		result.Total++
+		commitType := "other"
+		if matches := conventionalTypeRegex.FindStringSubmatch(c.Subject); matches != nil {
+			commitType = strings.ToLower(matches[1])
+		}
+		result.ByType[commitType]++
+	}
+
+	if *jsonOutput {
+		return printJSON(result)
+	}
+
+	if from == "" {
+		fmt.Printf("%d commits, no previous tag found\n", result.Total)
+	} else {
+		fmt.Printf("%d commits since %s\n", result.Total, from)
+	}
+	if *changelogCountByType {
+		types := make([]string, 0, len(result.ByType))
+		for commitType := range result.ByType {
+			types = append(types, commitType)
+		}
+		sort.Slice(types, func(i, j int) bool {
+			if result.ByType[types[i]] != result.ByType[types[j]] {
+				return result.ByType[types[i]] > result.ByType[types[j]]
+			}
+			return types[i] < types[j]
+		})
+		for _, commitType := range types {
+			fmt.Printf("  %s: %d\n", commitType, result.ByType[commitType])
+		}
+	}
+	return nil
+}
+
+func handleChangelogValidate(changelogManager ChangelogManager, gitManager GitManager) error {
+	if maxUnreleasedSet && *changelogMaxUnreleased < 0 {
+		return fmt.Errorf("Error: --max-unreleased must not be negative.")
+	}
+
+	if *changelogValidateFix {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+		added, err := changelogManager.FixMissingLinks(*changeLogFile, linkOptions(gitManager, *remoteRepositoryProvider))
+		if err != nil {
+			return fmt.Errorf("Error adding missing links: %v", err)
+		}
+		for _, version := range added {
+			fmt.Printf("Added missing link for [%s]\n", version)
+		}
+	}
+
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	issues := changelog.ValidateChangelog(content)
+	if *changelogValidateStrict {
+		issues = changelog.ValidateKeepAChangelog(content)
+	} else if *changelogValidateLinks || *changelogValidateFix {
+		issues = append(issues, changelog.CheckLinks(content)...)
+	}
+	if maxUnreleasedSet {
+		issues = append(issues, changelog.CheckMaxUnreleased(content, *changelogMaxUnreleased)...)
+	}
+	for _, issue := range issues {
+		if !useGitHubAnnotations() {
+			fmt.Printf("Error: %s\n", issue)
+			continue
+		}
+		message := issue.Message
+		if issue.Rule != "" {
+			message += fmt.Sprintf(" [%s]", issue.Rule)
+		}
+		fmt.Println(githubAnnotation("error", *changeLogFile, issue.Line, message))
+	}
+
+	switch {
+	case len(issues) > 0:
+		return fmt.Errorf("Error: %s has %d validation issues.", *changeLogFile, len(issues))
+	case *changelogValidateStrict:
+		fmt.Printf("%s follows Keep a Changelog 1.1.0.\n", *changeLogFile)
+	default:
+		fmt.Printf("%s is valid.\n", *changeLogFile)
+	}
+	return nil
+}
+
+func handleChangelogLint(changelogManager ChangelogManager) error {
+	if *changelogLintFix && !*changelogLintCheck {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	opts := changelog.LintOptions{Punctuation: *changelogLintPunctuation}
+	result, err := changelogManager.LintChangelog(*changeLogFile, opts, *changelogLintFix, *changelogLintCheck)
+	if err != nil {
+		return fmt.Errorf("Error linting changelog: %v", err)
+	}
+
+	prefix := "Issue"
+	switch {
+	case *changelogLintFix && *changelogLintCheck:
+		prefix = "Would fix"
+	case *changelogLintFix:
+		prefix = "Fixed"
+	}
+	annotate := useGitHubAnnotations()
+	for _, fix := range result.Fixes {
+		if annotate && prefix != "Fixed" {
+			fmt.Println(githubAnnotation("error", *changeLogFile, 0, fmt.Sprintf("%s: %s", prefix, fix)))
+			continue
+		}
+		fmt.Printf("%s: %s\n", prefix, fix)
+	}
+	for _, issue := range result.Errors {
+		if annotate {
+			fmt.Println(githubAnnotation("error", *changeLogFile, 0, issue))
+			continue
+		}
+		fmt.Printf("Error: %s\n", issue)
+	}
+	for _, warning := range result.Warnings {
+		if annotate {
+			fmt.Println(githubAnnotation("warning", *changeLogFile, 0, warning))
+			continue
+		}
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		return fmt.Errorf("Error: %s has %d issues that can't be fixed automatically.", *changeLogFile, len(result.Errors))
+	case len(result.Fixes) > 0 && *changelogLintFix && !*changelogLintCheck:
+		fmt.Printf("Fixed %d issues in %s.\n", len(result.Fixes), *changeLogFile)
+	case len(result.Fixes) > 0:
+		return fmt.Errorf("Error: %s has %d issues. Run changie changelog lint --fix to fix them.", *changeLogFile, len(result.Fixes))
+	default:
+		fmt.Printf("No issues found in %s.\n", *changeLogFile)
+	}
+	return nil
+}
+
+// useGitHubAnnotations reports whether issues are printed as GitHub Actions
+// annotations: with --github, or in a GitHub Actions workflow unless
+// --no-github is used
+func useGitHubAnnotations() bool {
+	if githubAnnotationsSet {
+		return *githubAnnotations
+	}
+	return githubActions
+}
+
+// githubAnnotation formats an issue as a GitHub Actions workflow command, e.g.
+// "::error file=CHANGELOG.md,line=3::message", so it is shown on the file in
+// pull requests. A line of 0 annotates the file as a whole.
+func githubAnnotation(level, file string, line int, message string) string {
+	escapeData := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	escapeProperty := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+	properties := "file=" + escapeProperty.Replace(file)
+	if line > 0 {
+		properties += fmt.Sprintf(",line=%d", line)
+	}
+	return fmt.Sprintf("::%s %s::%s", level, properties, escapeData.Replace(message))
+}
+
+// fixLinksOutput is the JSON output of the changelog fix-links command
+type fixLinksOutput struct {
+	Removed []string `json:"removed"`
+}
+
+func handleChangelogFixLinks(changelogManager ChangelogManager) error {
+	if err := backupChangelog(changelogManager); err != nil {
+		return err
+	}
+	removed, err := changelogManager.FixLinks(*changeLogFile)
+	if err != nil {
+		return fmt.Errorf("Error fixing changelog links: %v", err)
+	}
+
+	if *jsonOutput {
+		return printJSON(fixLinksOutput{Removed: append([]string{}, removed...)})
+	}
+	if len(removed) == 0 {
+		fmt.Printf("No links to nonexistent versions found in %s.\n", *changeLogFile)
+		return nil
+	}
+	for _, version := range removed {
+		fmt.Printf("Removed link to nonexistent version %s\n", version)
+	}
+	fmt.Printf("Removed %d links from %s.\n", len(removed), *changeLogFile)
+	return nil
+}
+
+func handleChangelogMoveVersion(changelogManager ChangelogManager) error {
+	if (*changelogMoveBefore == "") == (*changelogMoveAfter == "") {
+		return fmt.Errorf("Error: use either --before or --after to give the new position.")
+	}
+
+	target, position := *changelogMoveBefore, "before"
+	if *changelogMoveAfter != "" {
+		target, position = *changelogMoveAfter, "after"
+	}
+	if err := backupChangelog(changelogManager); err != nil {
+		return err
+	}
+	if err := changelogManager.MoveVersion(*changeLogFile, *changelogMoveVersion, target, position == "after"); err != nil {
+		return fmt.Errorf("Error moving version: %v", err)
+	}
+
+	fmt.Printf("Moved %s %s %s in %s.\n", *changelogMoveVersion, position, target, *changeLogFile)
+	return nil
+}
+
+// backupChangelog backs up the changelog before a command rewrites it, unless
+// --no-backup is given. The backup is reported on stderr, so the output of
+// the command itself, such as JSON, is unchanged.
+func backupChangelog(changelogManager ChangelogManager) error {
+	if !*backupEnabled {
+		return nil
+	}
+	backup, err := changelogManager.BackupChangelog(*changeLogFile, *backupKeep)
+	if err != nil {
+		return fmt.Errorf("Error backing up changelog: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Backed up %s to %s\n", *changeLogFile, backup)
+	return nil
+}
+
+func handleChangelogBackup(changelogManager ChangelogManager) error {
+	backup, err := changelogManager.BackupChangelog(*changeLogFile, *backupKeep)
+	if err != nil {
+		return fmt.Errorf("Error backing up changelog: %v", err)
+	}
+	fmt.Printf("Backed up %s to %s\n", *changeLogFile, backup)
+	return nil
+}
+
+// archiveOutput is the JSON output of the changelog archive command
+type archiveOutput struct {
+	DryRun   bool                `json:"dry_run"`
+	Archives []changelog.Archive `json:"archives"`
+}
+
+func handleChangelogArchive(changelogManager ChangelogManager) error {
+	// Releases of the current year stay in the changelog
+	year := time.Now().Year()
+	if !*changelogArchiveDryRun {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	archives, err := changelogManager.ArchiveChangelog(*changeLogFile, year, *changelogArchiveDryRun)
+	if err != nil {
+		return fmt.Errorf("Error archiving changelog: %v", err)
+	}
+
+	if *jsonOutput {
+		return printJSON(archiveOutput{DryRun: *changelogArchiveDryRun, Archives: append([]changelog.Archive{}, archives...)})
+	}
+	if len(archives) == 0 {
+		fmt.Printf("No releases before %d to archive in %s.\n", year, *changeLogFile)
+		return nil
+	}
+
+	verb := "Moved"
+	if *changelogArchiveDryRun {
+		verb = "Would move"
+	}
+	for _, archive := range archives {
+		fmt.Printf("%s %s to %s\n", verb, strings.Join(archive.Versions, ", "), archive.File)
+	}
+	if !*changelogArchiveDryRun {
+		fmt.Printf("Archived releases before %d from %s.\n", year, *changeLogFile)
+	}
+	return nil
+}
+
+func handleChangelogVerifyLinks(changelogManager ChangelogManager) error {
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	// The default transport uses the proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	client := &http.Client{Timeout: *changelogVerifyTimeout}
+	links := changelog.LinkReferences(content)
+	broken := 0
+	for _, link := range links {
+		err := changelog.ValidateLinkURL(link.URL)
+		if err == nil && *changelogVerifyOnline {
+			err = changelog.CheckLink(client, link.URL)
+		}
+		if err != nil {
+			fmt.Printf("Broken link [%s] on line %d: %s: %v\n", link.Label, link.Line, link.URL, err)
+			broken++
+		}
+	}
+
+	switch {
+	case broken > 0:
+		return fmt.Errorf("Error: %d of %d links in %s are broken.", broken, len(links), *changeLogFile)
+	case *changelogVerifyOnline:
+		fmt.Printf("All %d links in %s resolve.\n", len(links), *changeLogFile)
+	default:
+		fmt.Printf("All %d links in %s are valid URLs. Use --online to check that they resolve.\n", len(links), *changeLogFile)
+	}
+	return nil
+}
+
+func handleMigrate(changelogManager ChangelogManager) error {
+	if !*migrateCheck {
+		if err := backupChangelog(changelogManager); err != nil {
+			return err
+		}
+	}
+	changed, err := changelogManager.MigrateChangelog(*changeLogFile, *migrateCheck)
+	if err != nil {
+		return fmt.Errorf("Error migrating changelog: %v", err)
+	}
+
+	switch {
+	case *migrateCheck && changed:
+		return fmt.Errorf("Error: The header of %s is outdated. Run changie migrate to update it.", *changeLogFile)
+	case changed:
+		fmt.Printf("Updated the header of %s to Keep a Changelog 1.1.0.\n", *changeLogFile)
+	default:
+		fmt.Printf("The header of %s is up to date.\n", *changeLogFile)
+	}
+	return nil
+}
+
+// assembleOutput is the JSON output of the changelog assemble command
+type assembleOutput struct {
+	Entries []changelogOutput `json:"entries"`
+	Deleted int               `json:"deleted"`
+}
+
+func handleChangelogAssemble(changelogManager ChangelogManager) error {
+	if *replaceMatch != "" {
+		return fmt.Errorf("Error: --match applies to a single entry and can't be used with assemble.")
+	}
+	fragments, err := changelog.ReadFragments(*changelogAssembleDir)
+	if err != nil {
+		return fmt.Errorf("Error reading changelog fragments: %v", err)
+	}
+	if len(fragments) == 0 && !*jsonOutput {
+		fmt.Printf("No changelog fragments found in %s\n", *changelogAssembleDir)
+		return nil
+	}
+
+	output := assembleOutput{Entries: []changelogOutput{}}
+	for _, fragment := range fragments {
+		for _, entry := range fragment.Entries {
+			result, err := addChangelogEntry(fragment.Section, entry, "", changelogManager)
+			if err != nil {
+				return err
+			}
+			output.Entries = append(output.Entries, result)
+			if !*jsonOutput {
+				printChangelogEntry(result)
+			}
+		}
+	}
+
+	if *changelogAssembleDelete {
+		for _, fragment := range fragments {
+			if err := os.Remove(fragment.Path); err != nil {
+				return fmt.Errorf("Error deleting changelog fragment: %v", err)
+			}
+		}
+		output.Deleted = len(fragments)
+		if !*jsonOutput {
+			fmt.Printf("Deleted %d changelog fragments from %s\n", len(fragments), *changelogAssembleDir)
+		}
+	}
+
+	if *jsonOutput {
+		return printJSON(output)
+	}
+	return nil
+}
+
+func handleChangelogDate(changelogManager ChangelogManager) error {
+	date := *changelogDate
+	switch {
+	case *changelogDateClear && date != "":
+		return fmt.Errorf("Error: Provide either a date or --clear, not both.")
+	case !*changelogDateClear && date == "":
+		return fmt.Errorf("Error: Provide a date (YYYY-MM-DD) or --clear.")
+	}
+
+	if err := changelogManager.SetUnreleasedDate(*changeLogFile, date); err != nil {
+		return fmt.Errorf("Error setting Unreleased date: %v", err)
+	}
+
+	if date == "" {
+		fmt.Println("Cleared the scheduled release date.")
+	} else {
+		fmt.Printf("Scheduled the release for %s.\n", date)
+	}
+	return nil
+}
+
+// graphOutput is the JSON output of the changelog graph command
+type graphOutput struct {
+	Periods []changelog.PeriodCount `json:"periods"`
+	Undated int                     `json:"undated"`
+}
+
+func handleChangelogGrep(w io.Writer, changelogManager ChangelogManager) error {
+	pattern := *changelogGrepPattern
+	if *changelogGrepIgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("Error: invalid pattern %q: %v", *changelogGrepPattern, err)
+	}
+
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	matches := changelog.Parse(content).Grep(re, *changelogGrepUnreleased)
+	if *jsonOutput {
+		return fprintJSON(w, matches)
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintf(w, "No entries in %s match %s\n", *changeLogFile, *changelogGrepPattern)
+	}
+	for _, m := range matches {
+		version := m.Version
+		if m.Date != "" {
+			version += " (" + m.Date + ")"
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", version, m.Section, m.Entry)
+	}
+	return nil
+}
+
+// handleChangelogEntries prints every entry as it is read, so large changelogs
+// aren't held in memory. With --json, each entry is a JSON object on its own
+// line. With --no-stream, the changelog is parsed as a whole and --json prints
+// a single array.
+func handleChangelogEntries(w io.Writer, changelogManager ChangelogManager) error {
+	printEntry := func(m changelog.Match) error {
+		version := m.Version
+		if m.Date != "" {
+			version += " (" + m.Date + ")"
+		}
+		_, err := fmt.Fprintf(w, "%s %s: %s\n", version, m.Section, m.Entry)
+		return err
+	}
+	if *jsonOutput {
+		encoder := json.NewEncoder(w)
+		printEntry = func(m changelog.Match) error { return encoder.Encode(m) }
+	}
+
+	if *changelogEntriesNoStream {
+		content, err := changelogManager.GetChangelogContent()
+		if err != nil {
+			return fmt.Errorf("Error reading changelog: %v", err)
+		}
+		entries := changelog.Parse(content).Entries()
+		if *jsonOutput {
+			return fprintJSON(w, entries)
+		}
+		for _, m := range entries {
+			if err := printEntry(m); err != nil {
+				return fmt.Errorf("Error writing entries: %v", err)
+			}
+		}
+		return nil
+	}
+
+	file, err := changelogManager.OpenChangelog()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+	defer file.Close()
+	if err := changelog.ScanEntries(file, printEntry); err != nil {
+		return fmt.Errorf("Error reading entries: %v", err)
+	}
+	return nil
+}
+
+// statsOutput is the JSON output of the changelog stats command
+type statsOutput struct {
+	Versions []changelog.VersionStats `json:"versions"`
+	Sections map[string]int           `json:"sections"` // Entries per section across the versions
+	Total    int                      `json:"total"`
+}
+
+func handleChangelogStats(w io.Writer, changelogManager ChangelogManager) error {
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	c := changelog.Parse(content)
+	if !*changelogStatsAll {
+		unreleased := c.Version("Unreleased")
+		if unreleased == nil {
+			return fmt.Errorf("Error: %s has no Unreleased section.", *changeLogFile)
+		}
+		c = &changelog.Changelog{Versions: []*changelog.Version{unreleased}}
+	}
+	versions, total := c.Stats()
+
+	if *jsonOutput {
+		return fprintJSON(w, statsOutput{Versions: versions, Sections: total.Sections, Total: total.Total})
+	}
+
+	// The Keep a Changelog sections come first, then any other sections by name
+	sections := changelog.ValidSections()
+	known := map[string]bool{}
+	for _, name := range sections {
+		known[name] = true
+	}
+	var others []string
+	for name := range total.Sections {
+		if !known[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	sections = append(sections, others...)
+
+	width := len("Version")
+	for _, v := range versions {
+		if len(v.Version) > width {
+			width = len(v.Version)
+		}
+	}
+	printRow := func(name string, counts map[string]int, sum int) {
+		fmt.Fprintf(w, "%-*s", width, name)
+		for _, section := range sections {
+			fmt.Fprintf(w, "  %*d", len(section), counts[section])
+		}
+		fmt.Fprintf(w, "  %5d\n", sum)
+	}
+
+	fmt.Fprintf(w, "%-*s  %s  Total\n", width, "Version", strings.Join(sections, "  "))
+	for _, v := range versions {
+		printRow(v.Version, v.Sections, v.Total)
+	}
+	if *changelogStatsAll {
+		printRow("Total", total.Sections, total.Total)
+	}
+	return nil
+}
+
+func handleChangelogGraph(w io.Writer, changelogManager ChangelogManager) error {
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	periods, undated, err := changelog.Parse(content).ReleasesPerPeriod(*changelogGraphPeriod)
+	if err != nil {
+		return fmt.Errorf("Error counting releases: %v", err)
+	}
+
+	if *jsonOutput {
+		return fprintJSON(w, graphOutput{Periods: periods, Undated: undated})
+	}
+
+	if len(periods) == 0 {
+		fmt.Fprintf(w, "No dated releases found in %s\n", *changeLogFile)
+	}
+	for _, p := range periods {
+		fmt.Fprintf(w, "%-7s %s %d\n", p.Period, strings.Repeat("#", p.Releases), p.Releases)
+	}
+	if undated > 0 {
+		fmt.Fprintf(w, "\nExcluded %d versions without a date.\n", undated)
+	}
+	return nil
+}
+
+func handleChangelogRenameFile(changelogManager ChangelogManager) error {
+	from, to := *changeLogFile, *changelogRenameTarget
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return fmt.Errorf("Error: The changelog is already named %s.", to)
+	}
+
+	links, err := changelogManager.RenameChangelog(from, to)
+	if err != nil {
+		return fmt.Errorf("Error renaming changelog: %v", err)
+	}
+
+	fmt.Printf("Renamed %s to %s.\n", from, to)
+	if links > 0 {
+		fmt.Printf("Updated %d links to the changelog file.\n", links)
+	}
+	fmt.Printf("Use --file %s with changie from now on.\n", to)
+	return nil
+}
+
+// needsReleaseOutput is the JSON output of the changelog needs-release command
+type needsReleaseOutput struct {
+	NeedsRelease bool `json:"needs_release"`
+	Entries      int  `json:"entries"` // Entries in the Unreleased section
+	MinEntries   int  `json:"min_entries"`
+}
+
+// handleChangelogNeedsRelease succeeds when the Unreleased section has at
+// least --min-entries entries, so CI jobs can release only when there is
+// something to release
+func handleChangelogNeedsRelease(changelogManager ChangelogManager) error {
+	if *changelogNeedsMinEntries < 1 {
+		return fmt.Errorf("Error: --min-entries must be at least 1.")
+	}
+
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	entries := 0
+	if unreleased := changelog.Parse(content).Version("Unreleased"); unreleased != nil {
+		entries = unreleased.EntryCount()
+	}
+	needsRelease := entries >= *changelogNeedsMinEntries
+
+	if *jsonOutput {
+		if err := printJSON(needsReleaseOutput{NeedsRelease: needsRelease, Entries: entries, MinEntries: *changelogNeedsMinEntries}); err != nil {
+			return err
+		}
+	} else if needsRelease {
+		fmt.Printf("Release needed: %s has %d unreleased entries.\n", *changeLogFile, entries)
+	}
+
+	if !needsRelease {
+		return fmt.Errorf("Error: No release needed: %s has %d unreleased entries, %d needed.", *changeLogFile, entries, *changelogNeedsMinEntries)
+	}
+	return nil
+}
+
+// compareVersion is a version in the output of the changelog compare-to-git
+// command, with the tag and the changelog header it was found in
+type compareVersion struct {
+	Version   string `json:"version"`
+	Tag       string `json:"tag,omitempty"`
+	Changelog string `json:"changelog,omitempty"`
+}
+
+// compareOutput is the JSON output of the changelog compare-to-git command
+type compareOutput struct {
+	Versions           []compareVersion `json:"versions"`
+	MissingInChangelog []string         `json:"missing_in_changelog"` // Tags without a changelog section
+	MissingTags        []string         `json:"missing_tags"`         // Changelog versions without a tag
+	InSync             bool             `json:"in_sync"`
+}
+
+// handleChangelogCompareToGit lists the version tags and the released
+// changelog versions side by side. Versions are matched without their "v"
+// prefix, so the tag v1.2.0 matches the changelog version 1.2.0.
+func handleChangelogCompareToGit(w io.Writer, changelogManager ChangelogManager, gitManager GitManager) error {
+	tags, err := gitManager.ListTags()
+	if err != nil {
+		return fmt.Errorf("Error listing tags: %v", err)
+	}
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	versions := map[string]*compareVersion{}
+	get := func(version string) *compareVersion {
+		key := strings.TrimPrefix(version, "v")
+		if versions[key] == nil {
+			versions[key] = &compareVersion{Version: key}
+		}
+		return versions[key]
+	}
+	for _, tag := range tags {
+		if _, err := semver.ParseVersion(tag); err == nil && get(tag).Tag == "" {
+			get(tag).Tag = tag
+		}
+	}
+	for _, v := range changelog.Parse(content).Versions {
+		if _, err := semver.ParseVersion(v.Name); err == nil && get(v.Name).Changelog == "" {
+			get(v.Name).Changelog = v.Name
+		}
+	}
+
+	result := compareOutput{Versions: []compareVersion{}, MissingInChangelog: []string{}, MissingTags: []string{}}
+	for _, v := range versions {
+		result.Versions = append(result.Versions, *v)
+	}
+	sort.Slice(result.Versions, func(i, j int) bool {
+		cmp, _ := semver.Compare(result.Versions[i].Version, result.Versions[j].Version)
+		return cmp > 0
+	})
+	for _, v := range result.Versions {
+		switch {
+		case v.Changelog == "":
+			result.MissingInChangelog = append(result.MissingInChangelog, v.Tag)
+		case v.Tag == "":
+			result.MissingTags = append(result.MissingTags, v.Changelog)
+		}
+	}
+	discrepancies := len(result.MissingInChangelog) + len(result.MissingTags)
+	result.InSync = discrepancies == 0
+
+	if *jsonOutput {
+		if err := fprintJSON(w, result); err != nil {
+			return err
+		}
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tTAG\tCHANGELOG")
+		for _, v := range result.Versions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Version, orMissing(v.Tag), orMissing(v.Changelog))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if discrepancies > 0 {
+			fmt.Fprintln(w)
+		}
+		for _, tag := range result.MissingInChangelog {
+			fmt.Fprintf(w, "Tag %s has no section in %s.\n", tag, *changeLogFile)
+		}
+		for _, version := range result.MissingTags {
+			fmt.Fprintf(w, "Version %s in %s has no tag.\n", version, *changeLogFile)
+		}
+		if discrepancies == 0 {
+			fmt.Fprintf(w, "\nThe version tags and %s are in sync.\n", *changeLogFile)
+		}
+	}
+
+	if *strict && discrepancies > 0 {
+		return fmt.Errorf("Error: Found %d differences between the version tags and %s.", discrepancies, *changeLogFile)
+	}
+	return nil
+}
+
+// orMissing returns the value, or "missing" when it is empty
+func orMissing(value string) string {
+	if value == "" {
+		return "missing"
+	}
+	return value
+}
+
+// buildInfo is the JSON output of the version command
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// getBuildInfo returns the build information of the changie binary. Without
+// a version from -ldflags, the module version of go install is used, or
+// "dev" for local builds.
+func getBuildInfo() buildInfo {
+	info := buildInfo{Version: buildVersion, Commit: buildCommit, Date: buildDate, GoVersion: runtime.Version(), OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if info.Version == "" {
+		info.Version = "dev"
+		if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+	}
+	return info
+}
+
+func handleVersion() error {
+	info := getBuildInfo()
+	if *jsonOutput {
+		return printJSON(info)
+	}
+
+	fmt.Printf("changie %s\n", info.Version)
+	if *versionBuildInfo {
+		fmt.Printf("Commit:     %s\n", orUnknown(info.Commit))
+		fmt.Printf("Built:      %s\n", orUnknown(info.Date))
+		fmt.Printf("Go version: %s\n", info.GoVersion)
+		fmt.Printf("Platform:   %s/%s\n", info.OS, info.Arch)
+	}
+	return nil
+}
+
+// orUnknown returns the value, or "unknown" when it is empty
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// initOutput is the JSON output of the init command
+type initOutput struct {
+	Success       bool   `json:"success"`
+	ChangelogFile string `json:"changelog_file"`
+	Created       bool   `json:"created"`
+}
+
+// versionOutput is a version block in the JSON output of the changelog diff-versions command
+type versionOutput struct {
+	Version  string          `json:"version"`
+	Date     string          `json:"date,omitempty"`
+	Sections []sectionOutput `json:"sections"`
+}
+
+type sectionOutput struct {
+	Name     string              `json:"name"`
+	Entries  []string            `json:"entries"`
+	Metadata []map[string]string `json:"metadata,omitempty"` // Metadata of each entry, in the order of the entries
+}
+
+// newVersionOutput returns the sections and entries of a version for JSON output
+func newVersionOutput(v *changelog.Version) versionOutput {
+	vo := versionOutput{Version: v.Name, Date: v.Date, Sections: []sectionOutput{}}
+	for _, s := range v.Sections {
+		so := sectionOutput{Name: s.Name, Entries: []string{}}
+		hasMetadata := false
+		for _, e := range s.Entries {
+			so.Entries = append(so.Entries, strings.Join(append([]string{e.Text}, e.Nested...), "\n"))
+			meta := e.Metadata()
+			if meta == nil {
+				meta = map[string]string{}
+			}
+			so.Metadata = append(so.Metadata, meta)
+			hasMetadata = hasMetadata || len(meta) > 0
+		}
+		if !hasMetadata {
+			so.Metadata = nil
+		}
+		vo.Sections = append(vo.Sections, so)
+	}
+	return vo
+}
+
+func handleChangelogDiff(w io.Writer, changelogManager ChangelogManager) error {
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	versions, err := changelog.Parse(content).VersionsBetween(*changelogDiffFrom, *changelogDiffTo, *changelogDiffIncludeFrom)
+	if err != nil {
+		return fmt.Errorf("Error selecting versions: %v", err)
+	}
+
+	if *jsonOutput {
+		output := []versionOutput{}
+		for _, v := range versions {
+			output = append(output, newVersionOutput(v))
+		}
+		return fprintJSON(w, output)
+	}
+
+	if len(versions) == 0 {
+		fmt.Fprintf(w, "No releases found between %s and %s\n", *changelogDiffFrom, *changelogDiffTo)
+		return nil
+	}
+
+	var lines []string
+	for _, v := range versions {
+		lines = append(lines, v.Lines()...)
+	}
+	fmt.Fprintln(w, strings.TrimRight(strings.Join(lines, "\n"), "\n"))
+	return nil
+}
+
+// handleChangelogEntriesBetween prints the entries between two tags as one
+// list, with the entries of each section together, e.g. for an upgrade guide
+func handleChangelogEntriesBetween(w io.Writer, changelogManager ChangelogManager) error {
+	content, err := changelogManager.GetChangelogContent()
+	if err != nil {
+		return fmt.Errorf("Error reading changelog: %v", err)
+	}
+
+	entries, err := changelog.Parse(content).EntriesBetween(*changelogBetweenFrom, *changelogBetweenTo, *changelogBetweenInclFrom, *changelogBetweenExclTo)
+	if err != nil {
+		return fmt.Errorf("Error selecting versions: %v", err)
+	}
+
+	if *jsonOutput {
+		return fprintJSON(w, entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "No entries found between %s and %s\n", *changelogBetweenFrom, *changelogBetweenTo)
+		return nil
+	}
+
+	// The Keep a Changelog sections come first, then any other sections in the
+	// order they appear
+	sections := changelog.ValidSections()
+	bySection := map[string][]string{}
+	for _, name := range sections {
+		bySection[name] = nil
+	}
+	for _, m := range entries {
+		if _, ok := bySection[m.Section]; !ok {
+			sections = append(sections, m.Section)
+		}
+		bySection[m.Section] = append(bySection[m.Section], "- "+m.Entry)
+	}
+	var lines []string
+	for _, section := range sections {
+		if len(bySection[section]) > 0 {
+			lines = append(lines, "### "+section, "")
+			lines = append(lines, bySection[section]...)
+			lines = append(lines, "")
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(strings.Join(lines, "\n"), "\n"))
+	return nil
+}
+
+// tagListOutput is the JSON output of the tag list command
+type tagListOutput struct {
+	Tags    []string `json:"tags"`
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+func handleTagList(w io.Writer, gitManager GitManager) error {
+	tags, err := gitManager.ListTags()
+	if err != nil {
+		return fmt.Errorf("Error listing tags: %v", err)
+	}
+
+	versions := []string{}
+	var invalid []string
+	for _, tag := range tags {
+		if _, err := semver.ParseVersion(tag); err != nil {
+			invalid = append(invalid, tag)
+			continue
+		}
+		versions = append(versions, tag)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		result, _ := semver.Compare(versions[i], versions[j])
+		return result > 0
+	})
+	if *tagListLimit > 0 && len(versions) > *tagListLimit {
+		versions = versions[:*tagListLimit]
+	}
+	if !*tagListAll {
+		invalid = nil
+	}
+
+	if *jsonOutput {
+		return fprintJSON(w, tagListOutput{Tags: versions, Invalid: invalid})
+	}
+
+	for _, version := range versions {
+		fmt.Fprintln(w, version)
+	}
+	if len(invalid) > 0 {
+		fmt.Fprintln(w, "\nInvalid version tags:")
+		for _, tag := range invalid {
+			fmt.Fprintln(w, tag)
+		}
+	}
+
+	return nil
+}
+
+func handleTagDelete(gitManager GitManager) error {
+	tag := *tagDeleteVersion
+	exists, err := gitManager.TagExists(tag)
+	if err != nil {
+		return fmt.Errorf("Error checking tag: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("Error: Tag %s does not exist.", tag)
+	}
+
+	remote := ""
+	target := "locally"
+	if *tagDeleteRemote {
+		remote = defaultRemote
+		target = fmt.Sprintf("locally and from %s", remote)
+	}
+
+	if !*tagDeleteYes && !confirm(fmt.Sprintf("Delete tag %s %s?", tag, target)) {
+		fmt.Println("Aborted, no tags were deleted.")
+		return nil
+	}
+
+	if err := gitManager.DeleteTag(tag, remote); err != nil {
+		return fmt.Errorf("Error deleting tag: %v", err)
+	}
+
+	fmt.Printf("Deleted local tag %s\n", tag)
+	if remote != "" {
+		fmt.Printf("Deleted tag %s from %s\n", tag, remote)
+	}
+	return nil
+}
+
+// signatureResult is a checked signature in the output of the tag verify command
+type signatureResult struct {
+	Signed  bool   `json:"signed"`
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"` // Output of the signature check
+}
+
+// tagVerifyOutput is the JSON output of the tag verify command
+type tagVerifyOutput struct {
+	Tag             string           `json:"tag"`
+	Valid           bool             `json:"valid"` // All checked signatures are good
+	TagSignature    signatureResult  `json:"tag_signature"`
+	CommitSignature *signatureResult `json:"commit_signature,omitempty"`
+}
+
+func handleTagVerify(gitManager GitManager) error {
+	tag := *tagVerifyVersion
+	exists, err := gitManager.TagExists(tag)
+	if err != nil {
+		return fmt.Errorf("Error checking tag: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("Error: Tag %s does not exist.", tag)
+	}
+
+	output := tagVerifyOutput{Tag: tag}
+	output.TagSignature, err = checkSignature(gitManager.VerifyTag, tag)
+	if err != nil {
+		return fmt.Errorf("Error verifying tag: %v", err)
+	}
+	output.Valid = output.TagSignature.Valid
+	if *tagVerifyCommit {
+		commit, err := checkSignature(gitManager.VerifyCommit, tag)
+		if err != nil {
+			return fmt.Errorf("Error verifying release commit: %v", err)
+		}
+		output.CommitSignature = &commit
+		output.Valid = output.Valid && commit.Valid
+	}
+
+	if *jsonOutput {
+		if err := printJSON(output); err != nil {
+			return err
+		}
+	} else {
+		printSignature("Tag "+tag, output.TagSignature)
+		if output.CommitSignature != nil {
+			printSignature("The release commit of "+tag, *output.CommitSignature)
+		}
+	}
+
+	if !output.Valid {
+		return fmt.Errorf("Error: The signatures of %s could not be verified.", tag)
+	}
+	return nil
+}
+
+// checkSignature runs a signature check. A missing signature is a result of
+// the check rather than an error.
+func checkSignature(verify func(string) (bool, string, error), ref string) (signatureResult, error) {
+	valid, message, err := verify(ref)
+	if errors.Is(err, git.ErrNotSigned) {
+		return signatureResult{}, nil
+	}
+	if err != nil {
+		return signatureResult{}, err
+	}
+	return signatureResult{Signed: true, Valid: valid, Message: message}, nil
+}
+
+func printSignature(subject string, result signatureResult) {
+	switch {
+	case !result.Signed:
+		fmt.Printf("%s is not signed.\n", subject)
+		return
+	case result.Valid:
+		fmt.Printf("%s has a good signature.\n", subject)
+	default:
+		fmt.Printf("%s has a bad signature.\n", subject)
+	}
+	for _, line := range strings.Split(result.Message, "\n") {
+		if line != "" {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// confirm asks a yes/no question on stdin and reports whether the answer was yes
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// handleBump releases the bump type given as an argument, so scripts can run
+// changie bump "$TYPE", or the version range given with --to
+func handleBump(changelogManager ChangelogManager, gitManager GitManager, semverManager SemverManager) error {
+	switch {
+	case *bumpType != "" && *bumpTo != "":
+		return fmt.Errorf("Error: use either a bump type or --to, not both.")
+	case *bumpTo != "":
+		return handleVersionBump("constraint", changelogManager, gitManager, semverManager)
+	case *bumpType == "major" || *bumpType == "minor" || *bumpType == "patch" || *bumpType == "calver":
+		return handleVersionBump(*bumpType, changelogManager, gitManager, semverManager)
+	case *bumpType == "" && *versionScheme == "calver":
+		return handleVersionBump("calver", changelogManager, gitManager, semverManager)
+	}
+
+	app.UsageWriter(os.Stderr).Usage([]string{"bump"})
+	if *bumpType == "" {
+		return fmt.Errorf("Error: bump requires a bump type (major, minor, patch or calver) or --to.")
+	}
+	return fmt.Errorf("Error: invalid bump type %q, expected major, minor, patch or calver.", *bumpType)
+}
+
+func printJSON(v interface{}) error {
+	return fprintJSON(os.Stdout, v)
+}
+
+func fprintJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding JSON output: %v", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// withOutputFile runs a read command with its output written to --output-file,
+// or to stdout when the flag isn't set. The output is streamed to a temp file
+// next to it, which replaces the file only when the command succeeds, so a
+// failed command leaves an existing file as it was.
+func withOutputFile(f func(w io.Writer) error) error {
+	if *outputFile == "" {
+		return f(os.Stdout)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(*outputFile), "."+filepath.Base(*outputFile)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("Error creating output file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := f(w); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Error writing output file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Error writing output file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("Error creating output file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), *outputFile); err != nil {
+		return fmt.Errorf("Error creating output file: %v", err)
+	}
+	return nil
+}
+
+// requiresRepository reports whether the command reads or writes git tags or
+// commits, so it can't run outside a git repository
+func requiresRepository(command string) bool {
+	switch command {
+	case majorCommand.FullCommand(), minorCommand.FullCommand(), patchCommand.FullCommand(), bumpCommand.FullCommand(),
+		changelogFirstCommand.FullCommand(), changelogCommitsCommand.FullCommand(), changelogCountCommand.FullCommand(),
+		changelogCompareCommand.FullCommand(), tagListCommand.FullCommand(), tagDeleteCommand.FullCommand(), tagVerifyCommand.FullCommand():
+		return true
+	}
+	return false
+}
+
+func run(changelogManager ChangelogManager, gitManager GitManager, semverManager SemverManager) error {
+	logger.Debug("Entering run function")
+
+	if !isGitInstalled() {
+		return fmt.Errorf("Error: Git is not installed.")
+	}
+
+	// Commands that don't use git, such as adding changelog entries, also
+	// work outside a git repository
+	inRepository, err := gitManager.IsRepository()
+	if err != nil {
+		return fmt.Errorf("Error checking for a git repository: %v", err)
+	}
+
+	// Get the git tag
+	version := "dev"
+	if inRepository {
+		version, err = gitManager.GetVersion()
+		logger.Debug("GetVersion result", zap.String("version", version), zap.Error(err))
+		if err != nil {
+			return fmt.Errorf("Error getting project version: %w", err)
+		}
+	}
+	app.Version(version)
+
+	command, err := app.Parse(os.Args[1:])
+	if err != nil {
+		return fmt.Errorf("Error parsing command: %w", err)
+	}
+	if !inRepository && requiresRepository(command) {
+		return fmt.Errorf("Error: Not inside a git repository. changie %s reads git tags or commits, run it in a git working tree.", command)
+	}
+
+	switch command {
+	case initCommand.FullCommand():
+		if err := changelog.ValidateTitle(*initTitle); err != nil {
+			return fmt.Errorf("Error: Invalid --title: %v", err)
+		}
 		log.Printf("Initializing project with changelog file: %s", *changeLogFile)
 		handleError(changelogManager.InitProject(*changeLogFile))
+		if *jsonOutput {
+			return printJSON(initOutput{Success: true, ChangelogFile: *changeLogFile, Created: true})
+		}
 		fmt.Println("Project initialized for SemVer and Keep a Changelog.")
 
 	case majorCommand.FullCommand():
@@ -258,6 +2827,8 @@ func run(changelogManager ChangelogManager, gitManager GitManager, semverManager
 		return handleVersionBump("minor", changelogManager, gitManager, semverManager)
 	case patchCommand.FullCommand():
 		return handleVersionBump("patch", changelogManager, gitManager, semverManager)
+	case bumpCommand.FullCommand():
+		return handleBump(changelogManager, gitManager, semverManager)
 
 	case changelogAddCommand.FullCommand():
 		return handleChangelogUpdate("Added", *changelogAddContent, changelogManager)
@@ -272,6 +2843,107 @@ func run(changelogManager ChangelogManager, gitManager GitManager, semverManager
 	case changelogSecurityCommand.FullCommand():
 		return handleChangelogUpdate("Security", *changelogSecurityContent, changelogManager)
 
+	case changelogWrapCommand.FullCommand():
+		width := *wrapWidth
+		if changelogWrapWidthSet {
+			width = *changelogWrapWidth
+		}
+		return handleChangelogWrap(width, changelogManager)
+
+	case changelogFixDatesCommand.FullCommand():
+		return handleChangelogFixDates(changelogManager)
+
+	case changelogReorderCommand.FullCommand():
+		return handleChangelogReorder(changelogManager)
+
+	case changelogSetCommand.FullCommand():
+		return handleChangelogSetSection(changelogManager)
+
+	case changelogAssembleCommand.FullCommand():
+		return handleChangelogAssemble(changelogManager)
+
+	case changelogDiffCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogDiff(w, changelogManager) })
+
+	case changelogBetweenCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogEntriesBetween(w, changelogManager) })
+
+	case changelogShowCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogShow(w, changelogManager) })
+
+	case changelogFirstCommand.FullCommand():
+		return handleVersionBump("first", changelogManager, gitManager, semverManager)
+
+	case changelogDateCommand.FullCommand():
+		return handleChangelogDate(changelogManager)
+
+	case changelogSectionsCommand.FullCommand():
+		return handleChangelogSections()
+
+	case changelogCommitsCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogCommits(w, gitManager) })
+
+	case changelogCountCommand.FullCommand():
+		return handleChangelogCount(gitManager)
+
+	case changelogValidateCommand.FullCommand():
+		return handleChangelogValidate(changelogManager, gitManager)
+
+	case changelogLintCommand.FullCommand():
+		return handleChangelogLint(changelogManager)
+
+	case changelogFixLinksCommand.FullCommand():
+		return handleChangelogFixLinks(changelogManager)
+
+	case changelogVerifyCommand.FullCommand():
+		return handleChangelogVerifyLinks(changelogManager)
+
+	case changelogMoveCommand.FullCommand():
+		return handleChangelogMoveVersion(changelogManager)
+
+	case changelogArchiveCommand.FullCommand():
+		return handleChangelogArchive(changelogManager)
+
+	case changelogEntriesCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogEntries(w, changelogManager) })
+
+	case changelogGrepCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogGrep(w, changelogManager) })
+
+	case changelogStatsCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogStats(w, changelogManager) })
+
+	case changelogBackupCommand.FullCommand():
+		return handleChangelogBackup(changelogManager)
+
+	case changelogGraphCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogGraph(w, changelogManager) })
+
+	case changelogRenameCommand.FullCommand():
+		return handleChangelogRenameFile(changelogManager)
+
+	case changelogNeedsCommand.FullCommand():
+		return handleChangelogNeedsRelease(changelogManager)
+
+	case changelogCompareCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleChangelogCompareToGit(w, changelogManager, gitManager) })
+
+	case migrateCommand.FullCommand():
+		return handleMigrate(changelogManager)
+
+	case docsCommand.FullCommand():
+		return withOutputFile(handleDocs)
+
+	case versionCommand.FullCommand():
+		return handleVersion()
+
+	case tagListCommand.FullCommand():
+		return withOutputFile(func(w io.Writer) error { return handleTagList(w, gitManager) })
+	case tagDeleteCommand.FullCommand():
+		return handleTagDelete(gitManager)
+	case tagVerifyCommand.FullCommand():
+		return handleTagVerify(gitManager)
+
 	default:
 		return fmt.Errorf("Unknown command: %s", command)
 	}
@@ -282,6 +2954,7 @@ func run(changelogManager ChangelogManager, gitManager GitManager, semverManager
 func main() {
 	// Enable verbose logging
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	logger.Init(logger.INFO, os.Stderr)
 
 	changelogManager := DefaultChangelogManager{}
 	gitManager := DefaultGitManager{}
@@ -2,28 +2,102 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"runtime/debug"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/peiman/changie/internal/changelog"
+	"github.com/peiman/changie/internal/git"
+	"github.com/peiman/changie/internal/logger"
+	"github.com/peiman/changie/internal/semver"
 )
 
+func init() {
+	// The tests expect plain output, also when they run in GitHub Actions
+	githubActions = false
+}
+
 // Mock implementations
 type MockChangelogManager struct {
+	wrapChanged            bool
+	wrapWidth              int
+	reorderChanged         bool
+	reorderAll             bool
+	reorderCheck           bool
+	datesResult            changelog.DatesResult
+	datesFormat            string
+	datesCheck             bool
+	setSection             string
+	setEntries             []string
+	setSectionErr          error
+	replaceMatch           string
+	replaced               bool
+	migrateChanged         bool
+	migrateCheck           bool
+	orphanLinks            []string
+	missingLinks           []string
+	updateOptions          changelog.UpdateOptions // Options of the last UpdateChangelog or FixMissingLinks call
+	linkedContent          string                  // Changelog content after the missing links are added
+	archives               []changelog.Archive
+	archiveYear            int
+	archiveDryRun          bool
+	moveVersionErr         error
+	addedSection           string
+	addedContent           string
+	moved                  []string // Version, target and position of the last MoveVersion call
+	lintResult             changelog.LintResult
+	lintFix                bool
+	lintCheck              bool
+	lintOptions            changelog.LintOptions
 	initProjectErr         error
 	updateChangelogErr     error
 	addChangelogSectionErr error
 	updateChangelogCalled  int
 	isDuplicate            bool
 	changelogContent       string
+	releasedContent        string // Content returned once the changelog was updated
+	unreleasedDate         string
+	backups                []string // Files backed up, in order
+	backupKeep             int
+	backupErr              error
+	renamed                []string // From and to of the last RenameChangelog call
+	renameLinks            int
+	renameErr              error
+}
+
+func (m *MockChangelogManager) RenameChangelog(from, to string) (int, error) {
+	if m.renameErr != nil {
+		return 0, m.renameErr
+	}
+	m.renamed = []string{from, to}
+	return m.renameLinks, nil
+}
+
+func (m *MockChangelogManager) BackupChangelog(file string, keep int) (string, error) {
+	if m.backupErr != nil {
+		return "", m.backupErr
+	}
+	m.backups = append(m.backups, file)
+	m.backupKeep = keep
+	return file + ".bak-20240101-120000.000", nil
 }
 
 func (m *MockChangelogManager) GetChangelogContent() (string, error) {
+	if m.updateChangelogCalled > 0 && m.releasedContent != "" {
+		return m.releasedContent, nil
+	}
 	if m.changelogContent == "" {
 		m.changelogContent = `# Changelog
 
@@ -37,14 +111,87 @@ func (m *MockChangelogManager) GetChangelogContent() (string, error) {
 	return m.changelogContent, nil
 }
 
+func (m *MockChangelogManager) OpenChangelog() (io.ReadCloser, error) {
+	content, err := m.GetChangelogContent()
+	return io.NopCloser(strings.NewReader(content)), err
+}
+
+func (m *MockChangelogManager) SetUnreleasedDate(file, date string) error {
+	m.unreleasedDate = date
+	return nil
+}
+
+func (m *MockChangelogManager) WrapChangelog(file string, width int, check bool) (bool, error) {
+	m.wrapWidth = width
+	return m.wrapChanged, nil
+}
+
+func (m *MockChangelogManager) ReorderChangelog(file string, all, check bool) (bool, error) {
+	m.reorderAll, m.reorderCheck = all, check
+	return m.reorderChanged, nil
+}
+
+func (m *MockChangelogManager) FixDates(file, format string, check bool) (changelog.DatesResult, error) {
+	m.datesFormat, m.datesCheck = format, check
+	return m.datesResult, nil
+}
+
+func (m *MockChangelogManager) SetSectionEntries(file, section string, entries []string) error {
+	m.setSection, m.setEntries = section, entries
+	return m.setSectionErr
+}
+
+func (m *MockChangelogManager) ReplaceOrAddEntry(file, section, match, content string) (bool, error) {
+	m.addedSection, m.addedContent, m.replaceMatch = section, content, match
+	return m.replaced, m.addChangelogSectionErr
+}
+
+func (m *MockChangelogManager) LintChangelog(file string, opts changelog.LintOptions, fix, check bool) (changelog.LintResult, error) {
+	m.lintOptions, m.lintFix, m.lintCheck = opts, fix, check
+	return m.lintResult, nil
+}
+
+func (m *MockChangelogManager) FixLinks(file string) ([]string, error) {
+	return m.orphanLinks, nil
+}
+
+func (m *MockChangelogManager) FixMissingLinks(file string, opts changelog.UpdateOptions) ([]string, error) {
+	m.updateOptions = opts
+	if m.linkedContent != "" {
+		m.changelogContent = m.linkedContent
+	}
+	return m.missingLinks, nil
+}
+
+func (m *MockChangelogManager) MoveVersion(file, version, target string, after bool) error {
+	position := "before"
+	if after {
+		position = "after"
+	}
+	m.moved = []string{version, target, position}
+	return m.moveVersionErr
+}
+
+func (m *MockChangelogManager) ArchiveChangelog(file string, year int, dryRun bool) ([]changelog.Archive, error) {
+	m.archiveYear, m.archiveDryRun = year, dryRun
+	return m.archives, nil
+}
+
+func (m *MockChangelogManager) MigrateChangelog(file string, check bool) (bool, error) {
+	m.migrateCheck = check
+	return m.migrateChanged, nil
+}
+
 func (m *MockChangelogManager) InitProject(string) error {
 	return m.initProjectErr
 }
-func (m *MockChangelogManager) UpdateChangelog(string, string, string) error {
+func (m *MockChangelogManager) UpdateChangelog(file, version string, opts changelog.UpdateOptions) error {
 	m.updateChangelogCalled++
+	m.updateOptions = opts
 	return m.updateChangelogErr
 }
-func (m *MockChangelogManager) AddChangelogSection(string, string, string) (bool, error) {
+func (m *MockChangelogManager) AddChangelogSection(file, section, content string) (bool, error) {
+	m.addedSection, m.addedContent = section, content
 	return m.isDuplicate, m.addChangelogSectionErr
 }
 
@@ -59,6 +206,76 @@ type MockGitManager struct {
 	hasUncommittedChanges bool
 	pushChangesCalled     int
 	pushChangesErr        error
+	createdBranches       []string
+	createBranchErr       error
+	tags                  []string
+	listTagsErr           error
+	deletedTags           []string
+	deleteTagErr          error
+	tagSignature          signatureResult // Result of VerifyTag, unsigned when Signed is false
+	commitSignature       signatureResult // Result of VerifyCommit, unsigned when Signed is false
+	verifyErr             error
+	pushedTags            []string
+	detachedHead          bool
+	shallow               bool
+	shallowErr            error
+	notRepository         bool
+	remoteURL             string
+	defaultBranch         string // Empty when the default branch of the remote is unknown
+	remoteURLErr          error
+	tagMessage            string
+	lastTag               string
+	refs                  []string // Refs that exist besides HEAD and the tags
+	commits               []git.Commit
+	commitRange           string
+}
+
+func (m *MockGitManager) GetRemoteURL(remote string) (string, error) {
+	if m.remoteURLErr != nil {
+		return "", m.remoteURLErr
+	}
+	if m.remoteURL == "" {
+		return "git@github.com:peiman/changie.git", nil
+	}
+	return m.remoteURL, nil
+}
+
+func (m *MockGitManager) IsDetachedHead() (bool, error) {
+	return m.detachedHead, nil
+}
+
+func (m *MockGitManager) IsShallow() (bool, error) {
+	return m.shallow, m.shallowErr
+}
+
+func (m *MockGitManager) IsRepository() (bool, error) {
+	return !m.notRepository, nil
+}
+
+func (m *MockGitManager) GetLastTag() (string, error) {
+	// Without a lastTag, HEAD is on the projectVersion tag, and "dev" means there are no tags
+	if m.lastTag != "" || m.projectVersion == "dev" {
+		return m.lastTag, nil
+	}
+	return m.projectVersion, nil
+}
+
+func (m *MockGitManager) RefExists(ref string) bool {
+	for _, r := range append(append([]string{"HEAD"}, m.tags...), m.refs...) {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockGitManager) GetCommitsBetween(from, to string) ([]git.Commit, error) {
+	m.commitRange = from + ".." + to
+	return m.commits, nil
+}
+
+func (m *MockGitManager) GetHeadCommit() (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
 }
 
 func (m *MockGitManager) CommitChangelog(string, string) error {
@@ -69,6 +286,11 @@ func (m *MockGitManager) TagVersion(version string) error {
 	m.tagVersionCalled++
 	return m.tagVersionErr
 }
+func (m *MockGitManager) TagVersionWithMessage(version, message string) error {
+	m.tagVersionCalled++
+	m.tagMessage = message
+	return m.tagVersionErr
+}
 func (m *MockGitManager) GetVersion() (string, error) {
 	m.getVersionCalled++
 	if m.getVersionErr != nil {
@@ -83,6 +305,56 @@ func (m *MockGitManager) PushChanges() error {
 	m.pushChangesCalled++
 	return m.pushChangesErr
 }
+func (m *MockGitManager) PushTag(remote, tag string) error {
+	m.pushedTags = append(m.pushedTags, remote+"/"+tag)
+	return m.pushChangesErr
+}
+func (m *MockGitManager) TagExists(tag string) (bool, error) {
+	for _, t := range m.tags {
+		if t == tag {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *MockGitManager) DeleteTag(tag, remote string) error {
+	if m.deleteTagErr != nil {
+		return m.deleteTagErr
+	}
+	m.deletedTags = append(m.deletedTags, tag+"@"+remote)
+	return nil
+}
+func (m *MockGitManager) VerifyTag(tag string) (bool, string, error) {
+	return mockSignature(m.tagSignature, m.verifyErr)
+}
+func (m *MockGitManager) VerifyCommit(ref string) (bool, string, error) {
+	return mockSignature(m.commitSignature, m.verifyErr)
+}
+func mockSignature(result signatureResult, err error) (bool, string, error) {
+	if err != nil {
+		return false, "", err
+	}
+	if !result.Signed {
+		return false, "", git.ErrNotSigned
+	}
+	return result.Valid, result.Message, nil
+}
+func (m *MockGitManager) DefaultBranch(remote string) (string, error) {
+	if m.defaultBranch == "" {
+		return "", fmt.Errorf("ref refs/remotes/%s/HEAD is not a symbolic ref", remote)
+	}
+	return m.defaultBranch, nil
+}
+func (m *MockGitManager) ListTags() ([]string, error) {
+	return m.tags, m.listTagsErr
+}
+func (m *MockGitManager) CreateBranch(name string) error {
+	if m.createBranchErr != nil {
+		return m.createBranchErr
+	}
+	m.createdBranches = append(m.createdBranches, name)
+	return nil
+}
 
 type MockSemverManager struct {
 	bumpMajorErr    error
@@ -95,7 +367,7 @@ func (m *MockSemverManager) BumpMajor(version string) (string, error) {
 	if m.bumpMajorErr != nil {
 		return "", m.bumpMajorErr
 	}
-	parts := strings.Split(version, ".")
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
 	major, _ := strconv.Atoi(parts[0])
 	return fmt.Sprintf("%d.0.0", major+1), nil
 }
@@ -105,7 +377,7 @@ func (m *MockSemverManager) BumpMinor(version string) (string, error) {
 	if m.bumpMinorErr != nil {
 		return "", m.bumpMinorErr
 	}
-	parts := strings.Split(version, ".")
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
 	major, _ := strconv.Atoi(parts[0])
 	minor, _ := strconv.Atoi(parts[1])
 	return fmt.Sprintf("%d.%d.0", major, minor+1), nil
@@ -115,13 +387,17 @@ func (m *MockSemverManager) BumpPatch(version string) (string, error) {
 	if m.bumpPatchErr != nil {
 		return "", m.bumpPatchErr
 	}
-	parts := strings.Split(version, ".")
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
 	major, _ := strconv.Atoi(parts[0])
 	minor, _ := strconv.Atoi(parts[1])
 	patch, _ := strconv.Atoi(parts[2])
 	return fmt.Sprintf("%d.%d.%d", major, minor, patch+1), nil
 }
 
+func (m *MockSemverManager) BumpToConstraint(version, constraint string) (string, error) {
+	return semver.BumpToConstraint(version, constraint)
+}
+
 func captureOutput(t *testing.T, f func() error) (string, error) {
 	oldStdout := os.Stdout
 	oldStderr := os.Stderr
@@ -142,6 +418,37 @@ func captureOutput(t *testing.T, f func() error) (string, error) {
 	return buf.String(), err
 }
 
+// captureStreams is like captureOutput, but captures stdout and stderr separately
+func captureStreams(t *testing.T, f func() error) (string, string, error) {
+	oldStdout := os.Stdout
+	oldStderr := os.Stderr
+	rOut, wOut, _ := os.Pipe()
+	rErr, wErr, _ := os.Pipe()
+	os.Stdout = wOut
+	os.Stderr = wErr
+
+	// Read stderr concurrently so a full pipe doesn't block the command
+	errCh := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, rErr)
+		errCh <- buf.String()
+	}()
+
+	err := f()
+
+	wOut.Close()
+	wErr.Close()
+	os.Stdout = oldStdout
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rOut); err != nil {
+		t.Fatalf("Failed to copy output: %v", err)
+	}
+	return buf.String(), <-errCh, err
+}
+
 func TestMainPackage(t *testing.T) {
 	isTestMode = true
 	defer func() { isTestMode = false }()
@@ -337,6 +644,10 @@ func TestGitNotInstalled(t *testing.T) {
 
 	os.Args = []string{"changie", "major"}
 
+	var logOutput bytes.Buffer
+	logger.Init(logger.DEBUG, &logOutput)
+	defer logger.Init(logger.INFO, io.Discard)
+
 	output, err := captureOutput(t, func() error {
 		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
 	})
@@ -348,8 +659,11 @@ func TestGitNotInstalled(t *testing.T) {
 		t.Errorf("Expected error %q, but got: %v", expectedError, err)
 	}
 
-	if !strings.Contains(output, "Debug: Entering run function") {
-		t.Errorf("Expected output to contain debug information, but got: %q", output)
+	if !strings.Contains(logOutput.String(), "Entering run function") {
+		t.Errorf("Expected the debug log to contain the run entry, but got: %q", logOutput.String())
+	}
+	if strings.Contains(output, "Debug") {
+		t.Errorf("Expected no debug information in the output, but got: %q", output)
 	}
 }
 
@@ -359,6 +673,10 @@ func TestInvalidCommand(t *testing.T) {
 
 	os.Args = []string{"changie", "invalid"}
 
+	var logOutput bytes.Buffer
+	logger.Init(logger.DEBUG, &logOutput)
+	defer logger.Init(logger.INFO, io.Discard)
+
 	output, err := captureOutput(t, func() error {
 		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
 	})
@@ -383,8 +701,11 @@ func TestInvalidCommand(t *testing.T) {
 		}
 	}
 
-	if !strings.Contains(output, "Debug: Entering run function") {
-		t.Errorf("Expected output to contain debug information, but got: %q", output)
+	if !strings.Contains(logOutput.String(), "Entering run function") {
+		t.Errorf("Expected the debug log to contain the run entry, but got: %q", logOutput.String())
+	}
+	if strings.Contains(output, "Debug") {
+		t.Errorf("Expected no debug information in the output, but got: %q", output)
 	}
 }
 
@@ -690,35 +1011,5156 @@ func TestVersionBumpWithExistingTag(t *testing.T) {
 }
 
 func TestVersionMismatchWarning(t *testing.T) {
-	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
-	mockChangelogManager := &MockChangelogManager{
-		changelogContent: "## [2.0.0]",
+	tests := []struct {
+		name           string
+		projectVersion string
+		lastTag        string
+		expectedError  string
+	}{
+		{"Mismatch", "1.0.0", "1.0.0", "Version mismatch: Git tag version 1.0.0 does not match changelog version 2.0.0"},
+		{"Commits after the tag", "2.0.0-dev.1+be78306", "2.0.0", ""},
+		{"Prefixed tag", "v2.0.0", "v2.0.0", ""},
+		{"No tag", "dev", "", "Version mismatch: No git tag found for changelog version 2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGitManager := &MockGitManager{projectVersion: tt.projectVersion, lastTag: tt.lastTag}
+			mockChangelogManager := &MockChangelogManager{
+				changelogContent: "## [2.0.0]",
+			}
+
+			output, err := captureOutput(t, func() error {
+				return checkVersionMismatch(mockGitManager, mockChangelogManager)
+			})
+
+			if tt.expectedError == "" && err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+				t.Errorf("Expected error %q, but got: %v", tt.expectedError, err)
+			}
+			// The mismatch is only reported through the error
+			if output != "" {
+				t.Errorf("Expected no output, but got: %q", output)
+			}
+		})
 	}
+}
+
+func TestSkipChangelogOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *skipChangelog = false }()
+
+	os.Args = []string{"changie", "patch", "--no-changelog"}
+
+	mockGitManager := &MockGitManager{projectVersion: "0.1.0"}
+	mockChangelogManager := &MockChangelogManager{changelogContent: "## [0.0.9] - 2023-01-01"}
+	mockSemverManager := &MockSemverManager{}
 
-	// Test with printWarning = true
 	output, err := captureOutput(t, func() error {
-		return checkVersionMismatch(mockGitManager, mockChangelogManager, true)
+		return run(mockChangelogManager, mockGitManager, mockSemverManager)
 	})
 
-	if err == nil {
-		t.Error("Expected an error, but got nil")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expectedOutputs := []string{
+		"Warning: Skipping changelog update. No release commit will be created, only the tag.",
+		"Tagging version: 0.1.1",
+		"patch release 0.1.1 done.",
+	}
+	for _, expectedOutput := range expectedOutputs {
+		if !strings.Contains(output, expectedOutput) {
+			t.Errorf("Expected output to contain '%s', got: '%s'", expectedOutput, output)
+		}
 	}
 
-	expectedWarning := "Warning: Version mismatch: Git tag version 1.0.0 does not match changelog version 2.0.0"
-	if !strings.Contains(output, expectedWarning) {
-		t.Errorf("Expected warning %q, but got output: %q", expectedWarning, output)
+	if mockChangelogManager.updateChangelogCalled != 0 {
+		t.Errorf("Expected UpdateChangelog not to be called, got: %d", mockChangelogManager.updateChangelogCalled)
 	}
 
-	// Test with printWarning = false
-	output, err = captureOutput(t, func() error {
-		return checkVersionMismatch(mockGitManager, mockChangelogManager, false)
+	if mockGitManager.commitChangelogCalled != 0 {
+		t.Errorf("Expected CommitChangelog not to be called, got: %d", mockGitManager.commitChangelogCalled)
+	}
+
+	if mockGitManager.tagVersionCalled != 1 {
+		t.Errorf("Expected TagVersion to be called once, got: %d", mockGitManager.tagVersionCalled)
+	}
+}
+
+func TestChangelogUpdateWithEmoji(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*useEmoji = false
+		*sectionEmoji = nil
+	}()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Default emoji",
+			args:     []string{"changie", "changelog", "fixed", "Crash on start", "--emoji"},
+			expected: "Fixed section: 🐛 Crash on start\n",
+		},
+		{
+			name:     "Custom emoji",
+			args:     []string{"changie", "changelog", "added", "New feature", "--emoji", "--section-emoji", "Added=🎉"},
+			expected: "Added section: 🎉 New feature\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestReleaseBranchOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *releaseBranch = false }()
+
+	os.Args = []string{"changie", "minor", "--release-branch"}
+
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+	mockChangelogManager := &MockChangelogManager{}
+
+	output, err := captureOutput(t, func() error {
+		return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
 	})
 
-	if err == nil {
-		t.Error("Expected an error, but got nil")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Creating release branch: release/1.1.0") {
+		t.Errorf("Expected output to mention the release branch, got: %q", output)
+	}
+	if len(mockGitManager.createdBranches) != 1 || mockGitManager.createdBranches[0] != "release/1.1.0" {
+		t.Errorf("Expected release/1.1.0 to be created, got: %v", mockGitManager.createdBranches)
+	}
+
+	// An existing branch aborts the release before the changelog is touched
+	mockGitManager = &MockGitManager{projectVersion: "1.0.0", createBranchErr: fmt.Errorf("branch release/1.1.0 already exists")}
+	mockChangelogManager = &MockChangelogManager{}
+
+	_, err = captureOutput(t, func() error {
+		return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "Error creating release branch: branch release/1.1.0 already exists") {
+		t.Errorf("Expected release branch error, got: %v", err)
+	}
+	if mockChangelogManager.updateChangelogCalled != 0 || mockGitManager.tagVersionCalled != 0 {
+		t.Error("Changelog was updated or version tagged despite release branch error")
+	}
+}
+
+func TestTagList(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	mockGitManager := &MockGitManager{
+		projectVersion: "1.0.0",
+		tags:           []string{"0.9.0", "0.10.0", "nightly", "v1.0.0", "0.2.0"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Semantic order",
+			args:     []string{"changie", "tag", "list"},
+			expected: "v1.0.0\n0.10.0\n0.9.0\n0.2.0\n",
+		},
+		{
+			name:     "Limit",
+			args:     []string{"changie", "tag", "list", "--limit", "2"},
+			expected: "v1.0.0\n0.10.0\n",
+		},
+		{
+			name:     "All tags",
+			args:     []string{"changie", "tag", "list", "--all", "--limit", "1"},
+			expected: "v1.0.0\n\nInvalid version tags:\nnightly\n",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "tag", "list", "--json", "--limit", "2"},
+			expected: "{\n  \"tags\": [\n    \"v1.0.0\",\n    \"0.10.0\"\n  ]\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*tagListAll = false
+			*jsonOutput = false
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogRenameFile(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *changeLogFile = "CHANGELOG.md" }()
+
+	tests := []struct {
+		name            string
+		args            []string
+		manager         *MockChangelogManager
+		expected        string
+		expectedRenamed []string
+		expectedError   string
+	}{
+		{
+			name:            "Rename",
+			args:            []string{"changie", "changelog", "rename-file", "HISTORY.md"},
+			manager:         &MockChangelogManager{},
+			expected:        "Renamed CHANGELOG.md to HISTORY.md.\nUse --file HISTORY.md with changie from now on.\n",
+			expectedRenamed: []string{"CHANGELOG.md", "HISTORY.md"},
+		},
+		{
+			name:            "Links updated",
+			args:            []string{"changie", "changelog", "rename-file", "HISTORY.md", "--file", "docs/CHANGES.md"},
+			manager:         &MockChangelogManager{renameLinks: 2},
+			expected:        "Renamed docs/CHANGES.md to HISTORY.md.\nUpdated 2 links to the changelog file.\nUse --file HISTORY.md with changie from now on.\n",
+			expectedRenamed: []string{"docs/CHANGES.md", "HISTORY.md"},
+		},
+		{
+			name:          "Same name",
+			args:          []string{"changie", "changelog", "rename-file", "./CHANGELOG.md"},
+			manager:       &MockChangelogManager{},
+			expectedError: "Error: The changelog is already named ./CHANGELOG.md.",
+		},
+		{
+			name:          "Target exists",
+			args:          []string{"changie", "changelog", "rename-file", "HISTORY.md"},
+			manager:       &MockChangelogManager{renameErr: fmt.Errorf("HISTORY.md already exists")},
+			expectedError: "Error renaming changelog: HISTORY.md already exists",
+		},
 	}
 
-	if output != "" {
-		t.Errorf("Expected no output, but got: %q", output)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changeLogFile = "CHANGELOG.md"
+
+			output, err := captureOutput(t, func() error {
+				return run(tt.manager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+			if !reflect.DeepEqual(tt.manager.renamed, tt.expectedRenamed) {
+				t.Errorf("Expected rename %v, got: %v", tt.expectedRenamed, tt.manager.renamed)
+			}
+		})
+	}
+}
+
+func TestChangelogNeedsRelease(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature B
+
+### Fixed
+
+- Fix B
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Feature A
+`
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Unreleased entries",
+			args:     []string{"changie", "changelog", "needs-release"},
+			content:  content,
+			expected: "Release needed: CHANGELOG.md has 2 unreleased entries.\n",
+		},
+		{
+			name:          "No unreleased entries",
+			args:          []string{"changie", "changelog", "needs-release"},
+			content:       "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n\n- Feature A\n",
+			expectedError: "Error: No release needed: CHANGELOG.md has 0 unreleased entries, 1 needed.",
+		},
+		{
+			name:          "No Unreleased section",
+			args:          []string{"changie", "changelog", "needs-release"},
+			content:       "# Changelog\n",
+			expectedError: "Error: No release needed: CHANGELOG.md has 0 unreleased entries, 1 needed.",
+		},
+		{
+			name:          "Below the minimum",
+			args:          []string{"changie", "changelog", "needs-release", "--min-entries", "3"},
+			content:       content,
+			expectedError: "Error: No release needed: CHANGELOG.md has 2 unreleased entries, 3 needed.",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "needs-release", "--json", "--min-entries", "2"},
+			content:  content,
+			expected: "{\n  \"needs_release\": true,\n  \"entries\": 2,\n  \"min_entries\": 2\n}\n",
+		},
+		{
+			name:          "JSON output without a release",
+			args:          []string{"changie", "changelog", "needs-release", "--json", "--min-entries", "3"},
+			content:       content,
+			expected:      "{\n  \"needs_release\": false,\n  \"entries\": 2,\n  \"min_entries\": 3\n}\n",
+			expectedError: "Error: No release needed: CHANGELOG.md has 2 unreleased entries, 3 needed.",
+		},
+		{
+			name:          "Invalid minimum",
+			args:          []string{"changie", "changelog", "needs-release", "--min-entries", "0"},
+			content:       content,
+			expectedError: "Error: --min-entries must be at least 1.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogCompareToGit(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*strict = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [v1.2.0] - 2024-03-01
+
+## [1.1.0] - 2024-02-01
+
+## [1.0.0] - 2024-01-01
+`
+	tests := []struct {
+		name          string
+		args          []string
+		tags          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "In sync",
+			args: []string{"changie", "changelog", "compare-to-git"},
+			tags: []string{"1.0.0", "v1.1.0", "1.2.0", "nightly"},
+			expected: `VERSION  TAG     CHANGELOG
+1.2.0    1.2.0   v1.2.0
+1.1.0    v1.1.0  1.1.0
+1.0.0    1.0.0   1.0.0
+
+The version tags and CHANGELOG.md are in sync.
+`,
+		},
+		{
+			name: "Differences",
+			args: []string{"changie", "changelog", "compare-to-git"},
+			tags: []string{"v1.0.0", "v1.2.0", "v1.3.0"},
+			expected: `VERSION  TAG      CHANGELOG
+1.3.0    v1.3.0   missing
+1.2.0    v1.2.0   v1.2.0
+1.1.0    missing  1.1.0
+1.0.0    v1.0.0   1.0.0
+
+Tag v1.3.0 has no section in CHANGELOG.md.
+Version 1.1.0 in CHANGELOG.md has no tag.
+`,
+		},
+		{
+			name:          "Strict",
+			args:          []string{"changie", "changelog", "compare-to-git", "--strict"},
+			tags:          []string{"v1.0.0", "v1.2.0", "v1.3.0"},
+			expectedError: "Error: Found 2 differences between the version tags and CHANGELOG.md.",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "compare-to-git", "--json"},
+			tags:     []string{"v1.0.0", "v1.1.0"},
+			expected: `{"versions":[{"version":"1.2.0","changelog":"v1.2.0"},{"version":"1.1.0","tag":"v1.1.0","changelog":"1.1.0"},{"version":"1.0.0","tag":"v1.0.0","changelog":"1.0.0"}],"missing_in_changelog":[],"missing_tags":["v1.2.0"],"in_sync":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*strict = false
+			mockGitManager := &MockGitManager{projectVersion: "1.2.0", tags: tt.tags}
+
+			stdout, _, err := captureStreams(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if *jsonOutput {
+				var compact bytes.Buffer
+				if err := json.Compact(&compact, []byte(stdout)); err != nil {
+					t.Fatalf("Invalid JSON output %q: %v", stdout, err)
+				}
+				stdout = compact.String()
+			}
+			if stdout != tt.expected {
+				t.Errorf("Expected output:\n%s\ngot:\n%s", tt.expected, stdout)
+			}
+		})
+	}
+}
+
+func TestTagVerify(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*tagVerifyCommit = false
+	}()
+
+	good := signatureResult{Signed: true, Valid: true, Message: "Good \"git\" signature for dev@example.com"}
+	bad := signatureResult{Signed: true, Message: "gpg: BAD signature"}
+
+	tests := []struct {
+		name            string
+		args            []string
+		tagSignature    signatureResult
+		commitSignature signatureResult
+		verifyErr       error
+		expected        string
+		expectedError   string
+	}{
+		{
+			name:         "Good tag signature",
+			args:         []string{"changie", "tag", "verify", "1.0.0"},
+			tagSignature: good,
+			expected:     "Tag 1.0.0 has a good signature.\n  Good \"git\" signature for dev@example.com\n",
+		},
+		{
+			name:          "Unsigned tag",
+			args:          []string{"changie", "tag", "verify", "1.0.0"},
+			expected:      "Tag 1.0.0 is not signed.\n",
+			expectedError: "Error: The signatures of 1.0.0 could not be verified.",
+		},
+		{
+			name:          "Bad tag signature",
+			args:          []string{"changie", "tag", "verify", "1.0.0"},
+			tagSignature:  bad,
+			expected:      "Tag 1.0.0 has a bad signature.\n  gpg: BAD signature\n",
+			expectedError: "Error: The signatures of 1.0.0 could not be verified.",
+		},
+		{
+			name:          "Unsigned release commit",
+			args:          []string{"changie", "tag", "verify", "1.0.0", "--commit"},
+			tagSignature:  good,
+			expected:      "Tag 1.0.0 has a good signature.\n  Good \"git\" signature for dev@example.com\nThe release commit of 1.0.0 is not signed.\n",
+			expectedError: "Error: The signatures of 1.0.0 could not be verified.",
+		},
+		{
+			name:            "JSON output",
+			args:            []string{"changie", "tag", "verify", "1.0.0", "--commit", "--json"},
+			tagSignature:    good,
+			commitSignature: good,
+			expected:        "{\n  \"tag\": \"1.0.0\",\n  \"valid\": true,\n  \"tag_signature\": {\n    \"signed\": true,\n    \"valid\": true,\n    \"message\": \"Good \\\"git\\\" signature for dev@example.com\"\n  },\n  \"commit_signature\": {\n    \"signed\": true,\n    \"valid\": true,\n    \"message\": \"Good \\\"git\\\" signature for dev@example.com\"\n  }\n}\n",
+		},
+		{
+			name:          "Unknown tag",
+			args:          []string{"changie", "tag", "verify", "9.9.9"},
+			expectedError: "Error: Tag 9.9.9 does not exist.",
+		},
+		{
+			name:          "Git error",
+			args:          []string{"changie", "tag", "verify", "1.0.0"},
+			verifyErr:     fmt.Errorf("git failed"),
+			expectedError: "Error verifying tag: git failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*tagVerifyCommit = false
+			mockGitManager := &MockGitManager{
+				projectVersion:  "1.0.0",
+				tags:            []string{"1.0.0"},
+				tagSignature:    tt.tagSignature,
+				commitSignature: tt.commitSignature,
+				verifyErr:       tt.verifyErr,
+			}
+
+			stdout, _, err := captureStreams(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if stdout != tt.expected {
+				t.Errorf("Expected output %q, got: %q", tt.expected, stdout)
+			}
+		})
+	}
+}
+
+func TestTagDelete(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	oldStdin := stdin
+	defer func() { stdin = oldStdin }()
+
+	tests := []struct {
+		name            string
+		args            []string
+		input           string
+		expectedOutput  string
+		expectedError   string
+		expectedDeleted []string
+	}{
+		{
+			name:            "Delete local tag with confirmation",
+			args:            []string{"changie", "tag", "delete", "1.0.0"},
+			input:           "y\n",
+			expectedOutput:  "Delete tag 1.0.0 locally? [y/N] Deleted local tag 1.0.0\n",
+			expectedDeleted: []string{"1.0.0@"},
+		},
+		{
+			name:            "Delete remote tag without confirmation",
+			args:            []string{"changie", "tag", "delete", "1.0.0", "--remote", "--yes"},
+			expectedOutput:  "Deleted local tag 1.0.0\nDeleted tag 1.0.0 from origin\n",
+			expectedDeleted: []string{"1.0.0@origin"},
+		},
+		{
+			name:           "Declined confirmation",
+			args:           []string{"changie", "tag", "delete", "1.0.0", "--remote"},
+			input:          "n\n",
+			expectedOutput: "Delete tag 1.0.0 locally and from origin? [y/N] Aborted, no tags were deleted.\n",
+		},
+		{
+			name:          "Missing tag",
+			args:          []string{"changie", "tag", "delete", "2.0.0", "--yes"},
+			expectedError: "Error: Tag 2.0.0 does not exist.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*tagDeleteRemote = false
+			*tagDeleteYes = false
+			stdin = strings.NewReader(tt.input)
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0", tags: []string{"1.0.0"}}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expectedOutput) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expectedOutput, output)
+			}
+			if strings.Join(mockGitManager.deletedTags, ",") != strings.Join(tt.expectedDeleted, ",") {
+				t.Errorf("Expected deleted tags %v, got: %v", tt.expectedDeleted, mockGitManager.deletedTags)
+			}
+		})
+	}
+}
+
+func TestPushTagsOnlyAfterBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *tagsOnly = false }()
+
+	os.Args = []string{"changie", "patch", "--tags-only"}
+
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Automatically pushed tag 1.0.1 to origin. Commits were not pushed.") {
+		t.Errorf("Expected output to report the pushed tag, got: %q", output)
+	}
+	if mockGitManager.pushChangesCalled != 0 {
+		t.Errorf("Expected PushChanges not to be called, got: %d", mockGitManager.pushChangesCalled)
+	}
+	if len(mockGitManager.pushedTags) != 1 || mockGitManager.pushedTags[0] != "origin/1.0.1" {
+		t.Errorf("Expected tag 1.0.1 to be pushed to origin, got: %v", mockGitManager.pushedTags)
+	}
+}
+
+func TestReleaseLinkOptions(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	*autoPush = false
+	os.Args = []string{"changie", "minor"}
+	mockChangelogManager := &MockChangelogManager{}
+	mockGitManager := &MockGitManager{
+		projectVersion: "1.0.0",
+		remoteURL:      "https://dev.azure.com/org/project/_git/repo",
+		defaultBranch:  "develop",
+		tags:           []string{"v1.0.0"},
+	}
+
+	_, err := captureOutput(t, func() error {
+		return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	opts := mockChangelogManager.updateOptions
+	if opts.Provider != "azure" || opts.RepositoryURL != "https://dev.azure.com/org/project/_git/repo" || opts.DefaultBranch != "develop" {
+		t.Errorf("Expected the links to use the repository of the git manager, got: %+v", opts)
+	}
+	if expected := map[string]string{"1.0.0": "v1.0.0", "1.1.0": "1.1.0"}; !reflect.DeepEqual(opts.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, opts.Tags)
+	}
+}
+
+func TestExpectedRemoteURL(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*autoPush = false
+		*expectedRemoteURL = ""
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		remoteURL     string
+		expectedError string
+		expectedPush  int
+	}{
+		{
+			name:         "HTTPS URL matches SSH remote",
+			args:         []string{"changie", "patch", "--auto-push", "--expected-remote-url", "https://github.com/peiman/changie"},
+			remoteURL:    "git@github.com:peiman/changie.git",
+			expectedPush: 1,
+		},
+		{
+			name:          "Fork remote",
+			args:          []string{"changie", "patch", "--auto-push", "--expected-remote-url", "https://github.com/peiman/changie.git"},
+			remoteURL:     "git@github.com:someone/changie.git",
+			expectedError: "Error: The origin remote is git@github.com:someone/changie.git, not the expected https://github.com/peiman/changie.git. Nothing was released.",
+		},
+		{
+			name:      "Not checked without pushing",
+			args:      []string{"changie", "patch", "--expected-remote-url", "https://github.com/peiman/changie.git"},
+			remoteURL: "git@github.com:someone/changie.git",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*autoPush = false
+			*expectedRemoteURL = ""
+			os.Args = tt.args
+
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0", remoteURL: tt.remoteURL}
+
+			_, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Errorf("Expected no tag to be created, got: %d", mockGitManager.tagVersionCalled)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if mockGitManager.pushChangesCalled != tt.expectedPush {
+				t.Errorf("Expected PushChanges to be called %d times, got: %d", tt.expectedPush, mockGitManager.pushChangesCalled)
+			}
+		})
+	}
+}
+
+func TestChangelogWrap(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*wrapWidth = 0
+		*changelogWrapCheck = false
+		changelogWrapWidthSet = false
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		changed       bool
+		expectedWidth int
+		expected      string
+		expectedError string
+	}{
+		{
+			name:          "Wrap at configured width",
+			args:          []string{"changie", "changelog", "--wrap-width", "80", "wrap"},
+			changed:       true,
+			expectedWidth: 80,
+			expected:      "Wrapped entries in CHANGELOG.md at 80 columns.\n",
+		},
+		{
+			name:          "Width flag overrides wrap width",
+			args:          []string{"changie", "changelog", "--wrap-width", "80", "wrap", "--width", "0"},
+			expectedWidth: 0,
+			expected:      "Entries in CHANGELOG.md are already wrapped at 0 columns.\n",
+		},
+		{
+			name:          "Check passes",
+			args:          []string{"changie", "changelog", "wrap", "--width", "72", "--check"},
+			expectedWidth: 72,
+			expected:      "CHANGELOG.md is wrapped at 72 columns.\n",
+		},
+		{
+			name:          "Check fails",
+			args:          []string{"changie", "changelog", "wrap", "--width", "72", "--check"},
+			changed:       true,
+			expectedWidth: 72,
+			expectedError: "Error: CHANGELOG.md is not wrapped at 72 columns. Run changie changelog wrap to fix it.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogWrapCheck = false
+			changelogWrapWidthSet = false
+			mockChangelogManager := &MockChangelogManager{wrapChanged: tt.changed, wrapWidth: -1}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if mockChangelogManager.wrapWidth != tt.expectedWidth {
+				t.Errorf("Expected width %d, got %d", tt.expectedWidth, mockChangelogManager.wrapWidth)
+			}
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogFixDates(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogFixDatesFormat = changelog.DefaultDateFormat
+		*changelogFixDatesCheck = false
+	}()
+
+	fixes := changelog.DatesResult{Fixes: []string{"[1.0.0] 2024-1-1 -> 2024-01-01"}, Warnings: []string{`line 9: can't parse the date "soon" of [0.9.0]`}}
+	tests := []struct {
+		name           string
+		args           []string
+		result         changelog.DatesResult
+		expectedFormat string
+		expectedBackup bool
+		expected       string
+		expectedError  string
+	}{
+		{
+			name:           "Fix dates",
+			args:           []string{"changie", "changelog", "fix-dates"},
+			result:         fixes,
+			expectedFormat: "2006-01-02",
+			expectedBackup: true,
+			expected:       "Fixed: [1.0.0] 2024-1-1 -> 2024-01-01\nWarning: line 9: can't parse the date \"soon\" of [0.9.0]\nFixed 1 dates in CHANGELOG.md.\n",
+		},
+		{
+			name:           "Custom format",
+			args:           []string{"changie", "changelog", "fix-dates", "--format", "02.01.2006"},
+			expectedFormat: "02.01.2006",
+			expectedBackup: true,
+			expected:       "The dates in CHANGELOG.md are in the 02.01.2006 format.\n",
+		},
+		{
+			name:           "Check passes",
+			args:           []string{"changie", "changelog", "fix-dates", "--check"},
+			expectedFormat: "2006-01-02",
+			expected:       "The dates in CHANGELOG.md are in the 2006-01-02 format.\n",
+		},
+		{
+			name:           "Check fails",
+			args:           []string{"changie", "changelog", "fix-dates", "--check"},
+			result:         fixes,
+			expectedFormat: "2006-01-02",
+			expected:       "Would fix: [1.0.0] 2024-1-1 -> 2024-01-01\nWarning: line 9: can't parse the date \"soon\" of [0.9.0]\n",
+			expectedError:  "Error: CHANGELOG.md has 1 dates that aren't in the 2006-01-02 format. Run changie changelog fix-dates to fix them.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogFixDatesFormat = changelog.DefaultDateFormat
+			*changelogFixDatesCheck = false
+			mockChangelogManager := &MockChangelogManager{datesResult: tt.result}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if mockChangelogManager.datesFormat != tt.expectedFormat {
+				t.Errorf("Expected format %q, got %q", tt.expectedFormat, mockChangelogManager.datesFormat)
+			}
+			if backedUp := len(mockChangelogManager.backups) > 0; backedUp != tt.expectedBackup {
+				t.Errorf("Expected backup %v, got backups: %v", tt.expectedBackup, mockChangelogManager.backups)
+			}
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogReorderSections(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogReorderAll = false
+		*changelogReorderCheck = false
+	}()
+
+	tests := []struct {
+		name           string
+		args           []string
+		changed        bool
+		expectedAll    bool
+		expectedBackup bool
+		expected       string
+		expectedError  string
+	}{
+		{
+			name:           "Reorder Unreleased",
+			args:           []string{"changie", "changelog", "reorder-sections"},
+			changed:        true,
+			expectedBackup: true,
+			expected:       "Reordered the sections in CHANGELOG.md.\n",
+		},
+		{
+			name:           "Reorder all versions",
+			args:           []string{"changie", "changelog", "reorder-sections", "--all"},
+			expectedAll:    true,
+			expectedBackup: true,
+			expected:       "The sections in CHANGELOG.md are already in the Keep a Changelog order.\n",
+		},
+		{
+			name:     "Check passes",
+			args:     []string{"changie", "changelog", "reorder-sections", "--check"},
+			expected: "The sections in CHANGELOG.md are in the Keep a Changelog order.\n",
+		},
+		{
+			name:          "Check fails",
+			args:          []string{"changie", "changelog", "reorder-sections", "--check"},
+			changed:       true,
+			expectedError: "Error: The sections in CHANGELOG.md are not in the Keep a Changelog order. Run changie changelog reorder-sections to fix it.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogReorderAll = false
+			*changelogReorderCheck = false
+			mockChangelogManager := &MockChangelogManager{reorderChanged: tt.changed}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if mockChangelogManager.reorderAll != tt.expectedAll {
+				t.Errorf("Expected all %v, got %v", tt.expectedAll, mockChangelogManager.reorderAll)
+			}
+			if backedUp := len(mockChangelogManager.backups) > 0; backedUp != tt.expectedBackup {
+				t.Errorf("Expected backup %v, got backups: %v", tt.expectedBackup, mockChangelogManager.backups)
+			}
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogSetSection(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogSetEntries = nil
+		*changelogSetRemoveEmpty = false
+	}()
+
+	tests := []struct {
+		name            string
+		args            []string
+		setSectionErr   error
+		expectedSection string
+		expectedEntries []string
+		expected        string
+		expectedError   string
+	}{
+		{
+			name:            "Replace entries",
+			args:            []string{"changie", "changelog", "set-section", "added", "Feature A", "Feature B"},
+			expectedSection: "Added",
+			expectedEntries: []string{"Feature A", "Feature B"},
+			expected:        "Set the Added section of Unreleased in CHANGELOG.md to 2 entries.\n",
+		},
+		{
+			name:            "Remove empty section",
+			args:            []string{"changie", "changelog", "set-section", "Fixed", "--remove-empty"},
+			expectedSection: "Fixed",
+			expected:        "Removed the Fixed section from Unreleased in CHANGELOG.md.\n",
+		},
+		{
+			name:          "No entries without --remove-empty",
+			args:          []string{"changie", "changelog", "set-section", "Fixed"},
+			expectedError: "Error: No entries given for the Fixed section. Use --remove-empty to remove the section.",
+		},
+		{
+			name:            "Invalid section",
+			args:            []string{"changie", "changelog", "set-section", "Notes", "Note"},
+			setSectionErr:   fmt.Errorf("unknown section Notes"),
+			expectedSection: "Notes",
+			expectedEntries: []string{"Note"},
+			expectedError:   "Error setting changelog section: unknown section Notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogSetEntries = nil
+			*changelogSetRemoveEmpty = false
+			mockChangelogManager := &MockChangelogManager{setSectionErr: tt.setSectionErr}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if mockChangelogManager.setSection != tt.expectedSection {
+				t.Errorf("Expected section %q, got %q", tt.expectedSection, mockChangelogManager.setSection)
+			}
+			if entries := mockChangelogManager.setEntries; (len(entries) > 0 || len(tt.expectedEntries) > 0) && !reflect.DeepEqual(entries, tt.expectedEntries) {
+				t.Errorf("Expected entries %v, got %v", tt.expectedEntries, mockChangelogManager.setEntries)
+			}
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogSections(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*sectionEmoji = map[string]string{}
+	}()
+
+	*sectionEmoji = map[string]string{}
+	os.Args = []string{"changie", "changelog", "--section-emoji", "Fixed=🚑️", "sections"}
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	for _, expected := range []string{
+		"\nAdded       emoji ✨  aliases: added\n",
+		"\nFixed       emoji 🚑️  aliases: fixed\n",
+		"\nSecurity    emoji 🔒️  aliases: security\n",
+	} {
+		if !strings.Contains("\n"+output, expected) {
+			t.Errorf("Expected output to contain %q, got: %q", expected, output)
+		}
+	}
+
+	*sectionEmoji = map[string]string{}
+	os.Args = []string{"changie", "changelog", "sections", "--json"}
+	output, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	expected := "{\n    \"name\": \"Added\",\n    \"emoji\": \"✨\",\n    \"aliases\": [\n      \"added\"\n    ]\n  },"
+	if !strings.HasPrefix(output, "[\n  "+expected) {
+		t.Errorf("Expected JSON output to start with %q, got: %q", expected, output)
+	}
+}
+
+func TestChangelogCommits(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*commitsSince = ""
+		*jsonOutput = false
+	}()
+
+	commits := []git.Commit{
+		{Hash: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Subject: "Merge branch 'feature'", Merge: true},
+		{Hash: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Subject: "feat: add feature"},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		lastTag       string
+		commits       []git.Commit
+		expectedRange string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:          "Since latest tag",
+			args:          []string{"changie", "changelog", "commits"},
+			lastTag:       "1.1.0",
+			commits:       commits,
+			expectedRange: "1.1.0..HEAD",
+			expected:      "bbbbbbb Merge branch 'feature'\naaaaaaa feat: add feature\n",
+		},
+		{
+			name:          "No tags",
+			args:          []string{"changie", "changelog", "commits"},
+			expectedRange: "..HEAD",
+			expected:      "No commits found up to HEAD\n",
+		},
+		{
+			name:          "Since and until",
+			args:          []string{"changie", "changelog", "--since", "1.0.0", "--until", "release/1.1", "commits"},
+			lastTag:       "1.1.0",
+			expectedRange: "1.0.0..release/1.1",
+			expected:      "No commits found between 1.0.0 and release/1.1\n",
+		},
+		{
+			name:          "JSON output",
+			args:          []string{"changie", "changelog", "commits", "--json"},
+			lastTag:       "1.1.0",
+			commits:       commits[1:],
+			expectedRange: "1.1.0..HEAD",
+			expected:      "[\n  {\n    \"hash\": \"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\",\n    \"subject\": \"feat: add feature\",\n    \"merge\": false\n  }\n]\n",
+		},
+		{
+			name:          "Unknown since",
+			args:          []string{"changie", "changelog", "--since", "0.9.0", "commits"},
+			expectedError: "Error: --since 0.9.0 does not exist.",
+		},
+		{
+			name:          "Unknown until",
+			args:          []string{"changie", "changelog", "--until", "missing", "commits"},
+			expectedError: "Error: --until missing does not exist.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*commitsSince = ""
+			*jsonOutput = false
+			mockGitManager := &MockGitManager{projectVersion: "dev", lastTag: tt.lastTag, tags: []string{"1.0.0", "1.1.0"}, refs: []string{"release/1.1"}, commits: tt.commits}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if mockGitManager.commitRange != tt.expectedRange {
+				t.Errorf("Expected range %q, got %q", tt.expectedRange, mockGitManager.commitRange)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogCountSince(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*commitsSince = ""
+		*jsonOutput = false
+		*changelogCountByType = false
+		*changelogCountMerges = false
+	}()
+
+	commits := []git.Commit{
+		{Hash: "e", Subject: "Merge pull request #12 from feature", Merge: true},
+		{Hash: "d", Subject: "feat(cli)!: add count-since"},
+		{Hash: "c", Subject: "fix: handle empty ranges"},
+		{Hash: "b", Subject: "Feat: add feature"},
+		{Hash: "a", Subject: "Update README"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		lastTag  string
+		expected string
+	}{
+		{
+			name:     "Since latest tag",
+			args:     []string{"changie", "changelog", "count-since"},
+			lastTag:  "1.1.0",
+			expected: "4 commits since 1.1.0\n",
+		},
+		{
+			name:     "No previous tag",
+			args:     []string{"changie", "changelog", "count-since"},
+			expected: "4 commits, no previous tag found\n",
+		},
+		{
+			name:     "By type with merges",
+			args:     []string{"changie", "changelog", "count-since", "--by-type", "--include-merges"},
+			lastTag:  "1.1.0",
+			expected: "5 commits since 1.1.0\n  feat: 2\n  other: 2\n  fix: 1\n",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "count-since", "--json"},
+			lastTag:  "1.1.0",
+			expected: "{\n  \"since\": \"1.1.0\",\n  \"total\": 4,\n  \"by_type\": {\n    \"feat\": 2,\n    \"fix\": 1,\n    \"other\": 1\n  }\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*changelogCountByType = false
+			*changelogCountMerges = false
+			mockGitManager := &MockGitManager{projectVersion: "dev", lastTag: tt.lastTag, commits: commits}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestOutsideRepository(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{"Adding an entry", []string{"changie", "changelog", "added", "New feature"}, ""},
+		{"Validating", []string{"changie", "changelog", "validate"}, ""},
+		{"Release", []string{"changie", "minor"}, "Error: Not inside a git repository. changie minor reads git tags or commits, run it in a git working tree."},
+		{"First release", []string{"changie", "changelog", "first-release"}, "Error: Not inside a git repository. changie changelog first-release reads git tags or commits, run it in a git working tree."},
+		{"Tag list", []string{"changie", "tag", "list"}, "Error: Not inside a git repository. changie tag list reads git tags or commits, run it in a git working tree."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*autoPush = false
+			mockGitManager := &MockGitManager{notRepository: true, getVersionErr: fmt.Errorf("not a git repository")}
+
+			_, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: "# Changelog\n\n## [Unreleased]\n"}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+			}
+			if mockGitManager.tagVersionCalled != 0 {
+				t.Error("Expected no tag outside a repository")
+			}
+		})
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*versionBuildInfo = false
+		buildVersion, buildCommit, buildDate = "", "", ""
+	}()
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	tests := []struct {
+		name     string
+		args     []string
+		version  string
+		commit   string
+		expected string
+	}{
+		{"Local build", []string{"changie", "version"}, "", "", "changie dev\n"},
+		{"Release build", []string{"changie", "version"}, "1.2.3", "abc1234", "changie 1.2.3\n"},
+		{
+			"Build info",
+			[]string{"changie", "version", "--build-info"},
+			"1.2.3",
+			"abc1234",
+			"changie 1.2.3\nCommit:     abc1234\nBuilt:      unknown\nGo version: " + runtime.Version() + "\nPlatform:   " + platform + "\n",
+		},
+		{
+			"JSON output",
+			[]string{"changie", "version", "--json"},
+			"1.2.3",
+			"",
+			fmt.Sprintf("{\n  \"version\": \"1.2.3\",\n  \"go_version\": %q,\n  \"os\": %q,\n  \"arch\": %q\n}\n", runtime.Version(), runtime.GOOS, runtime.GOARCH),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*versionBuildInfo = false
+			buildVersion, buildCommit, buildDate = tt.version, tt.commit, ""
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestGitHubAnnotation(t *testing.T) {
+	tests := []struct {
+		level    string
+		file     string
+		line     int
+		message  string
+		expected string
+	}{
+		{"error", "CHANGELOG.md", 3, "duplicate version", "::error file=CHANGELOG.md,line=3::duplicate version"},
+		{"warning", "CHANGELOG.md", 0, "entry is long", "::warning file=CHANGELOG.md::entry is long"},
+		{"error", "docs/a,b:c.md", 1, "100% done\nnext", "::error file=docs/a%2Cb%3Ac.md,line=1::100%25 done%0Anext"},
+	}
+
+	for _, tt := range tests {
+		if got := githubAnnotation(tt.level, tt.file, tt.line, tt.message); got != tt.expected {
+			t.Errorf("githubAnnotation(%q, %q, %d, %q) = %q, want %q", tt.level, tt.file, tt.line, tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestChangelogValidate(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogValidateStrict = false
+		*changelogValidateLinks = false
+		*changelogValidateFix = false
+		maxUnreleasedSet = false
+		githubAnnotationsSet = false
+		githubActions = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix A
+
+### Added
+
+- Feature A
+
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		githubActions bool
+		missingLinks  []string
+		linkedContent string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Valid",
+			args:     []string{"changie", "changelog", "validate"},
+			content:  content,
+			expected: "CHANGELOG.md is valid.\n",
+		},
+		{
+			name:          "Duplicate version",
+			args:          []string{"changie", "changelog", "validate"},
+			content:       content + "\n## [1.0.0] - 2024-01-01\n",
+			expected:      "Error: line 17: duplicate version header [1.0.0] on lines 5, 17\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+		{
+			name:          "Keep a Changelog strict",
+			args:          []string{"changie", "changelog", "validate", "--keepachangelog-strict"},
+			content:       content,
+			expected:      "Error: line 1: the header doesn't have the introduction \"All notable changes to this project will be documented in this file.\" [KAC-INTRO]\nError: line 3: version [Unreleased] has no link [KAC-LINK]\nError: line 11: section Added in [1.0.0] is out of order [KAC-ORDER]\n",
+			expectedError: "Error: CHANGELOG.md has 3 validation issues.",
+		},
+		{
+			name:     "Compliant",
+			args:     []string{"changie", "changelog", "validate", "--keepachangelog-strict"},
+			content:  "# Changelog\n\nAll notable changes to this project will be documented in this file.\n\n## [Unreleased]\n\n[Unreleased]: https://github.com/peiman/changie/commits/HEAD\n",
+			expected: "CHANGELOG.md follows Keep a Changelog 1.1.0.\n",
+		},
+		{
+			name:     "Unreleased within the maximum",
+			args:     []string{"changie", "changelog", "validate", "--max-unreleased", "2"},
+			content:  strings.Replace(content, "## [Unreleased]\n", "## [Unreleased]\n\n### Added\n\n- Feature B\n\n### Fixed\n\n- Fix B\n", 1),
+			expected: "CHANGELOG.md is valid.\n",
+		},
+		{
+			name:          "Too many unreleased entries",
+			args:          []string{"changie", "changelog", "validate", "--max-unreleased", "1"},
+			content:       strings.Replace(content, "## [Unreleased]\n", "## [Unreleased]\n\n### Added\n\n- Feature B\n\n### Fixed\n\n- Fix B\n", 1),
+			expected:      "Error: line 3: [Unreleased] has 2 entries, more than the maximum of 1, consider releasing\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+		{
+			name:          "Too many unreleased entries in strict mode",
+			args:          []string{"changie", "changelog", "validate", "--keepachangelog-strict", "--max-unreleased", "0"},
+			content:       "# Changelog\n\nAll notable changes to this project will be documented in this file.\n\n## [Unreleased]\n\n### Added\n\n- Feature A\n\n[Unreleased]: https://github.com/peiman/changie/commits/HEAD\n",
+			expected:      "Error: line 5: [Unreleased] has 1 entries, more than the maximum of 0, consider releasing\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+		{
+			name:          "Missing links",
+			args:          []string{"changie", "changelog", "validate", "--require-links"},
+			content:       content,
+			expected:      "Error: line 3: version [Unreleased] has no link [KAC-LINK]\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+		{
+			name:          "Missing links in strict mode are reported once",
+			args:          []string{"changie", "changelog", "validate", "--keepachangelog-strict", "--require-links"},
+			content:       content,
+			expected:      "Error: line 3: version [Unreleased] has no link [KAC-LINK]\nError: line 11: section Added in [1.0.0] is out of order [KAC-ORDER]\n",
+			expectedError: "Error: CHANGELOG.md has 3 validation issues.",
+		},
+		{
+			name:          "Fix missing links",
+			args:          []string{"changie", "changelog", "validate", "--fix"},
+			content:       content,
+			missingLinks:  []string{"Unreleased"},
+			linkedContent: content + "[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD\n",
+			expected:      "Added missing link for [Unreleased]\nCHANGELOG.md is valid.\n",
+		},
+		{
+			name:          "GitHub annotations",
+			args:          []string{"changie", "changelog", "validate", "--keepachangelog-strict", "--github"},
+			content:       content,
+			expected:      "::error file=CHANGELOG.md,line=1::the header doesn't have the introduction \"All notable changes to this project will be documented in this file.\" [KAC-INTRO]\n::error file=CHANGELOG.md,line=3::version [Unreleased] has no link [KAC-LINK]\n::error file=CHANGELOG.md,line=11::section Added in [1.0.0] is out of order [KAC-ORDER]\n",
+			expectedError: "Error: CHANGELOG.md has 3 validation issues.",
+		},
+		{
+			name:          "GitHub Actions detected",
+			args:          []string{"changie", "changelog", "validate"},
+			content:       content + "\n## [1.0.0] - 2024-01-01\n",
+			githubActions: true,
+			expected:      "::error file=CHANGELOG.md,line=17::duplicate version header [1.0.0] on lines 5, 17\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+		{
+			name:          "GitHub Actions with plain output",
+			args:          []string{"changie", "changelog", "validate", "--no-github"},
+			content:       content + "\n## [1.0.0] - 2024-01-01\n",
+			githubActions: true,
+			expected:      "Error: line 17: duplicate version header [1.0.0] on lines 5, 17\n",
+			expectedError: "Error: CHANGELOG.md has 1 validation issues.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogValidateStrict = false
+			*changelogValidateLinks = false
+			*changelogValidateFix = false
+			maxUnreleasedSet = false
+			githubAnnotationsSet = false
+			githubActions = tt.githubActions
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content, missingLinks: tt.missingLinks, linkedContent: tt.linkedContent}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogLint(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogLintFix = false
+		*changelogLintCheck = false
+	}()
+
+	fixes := []string{`[Unreleased] Added: capitalized "new feature"`}
+	warnings := []string{`[Unreleased] Added: "Adding things" starts with "Adding", use the past tense or imperative mood`}
+
+	tests := []struct {
+		name          string
+		args          []string
+		result        changelog.LintResult
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "No issues",
+			args:     []string{"changie", "changelog", "lint"},
+			expected: "No issues found in CHANGELOG.md.\n",
+		},
+		{
+			name:          "Issues",
+			args:          []string{"changie", "changelog", "lint"},
+			result:        changelog.LintResult{Fixes: fixes, Warnings: warnings},
+			expected:      "Issue: " + fixes[0] + "\nWarning: " + warnings[0] + "\n",
+			expectedError: "Error: CHANGELOG.md has 1 issues. Run changie changelog lint --fix to fix them.",
+		},
+		{
+			name:     "Only warnings",
+			args:     []string{"changie", "changelog", "lint"},
+			result:   changelog.LintResult{Warnings: warnings},
+			expected: "Warning: " + warnings[0] + "\nNo issues found in CHANGELOG.md.\n",
+		},
+		{
+			name:     "Fix",
+			args:     []string{"changie", "changelog", "lint", "--fix", "--punctuation", "none"},
+			result:   changelog.LintResult{Fixes: fixes},
+			expected: "Fixed: " + fixes[0] + "\nFixed 1 issues in CHANGELOG.md.\n",
+		},
+		{
+			name:          "Fix check",
+			args:          []string{"changie", "changelog", "lint", "--fix", "--check"},
+			result:        changelog.LintResult{Fixes: fixes},
+			expected:      "Would fix: " + fixes[0] + "\n",
+			expectedError: "Error: CHANGELOG.md has 1 issues. Run changie changelog lint --fix to fix them.",
+		},
+		{
+			name:          "Errors",
+			args:          []string{"changie", "changelog", "lint", "--fix"},
+			result:        changelog.LintResult{Errors: []string{"line 9: duplicate version header [1.0.0] on lines 3, 9"}},
+			expected:      "Error: line 9: duplicate version header [1.0.0] on lines 3, 9\n",
+			expectedError: "Error: CHANGELOG.md has 1 issues that can't be fixed automatically.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogLintFix = false
+			*changelogLintCheck = false
+			mockChangelogManager := &MockChangelogManager{lintResult: tt.result}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogFixLinks(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		removed  []string
+		expected string
+	}{
+		{
+			name:     "Removes links",
+			args:     []string{"changie", "changelog", "fix-links"},
+			removed:  []string{"1.2.0", "1.1.1"},
+			expected: "Removed link to nonexistent version 1.2.0\nRemoved link to nonexistent version 1.1.1\nRemoved 2 links from CHANGELOG.md.\n",
+		},
+		{
+			name:     "Nothing to remove",
+			args:     []string{"changie", "changelog", "fix-links"},
+			expected: "No links to nonexistent versions found in CHANGELOG.md.\n",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "fix-links", "--json"},
+			removed:  []string{"1.2.0"},
+			expected: "{\n  \"removed\": [\n    \"1.2.0\"\n  ]\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{orphanLinks: tt.removed}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogMoveVersion(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogMoveBefore = ""
+		*changelogMoveAfter = ""
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		moveErr       error
+		expectedMove  []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:         "After",
+			args:         []string{"changie", "changelog", "move-version", "1.0.1", "--after", "1.1.0"},
+			expectedMove: []string{"1.0.1", "1.1.0", "after"},
+			expected:     "Moved 1.0.1 after 1.1.0 in CHANGELOG.md.\n",
+		},
+		{
+			name:         "Before",
+			args:         []string{"changie", "changelog", "move-version", "1.1.0", "--before", "1.0.1"},
+			expectedMove: []string{"1.1.0", "1.0.1", "before"},
+			expected:     "Moved 1.1.0 before 1.0.1 in CHANGELOG.md.\n",
+		},
+		{
+			name:          "No position",
+			args:          []string{"changie", "changelog", "move-version", "1.1.0"},
+			expectedError: "Error: use either --before or --after to give the new position.",
+		},
+		{
+			name:          "Both positions",
+			args:          []string{"changie", "changelog", "move-version", "1.1.0", "--before", "1.0.1", "--after", "1.0.0"},
+			expectedError: "Error: use either --before or --after to give the new position.",
+		},
+		{
+			name:          "Missing version",
+			args:          []string{"changie", "changelog", "move-version", "2.0.0", "--after", "1.0.0"},
+			moveErr:       fmt.Errorf("version 2.0.0 not found"),
+			expectedMove:  []string{"2.0.0", "1.0.0", "after"},
+			expectedError: "Error moving version: version 2.0.0 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogMoveBefore = ""
+			*changelogMoveAfter = ""
+			mockCM := &MockChangelogManager{moveVersionErr: tt.moveErr}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockCM, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			} else if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+			if strings.Join(mockCM.moved, " ") != strings.Join(tt.expectedMove, " ") {
+				t.Errorf("Expected move %v, got %v", tt.expectedMove, mockCM.moved)
+			}
+		})
+	}
+}
+
+func TestChangelogBackup(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*backupEnabled = true
+		*backupKeep = 5
+		*changelogMoveAfter = ""
+		*changelogArchiveDryRun = false
+		*changelogWrapCheck = false
+		changelogWrapWidthSet = false
+	}()
+
+	tests := []struct {
+		name            string
+		args            []string
+		backupErr       error
+		expectedBackups int
+		expectedKeep    int
+		expected        string
+		expectedError   string
+	}{
+		{
+			name:            "Before moving a version",
+			args:            []string{"changie", "changelog", "move-version", "1.0.1", "--after", "1.1.0", "--backup-keep", "3"},
+			expectedBackups: 1,
+			expectedKeep:    3,
+			expected:        "Backed up CHANGELOG.md to CHANGELOG.md.bak-20240101-120000.000\nMoved 1.0.1 after 1.1.0 in CHANGELOG.md.\n",
+		},
+		{
+			name:     "Skipped with --no-backup",
+			args:     []string{"changie", "changelog", "move-version", "1.0.1", "--after", "1.1.0", "--no-backup"},
+			expected: "Moved 1.0.1 after 1.1.0 in CHANGELOG.md.\n",
+		},
+		{
+			name:     "Not on a dry run",
+			args:     []string{"changie", "changelog", "archive", "--dry-run"},
+			expected: "No releases before " + strconv.Itoa(time.Now().Year()) + " to archive in CHANGELOG.md.\n",
+		},
+		{
+			name:     "Not on a check",
+			args:     []string{"changie", "changelog", "wrap", "--check", "--width", "80"},
+			expected: "CHANGELOG.md is wrapped at 80 columns.\n",
+		},
+		{
+			name:          "Failed backup aborts the command",
+			args:          []string{"changie", "changelog", "fix-links"},
+			backupErr:     fmt.Errorf("permission denied"),
+			expectedError: "Error backing up changelog: permission denied",
+		},
+		{
+			name:            "Backup command",
+			args:            []string{"changie", "changelog", "backup", "--no-backup"},
+			expectedBackups: 1,
+			expectedKeep:    5,
+			expected:        "Backed up CHANGELOG.md to CHANGELOG.md.bak-20240101-120000.000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*backupEnabled = true
+			*backupKeep = 5
+			*changelogMoveAfter = ""
+			*changelogArchiveDryRun = false
+			*changelogWrapCheck = false
+			changelogWrapWidthSet = false
+			mockChangelogManager := &MockChangelogManager{backupErr: tt.backupErr}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if strings.Contains(output, "links") {
+					t.Errorf("Expected the command not to run, got: %q", output)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if len(mockChangelogManager.backups) != tt.expectedBackups || mockChangelogManager.backupKeep != tt.expectedKeep {
+				t.Errorf("Expected %d backups keeping %d, got: %v keeping %d", tt.expectedBackups, tt.expectedKeep, mockChangelogManager.backups, mockChangelogManager.backupKeep)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogArchive(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*changelogArchiveDryRun = false
+	}()
+
+	year := time.Now().Year()
+	archives := []changelog.Archive{
+		{Year: 2023, File: "CHANGELOG-2023.md", Versions: []string{"1.1.0", "1.0.1"}},
+		{Year: 2022, File: "CHANGELOG-2022.md", Versions: []string{"1.0.0"}},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		archives []changelog.Archive
+		dryRun   bool
+		expected string
+	}{
+		{
+			name:     "Archive",
+			args:     []string{"changie", "changelog", "archive", "--by", "year"},
+			archives: archives,
+			expected: fmt.Sprintf("Moved 1.1.0, 1.0.1 to CHANGELOG-2023.md\nMoved 1.0.0 to CHANGELOG-2022.md\nArchived releases before %d from CHANGELOG.md.\n", year),
+		},
+		{
+			name:     "Dry run",
+			args:     []string{"changie", "changelog", "archive", "--dry-run"},
+			archives: archives,
+			dryRun:   true,
+			expected: "Would move 1.1.0, 1.0.1 to CHANGELOG-2023.md\nWould move 1.0.0 to CHANGELOG-2022.md\n",
+		},
+		{
+			name:     "Nothing to archive",
+			args:     []string{"changie", "changelog", "archive"},
+			expected: fmt.Sprintf("No releases before %d to archive in CHANGELOG.md.\n", year),
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "archive", "--json", "--dry-run"},
+			archives: archives[1:],
+			dryRun:   true,
+			expected: "{\n  \"dry_run\": true,\n  \"archives\": [\n    {\n      \"year\": 2022,\n      \"file\": \"CHANGELOG-2022.md\",\n      \"versions\": [\n        \"1.0.0\"\n      ]\n    }\n  ]\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*changelogArchiveDryRun = false
+			mockCM := &MockChangelogManager{archives: tt.archives}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockCM, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if mockCM.archiveYear != year || mockCM.archiveDryRun != tt.dryRun {
+				t.Errorf("Expected archive before %d with dry run %v, got %d and %v", year, tt.dryRun, mockCM.archiveYear, mockCM.archiveDryRun)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *migrateCheck = false }()
+
+	tests := []struct {
+		name          string
+		args          []string
+		changed       bool
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Migrate header",
+			args:     []string{"changie", "migrate"},
+			changed:  true,
+			expected: "Updated the header of CHANGELOG.md to Keep a Changelog 1.1.0.\n",
+		},
+		{
+			name:     "Up to date",
+			args:     []string{"changie", "migrate"},
+			expected: "The header of CHANGELOG.md is up to date.\n",
+		},
+		{
+			name:     "Check passes",
+			args:     []string{"changie", "migrate", "--check"},
+			expected: "The header of CHANGELOG.md is up to date.\n",
+		},
+		{
+			name:          "Check fails",
+			args:          []string{"changie", "migrate", "--check"},
+			changed:       true,
+			expectedError: "Error: The header of CHANGELOG.md is outdated. Run changie migrate to update it.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*migrateCheck = false
+			mockChangelogManager := &MockChangelogManager{migrateChanged: tt.changed}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogAssemble(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *changelogAssembleDelete = false }()
+
+	dir, err := os.MkdirTemp("", "changie-fragments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{"2.fixed.md": "Crash on start\n", "1.added.md": "New feature\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.Args = []string{"changie", "changelog", "assemble", "--dir", dir, "--delete"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := fmt.Sprintf("Added section: New feature\nFixed section: Crash on start\nDeleted 2 changelog fragments from %s\n", dir)
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf("Expected output to end with %q, got: %q", expected, output)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("Expected fragments to be deleted, found %d files", len(files))
+	}
+}
+
+func TestChangelogAssembleJSONOutput(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	dir, err := os.MkdirTemp("", "changie-fragments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "1.added.md"), []byte("New feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"changie", "changelog", "assemble", "--dir", dir, "--json"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{isDuplicate: true}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := `{
+  "entries": [
+    {
+      "success": true,
+      "section": "Added",
+      "content": "New feature",
+      "changelog_file": "CHANGELOG.md",
+      "duplicate": true
+    }
+  ],
+  "deleted": 0
+}
+`
+	if !strings.HasSuffix("\n"+output, "\n"+expected) {
+		t.Errorf("Expected output to end with %q, got: %q", expected, output)
+	}
+}
+
+func TestExtraCommitFilesOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *extraCommitFiles = nil }()
+
+	versionFile, err := os.CreateTemp("", "VERSION")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(versionFile.Name())
+
+	os.Args = []string{"changie", "patch", "--add", versionFile.Name()}
+
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Staging additional files: "+versionFile.Name()) {
+		t.Errorf("Expected output to report the staged file, got: %q", output)
+	}
+
+	*extraCommitFiles = nil
+	os.Args = []string{"changie", "patch", "--add", "does-not-exist.txt"}
+	mockGitManager = &MockGitManager{projectVersion: "1.0.0"}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err == nil || err.Error() != "Error: Additional commit file does-not-exist.txt does not exist." {
+		t.Errorf("Expected missing file error, got: %v", err)
+	}
+	if mockGitManager.commitChangelogCalled != 0 {
+		t.Error("Changelog was committed despite a missing additional file")
+	}
+
+	// Without a release commit there is nothing to add the files to
+	*extraCommitFiles = nil
+	defer func() { *skipChangelog = false }()
+	os.Args = []string{"changie", "patch", "--no-changelog", "--add", versionFile.Name()}
+	mockGitManager = &MockGitManager{projectVersion: "1.0.0"}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err == nil || err.Error() != "Error: --add can't be used with --no-changelog, no release commit is created to add the files to." {
+		t.Errorf("Expected --no-changelog error, got: %v", err)
+	}
+	if mockGitManager.tagVersionCalled != 0 {
+		t.Error("Version was tagged despite --add with --no-changelog")
+	}
+}
+
+func TestDetachedHeadOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *releaseBranch = false }()
+
+	os.Args = []string{"changie", "patch"}
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0", detachedHead: true}
+
+	_, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	expectedErr := "Error: You are in detached HEAD state. Please checkout a branch before releasing."
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error %q, got: %v", expectedErr, err)
+	}
+	if mockGitManager.tagVersionCalled != 0 {
+		t.Error("Version was tagged in detached HEAD state")
+	}
+
+	os.Args = []string{"changie", "patch", "--release-branch"}
+	mockGitManager = &MockGitManager{projectVersion: "1.0.0", detachedHead: true}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected release branch to be created from detached HEAD, got: %v", err)
+	}
+	if len(mockGitManager.createdBranches) != 1 {
+		t.Errorf("Expected a release branch to be created, got: %v", mockGitManager.createdBranches)
+	}
+}
+
+func TestChangelogEntryMetadata(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*entryMeta = map[string]string{}
+		*changelogDiffIncludeFrom = false
+	}()
+
+	content := `# Changelog
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Fix login [severity=high, team=auth]
+- Fix typo
+
+## [1.0.0] - 2024-01-01
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Add with metadata",
+			args:     []string{"changie", "changelog", "fixed", "Fix login", "--meta", "severity=high", "--meta", "team=auth"},
+			expected: "Fixed section: Fix login [severity=high, team=auth]\n",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "fixed", "Fix login", "--meta", "severity=high", "--json"},
+			expected: "{\n  \"success\": true,\n  \"section\": \"Fixed\",\n  \"content\": \"Fix login [severity=high]\",\n  \"changelog_file\": \"CHANGELOG.md\",\n  \"duplicate\": false,\n  \"metadata\": {\n    \"severity\": \"high\"\n  }\n}\n",
+		},
+		{
+			name:          "Invalid metadata",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--meta", "severity=[high]"},
+			expectedError: "Error: invalid metadata value \"[high]\" for severity",
+		},
+		{
+			name: "Metadata in diff-versions",
+			args: []string{"changie", "changelog", "diff-versions", "1.0.0", "1.1.0", "--json"},
+			expected: `[
+  {
+    "version": "1.1.0",
+    "date": "2024-02-01",
+    "sections": [
+      {
+        "name": "Fixed",
+        "entries": [
+          "Fix login [severity=high, team=auth]",
+          "Fix typo"
+        ],
+        "metadata": [
+          {
+            "severity": "high",
+            "team": "auth"
+          },
+          {}
+        ]
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*entryMeta = map[string]string{}
+			*sectionEmoji = map[string]string{}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogEntryTemplate(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*entryTemplates = map[string]string{}
+		*entryIssue = ""
+		*useEmoji = false
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Template of the section",
+			args:     []string{"changie", "changelog", "fixed", "Fix login", "--issue", "42", "--entry-template", "Fixed={{.Text}} (fixes #{{.Issue}})"},
+			expected: "Fix login (fixes #42)",
+		},
+		{
+			name:     "Optional issue",
+			args:     []string{"changie", "changelog", "added", "Add export", "--entry-template", "Added={{.Text}}{{if .Issue}} (#{{.Issue}}){{end}}"},
+			expected: "Add export",
+		},
+		{
+			name:     "Section without a template",
+			args:     []string{"changie", "changelog", "changed", "Change defaults", "--issue", "42", "--entry-template", "Fixed={{.Text}} (fixes #{{.Issue}})"},
+			expected: "Change defaults",
+		},
+		{
+			name:     "Template before emoji",
+			args:     []string{"changie", "changelog", "fixed", "Fix login", "--issue", "42", "--entry-template", "Fixed={{.Text}} (fixes #{{.Issue}})", "--emoji"},
+			expected: "🐛 Fix login (fixes #42)",
+		},
+		{
+			name:          "Unknown section",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--entry-template", "Bugs={{.Text}}"},
+			expectedError: "Error: Invalid entry template: unknown section Bugs",
+		},
+		{
+			name:          "Unknown field",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--entry-template", "Fixed={{.Title}}"},
+			expectedError: "Error: Invalid entry template: error rendering Fixed entry template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*entryTemplates = map[string]string{}
+			*entryIssue = ""
+			*useEmoji = false
+			changelogManager := &MockChangelogManager{}
+
+			_, err := captureOutput(t, func() error {
+				return run(changelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+					t.Errorf("Expected error starting with %q, got: %v", tt.expectedError, err)
+				}
+				if changelogManager.addedContent != "" {
+					t.Errorf("Expected no entry to be added, got: %q", changelogManager.addedContent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if changelogManager.addedContent != tt.expected {
+				t.Errorf("Expected entry %q, got: %q", tt.expected, changelogManager.addedContent)
+			}
+		})
+	}
+}
+
+func TestChangelogReplaceEntry(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*replaceEntry = false
+		*replaceMatch = ""
+		*entryMeta = map[string]string{}
+		*jsonOutput = false
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		replaced      bool
+		expectedMatch string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:          "Replace the same entry",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--replace", "--meta", "severity=high"},
+			replaced:      true,
+			expectedMatch: "Fix login [severity=high]",
+			expected:      "Fixed section: Fix login [severity=high] (replaced existing entry)\n",
+		},
+		{
+			name:          "Replace the old wording",
+			args:          []string{"changie", "changelog", "fixed", "Fix login for long names", "--replace", "--match", "Fix login"},
+			replaced:      true,
+			expectedMatch: "Fix login",
+			expected:      "Fixed section: Fix login for long names (replaced existing entry)\n",
+		},
+		{
+			name:          "Added when nothing matches",
+			args:          []string{"changie", "changelog", "fixed", "Fix logout", "--replace"},
+			expectedMatch: "Fix logout",
+			expected:      "Fixed section: Fix logout\n",
+		},
+		{
+			name:          "JSON output",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--replace", "--json"},
+			replaced:      true,
+			expectedMatch: "Fix login",
+			expected:      "{\n  \"success\": true,\n  \"section\": \"Fixed\",\n  \"content\": \"Fix login\",\n  \"changelog_file\": \"CHANGELOG.md\",\n  \"duplicate\": false,\n  \"replaced\": true\n}\n",
+		},
+		{
+			name:          "Match without replace",
+			args:          []string{"changie", "changelog", "fixed", "Fix login", "--match", "Fix"},
+			expectedError: "Error: --match is only used with --replace.",
+		},
+		{
+			name:          "Match with assemble",
+			args:          []string{"changie", "changelog", "assemble", "--replace", "--match", "Fix"},
+			expectedError: "Error: --match applies to a single entry and can't be used with assemble.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*replaceEntry = false
+			*replaceMatch = ""
+			*entryMeta = map[string]string{}
+			*jsonOutput = false
+			changelogManager := &MockChangelogManager{replaced: tt.replaced}
+
+			output, err := captureOutput(t, func() error {
+				return run(changelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if changelogManager.replaceMatch != tt.expectedMatch {
+				t.Errorf("Expected match %q, got: %q", tt.expectedMatch, changelogManager.replaceMatch)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogDiffVersions(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogDiffIncludeFrom = false
+		*jsonOutput = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-03-01
+
+### Added
+
+- Feature C
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Fix B
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Feature A
+`
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Exclusive range",
+			args:     []string{"changie", "changelog", "diff-versions", "1.0.0", "1.2.0"},
+			expected: "## [1.2.0] - 2024-03-01\n\n### Added\n\n- Feature C\n\n## [1.1.0] - 2024-02-01\n\n### Fixed\n\n- Fix B\n",
+		},
+		{
+			name:     "Inclusive range",
+			args:     []string{"changie", "changelog", "diff-versions", "1.0.0", "1.0.0", "--include-from"},
+			expected: "## [1.0.0] - 2024-01-01\n\n### Added\n\n- Feature A\n",
+		},
+		{
+			name:     "Empty range",
+			args:     []string{"changie", "changelog", "diff-versions", "1.2.0", "1.3.0"},
+			expected: "No releases found between 1.2.0 and 1.3.0\n",
+		},
+		{
+			name: "JSON output",
+			args: []string{"changie", "changelog", "diff-versions", "1.1.0", "1.2.0", "--json"},
+			expected: `[
+  {
+    "version": "1.2.0",
+    "date": "2024-03-01",
+    "sections": [
+      {
+        "name": "Added",
+        "entries": [
+          "Feature C"
+        ]
+      }
+    ]
+  }
+]
+`,
+		},
+		{
+			name:    "Reversed range",
+			args:    []string{"changie", "changelog", "diff-versions", "1.2.0", "1.0.0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*changelogDiffIncludeFrom = false
+			*jsonOutput = false
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogEntriesBetweenTags(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogBetweenInclFrom = false
+		*changelogBetweenExclTo = false
+		*jsonOutput = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-03-01
+
+### Added
+
+- Feature C
+
+### Notes
+
+- Custom section
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Fix B
+
+### Added
+
+- Feature B
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Feature A
+`
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Grouped by section",
+			args:     []string{"changie", "changelog", "entries-between-tags", "v1.0.0", "v1.2.0"},
+			expected: "### Added\n\n- Feature C\n- Feature B\n\n### Fixed\n\n- Fix B\n\n### Notes\n\n- Custom section\n",
+		},
+		{
+			name:     "Strictly between",
+			args:     []string{"changie", "changelog", "entries-between-tags", "1.0.0", "1.2.0", "--exclude-to"},
+			expected: "### Added\n\n- Feature B\n\n### Fixed\n\n- Fix B\n",
+		},
+		{
+			name:     "Include from",
+			args:     []string{"changie", "changelog", "entries-between-tags", "1.0.0", "1.1.0", "--include-from"},
+			expected: "### Added\n\n- Feature B\n- Feature A\n\n### Fixed\n\n- Fix B\n",
+		},
+		{
+			name:     "Empty range",
+			args:     []string{"changie", "changelog", "entries-between-tags", "1.2.0", "1.3.0"},
+			expected: "No entries found between 1.2.0 and 1.3.0\n",
+		},
+		{
+			name: "JSON output",
+			args: []string{"changie", "changelog", "entries-between-tags", "1.1.0", "1.2.0", "--json"},
+			expected: `[
+  {
+    "version": "1.2.0",
+    "date": "2024-03-01",
+    "section": "Added",
+    "entry": "Feature C"
+  },
+  {
+    "version": "1.2.0",
+    "date": "2024-03-01",
+    "section": "Notes",
+    "entry": "Custom section"
+  }
+]
+`,
+		},
+		{
+			name:    "Reversed range",
+			args:    []string{"changie", "changelog", "entries-between-tags", "1.2.0", "1.0.0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*changelogBetweenInclFrom = false
+			*changelogBetweenExclTo = false
+			*jsonOutput = false
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestInitJSONOutput(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	os.Args = []string{"changie", "init", "--json", "--file", "CHANGES.md"}
+	defer func() { *changeLogFile = "CHANGELOG.md" }()
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := `{
+  "success": true,
+  "changelog_file": "CHANGES.md",
+  "created": true
+}
+`
+	if !strings.HasSuffix("\n"+output, "\n"+expected) {
+		t.Errorf("Expected output to end with %q, got %q", expected, output)
+	}
+	if strings.Contains(output, "Project initialized") {
+		t.Errorf("Expected no human-readable output with --json, got %q", output)
+	}
+}
+
+func TestInitTitle(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *initTitle = changelog.DefaultTitle }()
+
+	os.Args = []string{"changie", "init", "--title", "# Änderungsprotokoll"}
+	_, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	expected := "Error: Invalid --title: title \"# Änderungsprotokoll\" must not start with #, the heading marker is added"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+
+	os.Args = []string{"changie", "init", "--title", "Änderungsprotokoll"}
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+	})
+	if err != nil || !strings.HasSuffix(output, "Project initialized for SemVer and Keep a Changelog.\n") {
+		t.Errorf("Expected the project to be initialized, got %q, %v", output, err)
+	}
+}
+
+func TestChangelogUpdateJSONOutput(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	tests := []struct {
+		name        string
+		isDuplicate bool
+		expected    string
+	}{
+		{
+			name:        "New entry",
+			isDuplicate: false,
+			expected: `{
+  "success": true,
+  "section": "Added",
+  "content": "New feature",
+  "changelog_file": "CHANGELOG.md",
+  "duplicate": false
+}
+`,
+		},
+		{
+			name:        "Duplicate entry",
+			isDuplicate: true,
+			expected: `{
+  "success": true,
+  "section": "Added",
+  "content": "New feature",
+  "changelog_file": "CHANGELOG.md",
+  "duplicate": true
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"changie", "changelog", "added", "New feature", "--json"}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{isDuplicate: tt.isDuplicate}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+			if strings.Contains(output, "Added section:") {
+				t.Errorf("Expected no human-readable output with --json, got: %q", output)
+			}
+		})
+	}
+}
+
+func TestVersionSourceChangelog(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *skipChangelog = false }()
+
+	os.Args = []string{"changie", "patch", "--version-source", "changelog"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "0.5.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Current version from changelog: 1.0.0\nNew version: 1.0.1\n") {
+		t.Errorf("Expected version to be read from the changelog, got: %q", output)
+	}
+	if strings.Contains(output, "Current version from git tags") {
+		t.Errorf("Expected git tags not to be used, got: %q", output)
+	}
+
+	os.Args = []string{"changie", "patch", "--version-source", "changelog", "--no-changelog"}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "--no-changelog") {
+		t.Errorf("Expected --no-changelog error, got: %v", err)
+	}
+
+	*skipChangelog = false
+	os.Args = []string{"changie", "patch", "--version-source", "changelog"}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{changelogContent: "# Changelog\n\n## [Unreleased]\n"}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "Error getting changelog version") {
+		t.Errorf("Expected missing version error, got: %v", err)
+	}
+}
+
+func TestRequireSyncWithChangelogSource(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *requireSync = false }()
+
+	tests := []struct {
+		name       string
+		gitVersion string
+		lastTag    string
+		wantErr    bool
+	}{
+		{"Versions match", "1.0.0", "", false},
+		{"Prefixed tag matches", "v1.0.0", "", false},
+		{"Commits after the tag", "1.0.0-dev.1+be78306", "1.0.0", false},
+		{"Versions differ", "0.5.0", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"changie", "patch", "--version-source", "changelog", "--require-sync"}
+			mockGitManager := &MockGitManager{projectVersion: tt.gitVersion, lastTag: tt.lastTag}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if strings.Contains(output, "Version mismatch") {
+				t.Errorf("Expected the mismatch to be reported only through the error, got: %q", output)
+			}
+			if tt.wantErr && mockGitManager.tagVersionCalled != 0 {
+				t.Error("Version was tagged despite a version mismatch")
+			}
+		})
+	}
+}
+
+// TestBumpWithChangelogBackup releases in a real repository where a rewrite
+// command left an untracked changelog backup behind
+func TestBumpWithChangelogBackup(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git is not installed")
+	}
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	*autoPush = false
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// Don't look for a repository above the temp dir
+	oldCeiling, hadCeiling := os.LookupEnv("GIT_CEILING_DIRECTORIES")
+	defer func() {
+		if hadCeiling {
+			os.Setenv("GIT_CEILING_DIRECTORIES", oldCeiling)
+		} else {
+			os.Unsetenv("GIT_CEILING_DIRECTORIES")
+		}
+	}()
+	os.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	content := "# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- Fix login\n\n## [1.0.0] - 2024-01-01\n\n### Added\n\n- First release\n"
+	if err := os.WriteFile("CHANGELOG.md", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+		{"config", "tag.gpgsign", "false"},
+		{"add", "CHANGELOG.md"},
+		{"commit", "-m", "Initial commit"},
+		{"tag", "1.0.0"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+		}
+	}
+	if err := os.WriteFile(changelog.BackupFileName("CHANGELOG.md", time.Now()), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"changie", "patch"}
+	_, err = captureOutput(t, func() error {
+		return run(DefaultChangelogManager{}, DefaultGitManager{}, DefaultSemverManager{})
+	})
+
+	if err != nil {
+		t.Fatalf("Expected the backup not to count as an uncommitted change, got: %v", err)
+	}
+	if exists, err := git.TagExists("1.0.1"); err != nil || !exists {
+		t.Errorf("Expected tag 1.0.1, got: %v, %v", exists, err)
+	}
+}
+
+func TestChangelogSetUnreleasedDate(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *changelogDateClear = false }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		date     string
+		wantErr  bool
+	}{
+		{
+			name:     "Set date",
+			args:     []string{"changie", "changelog", "set-unreleased-date", "2024-07-01"},
+			expected: "Scheduled the release for 2024-07-01.\n",
+			date:     "2024-07-01",
+		},
+		{
+			name:     "Clear date",
+			args:     []string{"changie", "changelog", "set-unreleased-date", "--clear"},
+			expected: "Cleared the scheduled release date.\n",
+		},
+		{
+			name:    "Missing date",
+			args:    []string{"changie", "changelog", "set-unreleased-date"},
+			wantErr: true,
+		},
+		{
+			name:    "Date and clear",
+			args:    []string{"changie", "changelog", "set-unreleased-date", "2024-07-01", "--clear"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*changelogDateClear = false
+			*changelogDate = ""
+			os.Args = tt.args
+			mockChangelogManager := &MockChangelogManager{unreleasedDate: "unchanged"}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if mockChangelogManager.unreleasedDate != "unchanged" {
+					t.Error("Changelog was modified despite invalid arguments")
+				}
+				return
+			}
+			if !strings.HasSuffix(output, tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+			if mockChangelogManager.unreleasedDate != tt.date {
+				t.Errorf("Expected date %q, got %q", tt.date, mockChangelogManager.unreleasedDate)
+			}
+		})
+	}
+}
+
+func TestReleaseCommitAuthor(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*authorName = ""
+		*authorEmail = ""
+	}()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "Name and email",
+			args: []string{"changie", "patch", "--author-name", "Release Bot", "--author-email", "bot@example.com"},
+		},
+		{
+			name:    "Missing email",
+			args:    []string{"changie", "patch", "--author-name", "Release Bot"},
+			wantErr: "Error: --author-name and --author-email must be used together.",
+		},
+		{
+			name:    "Invalid email",
+			args:    []string{"changie", "patch", "--author-name", "Release Bot", "--author-email", "Bot <bot@example.com>"},
+			wantErr: "Error: Invalid author email: Bot <bot@example.com>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*authorName = ""
+			*authorEmail = ""
+			os.Args = tt.args
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+			_, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got: %v", tt.wantErr, err)
+			}
+			if mockGitManager.commitChangelogCalled != 0 {
+				t.Error("Changelog was committed despite an invalid author")
+			}
+		})
+	}
+}
+
+func TestChangelogEntries(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*changelogEntriesNoStream = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature B
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix A, wrapped
+  over two lines
+`
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Text",
+			args:     []string{"changie", "changelog", "entries"},
+			expected: "Unreleased Added: Feature B\n1.0.0 (2024-01-01) Fixed: Fix A, wrapped over two lines\n",
+		},
+		{
+			name:     "JSON lines",
+			args:     []string{"changie", "changelog", "entries", "--json"},
+			expected: "{\"version\":\"Unreleased\",\"section\":\"Added\",\"entry\":\"Feature B\"}\n{\"version\":\"1.0.0\",\"date\":\"2024-01-01\",\"section\":\"Fixed\",\"entry\":\"Fix A, wrapped over two lines\"}\n",
+		},
+		{
+			name:     "Text without streaming",
+			args:     []string{"changie", "changelog", "entries", "--no-stream"},
+			expected: "Unreleased Added: Feature B\n1.0.0 (2024-01-01) Fixed: Fix A, wrapped over two lines\n",
+		},
+		{
+			name:     "JSON array without streaming",
+			args:     []string{"changie", "changelog", "entries", "--json", "--no-stream"},
+			expected: "[\n  {\n    \"version\": \"Unreleased\",\n    \"section\": \"Added\",\n    \"entry\": \"Feature B\"\n  },\n  {\n    \"version\": \"1.0.0\",\n    \"date\": \"2024-01-01\",\n    \"section\": \"Fixed\",\n    \"entry\": \"Fix A, wrapped over two lines\"\n  }\n]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*jsonOutput = false
+			*changelogEntriesNoStream = false
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogGrep(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*changelogGrepUnreleased = false
+		*changelogGrepIgnoreCase = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Crash when the changelog is empty
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Crash on missing tags
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "All versions",
+			args:     []string{"changie", "changelog", "grep", "Crash"},
+			expected: "Unreleased Fixed: Crash when the changelog is empty\n1.1.0 (2024-02-01) Fixed: Crash on missing tags\n",
+		},
+		{
+			name:     "Unreleased only",
+			args:     []string{"changie", "changelog", "grep", "Crash", "--unreleased-only"},
+			expected: "Unreleased Fixed: Crash when the changelog is empty\n",
+		},
+		{
+			name:     "Ignore case",
+			args:     []string{"changie", "changelog", "grep", "-i", "initial"},
+			expected: "1.0.0 (2024-01-01) Added: Initial release\n",
+		},
+		{
+			name:     "No matches",
+			args:     []string{"changie", "changelog", "grep", "initial"},
+			expected: "No entries in CHANGELOG.md match initial\n",
+		},
+		{
+			name:     "JSON output",
+			args:     []string{"changie", "changelog", "grep", "tags", "--json"},
+			expected: "[\n  {\n    \"version\": \"1.1.0\",\n    \"date\": \"2024-02-01\",\n    \"section\": \"Fixed\",\n    \"entry\": \"Crash on missing tags\"\n  }\n]\n",
+		},
+		{
+			name:          "Invalid pattern",
+			args:          []string{"changie", "changelog", "grep", "(crash"},
+			expectedError: "Error: invalid pattern \"(crash\": error parsing regexp: missing closing ): `(crash`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*jsonOutput = false
+			*changelogGrepUnreleased = false
+			*changelogGrepIgnoreCase = false
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogStats(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*jsonOutput = false
+		*changelogStatsAll = false
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Fix C
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature B
+
+### Internal
+
+- Refactor
+
+## [1.0.0] - 2024-01-01
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:    "Unreleased",
+			args:    []string{"changie", "changelog", "stats"},
+			content: content,
+			expected: `Version     Added  Changed  Deprecated  Removed  Fixed  Security  Total
+Unreleased      0        0           0        0      1         0      1
+`,
+		},
+		{
+			name:    "All versions",
+			args:    []string{"changie", "changelog", "stats", "--all"},
+			content: content,
+			expected: `Version     Added  Changed  Deprecated  Removed  Fixed  Security  Internal  Total
+Unreleased      0        0           0        0      1         0         0      1
+1.1.0           1        0           0        0      0         0         1      2
+1.0.0           0        0           0        0      0         0         0      0
+Total           1        0           0        0      1         0         1      3
+`,
+		},
+		{
+			name:    "JSON output",
+			args:    []string{"changie", "changelog", "stats", "--json"},
+			content: content,
+			expected: `{
+  "versions": [
+    {
+      "version": "Unreleased",
+      "sections": {
+        "Added": 0,
+        "Changed": 0,
+        "Deprecated": 0,
+        "Fixed": 1,
+        "Removed": 0,
+        "Security": 0
+      },
+      "total": 1
+    }
+  ],
+  "sections": {
+    "Added": 0,
+    "Changed": 0,
+    "Deprecated": 0,
+    "Fixed": 1,
+    "Removed": 0,
+    "Security": 0
+  },
+  "total": 1
+}
+`,
+		},
+		{
+			name:          "No Unreleased section",
+			args:          []string{"changie", "changelog", "stats"},
+			content:       "# Changelog\n\n## [1.0.0] - 2024-01-01\n",
+			expectedError: "Error: CHANGELOG.md has no Unreleased section.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*jsonOutput = false
+			*changelogStatsAll = false
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogGraph(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-03-05
+
+## [1.1.0] - 2024-01-30
+
+## [1.0.1] - TBD
+
+## [1.0.0] - 2024-01-10
+`
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Per month",
+			args:     []string{"changie", "changelog", "graph"},
+			expected: "2024-01 ## 2\n2024-02  0\n2024-03 # 1\n\nExcluded 1 versions without a date.\n",
+		},
+		{
+			name:     "Per quarter",
+			args:     []string{"changie", "changelog", "graph", "--period", "quarter"},
+			expected: "2024-Q1 ### 3\n\nExcluded 1 versions without a date.\n",
+		},
+		{
+			name: "JSON output",
+			args: []string{"changie", "changelog", "graph", "--period", "quarter", "--json"},
+			expected: `{
+  "periods": [
+    {
+      "period": "2024-Q1",
+      "releases": 3
+    }
+  ],
+  "undated": 1
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*jsonOutput = false
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*outputFile = ""
+		*changelogShowVersion = ""
+	}()
+
+	dir, err := os.MkdirTemp("", "changie-output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tags.txt")
+	os.Args = []string{"changie", "tag", "list", "--output-file", path}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{tags: []string{"0.9.0", "0.10.0"}}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(output, "0.10.0") {
+		t.Errorf("Expected tags not to be printed to stdout, got: %q", output)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "0.10.0\n0.9.0\n" {
+		t.Errorf("Expected tags in output file, got: %q", string(content))
+	}
+
+	*outputFile = ""
+	os.Args = []string{"changie", "tag", "list", "--output-file", filepath.Join(dir, "missing", "tags.txt")}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{tags: []string{"0.9.0"}}, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "Error creating output file") {
+		t.Errorf("Expected output file error, got: %v", err)
+	}
+
+	// A failing command leaves the existing output file as it was
+	*outputFile = ""
+	os.Args = []string{"changie", "changelog", "show", "9.9.9", "--output-file", path}
+
+	_, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err == nil {
+		t.Error("Expected an error for an unknown version")
+	}
+	if content, _ := os.ReadFile(path); string(content) != "0.10.0\n0.9.0\n" {
+		t.Errorf("Expected the output file to be unchanged, got: %q", string(content))
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, ".tags.txt.tmp-*")); len(files) != 0 {
+		t.Errorf("Expected the temp file to be removed, got: %v", files)
+	}
+}
+
+// streamCheckManager records the size of the temp output file once the
+// changelog has been read to the end
+type streamCheckManager struct {
+	*MockChangelogManager
+	pattern string
+	size    int64
+}
+
+func (m *streamCheckManager) OpenChangelog() (io.ReadCloser, error) {
+	content, err := m.GetChangelogContent()
+	return io.NopCloser(&streamCheckReader{Reader: strings.NewReader(content), manager: m}), err
+}
+
+type streamCheckReader struct {
+	io.Reader
+	manager *streamCheckManager
+}
+
+func (r *streamCheckReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		if files, _ := filepath.Glob(r.manager.pattern); len(files) == 1 {
+			if info, err := os.Stat(files[0]); err == nil {
+				r.manager.size = info.Size()
+			}
+		}
+	}
+	return n, err
+}
+
+func TestOutputFileStreamsEntries(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *outputFile = "" }()
+
+	var content strings.Builder
+	var expected strings.Builder
+	content.WriteString("# Changelog\n\n## [1.0.0] - 2024-01-01\n\n### Added\n\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&content, "- Feature %d\n", i)
+		fmt.Fprintf(&expected, "1.0.0 (2024-01-01) Added: Feature %d\n", i)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entries.txt")
+	manager := &streamCheckManager{
+		MockChangelogManager: &MockChangelogManager{changelogContent: content.String()},
+		pattern:              filepath.Join(dir, ".entries.txt.tmp-*"),
+	}
+	os.Args = []string{"changie", "changelog", "entries", "--output-file", path}
+
+	_, err := captureOutput(t, func() error {
+		return run(manager, &MockGitManager{}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Entries are on disk before the whole changelog has been read
+	if manager.size == 0 {
+		t.Error("Expected the entries to be streamed to a temp file")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != expected.String() {
+		t.Errorf("Expected all entries in the output file, got %d bytes, %v", len(data), err)
+	}
+	if files, _ := filepath.Glob(manager.pattern); len(files) != 0 {
+		t.Errorf("Expected the temp file to be removed, got: %v", files)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Markdown",
+			args:     []string{"changie", "docs"},
+			expected: []string{"# changie\n", "| `--auto-push` | Automatically push changes and tags after version bump |", "### changelog added\n", "changie changelog added <content>"},
+		},
+		{
+			name:     "Man page",
+			args:     []string{"changie", "docs", "--format", "man"},
+			expected: []string{".TH changie 1", ".SH \"COMMANDS\"", "changelog added"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, &MockGitManager{}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected output to contain %q, got: %q", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestBumpToConstraint(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *bumpTo = "" }()
+
+	tests := []struct {
+		name     string
+		to       string
+		expected string
+		wantErr  bool
+	}{
+		{"Major range", ">=2.0.0 <3.0.0", "major release 2.0.0 done.\n", false},
+		{"Minor range", ">=1.2.0", "minor release 1.2.0 done.\n", false},
+		{"Unsatisfiable range", "<1.0.0", "", true},
+		{"Invalid range", "~1.2", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"changie", "bump", "--to", tt.to}
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Version was tagged despite an invalid range")
+				}
+				return
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestBumpTypeArgument(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*bumpType = ""
+		*bumpTo = ""
+	}()
+
+	tests := []struct {
+		name          string
+		args          []string
+		expected      string
+		expectedError string
+	}{
+		{"Major", []string{"changie", "bump", "major"}, "major release 2.0.0 done.\n", ""},
+		{"Minor", []string{"changie", "bump", "minor"}, "minor release 1.1.0 done.\n", ""},
+		{"Patch", []string{"changie", "bump", "patch"}, "patch release 1.0.1 done.\n", ""},
+		{"Invalid type", []string{"changie", "bump", "huge"}, "usage: changie bump [<flags>] [<type>]", "Error: invalid bump type \"huge\", expected major, minor, patch or calver."},
+		{"Missing type", []string{"changie", "bump"}, "usage: changie bump [<flags>] [<type>]", "Error: bump requires a bump type (major, minor, patch or calver) or --to."},
+		{"Type and range", []string{"changie", "bump", "minor", "--to", ">=2.0.0"}, "", "Error: use either a bump type or --to, not both."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*bumpType = ""
+			*bumpTo = ""
+			*autoPush = false
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Version was tagged despite invalid arguments")
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestBumpCalVer(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*bumpType = ""
+		*versionSource = "git"
+		*versionScheme = "semver"
+	}()
+
+	month := time.Now().Format("2006.01")
+	tests := []struct {
+		name          string
+		args          []string
+		gitVersion    string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:       "Same month",
+			args:       []string{"changie", "--version-scheme", "calver", "bump", "calver"},
+			gitVersion: month + ".2",
+			expected:   "calver release " + month + ".3 done.\n",
+		},
+		{
+			name:       "New month",
+			args:       []string{"changie", "--version-scheme", "calver", "bump", "calver"},
+			gitVersion: "2000.01.4",
+			expected:   "calver release " + month + ".0 done.\n",
+		},
+		{
+			name:          "Semantic version",
+			args:          []string{"changie", "--version-scheme", "calver", "bump", "calver"},
+			gitVersion:    "1.2.3",
+			expectedError: "Error bumping version: invalid calendar version format: 1.2.3, expected YYYY.0M.MICRO",
+		},
+		{
+			name:     "Version from changelog",
+			args:     []string{"changie", "--version-scheme", "calver", "bump", "calver", "--version-source", "changelog"},
+			content:  "# Changelog\n\n## [Unreleased]\n\n## [" + month + ".0] - 2024-01-01\n",
+			expected: "calver release " + month + ".1 done.\n",
+		},
+		{
+			name:          "Invalid version in changelog",
+			args:          []string{"changie", "--version-scheme", "calver", "bump", "calver", "--version-source", "changelog"},
+			content:       "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n",
+			expectedError: "Error: Latest changelog version 1.0.0 is not a valid calendar version: invalid calendar version format: 1.0.0, expected YYYY.0M.MICRO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*bumpType = ""
+			*versionSource = "git"
+			*versionScheme = "semver"
+			*autoPush = false
+			if tt.content == "" {
+				tt.content = "# Changelog\n\n## [Unreleased]\n\n## [" + tt.gitVersion + "] - 2000-01-01\n"
+			}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, &MockGitManager{projectVersion: tt.gitVersion}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestVersionScheme(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*bumpType = ""
+		*bumpTo = ""
+		*versionScheme = "semver"
+		*changelogFirstVersion = ""
+		initialVersionSet = false
+	}()
+
+	month := time.Now().Format("2006.01")
+	released := "# Changelog\n\n## [Unreleased]\n\n## [" + month + ".0] - 2000-01-01\n"
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		gitVersion    string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:          "Patch with calver",
+			args:          []string{"changie", "--version-scheme", "calver", "patch"},
+			expectedError: "Error: patch releases can't be used with --version-scheme calver. Use changie bump calver.",
+		},
+		{
+			name:          "Bump major with calver",
+			args:          []string{"changie", "--version-scheme", "calver", "bump", "major"},
+			expectedError: "Error: major releases can't be used with --version-scheme calver. Use changie bump calver.",
+		},
+		{
+			name:          "Range with calver",
+			args:          []string{"changie", "--version-scheme", "calver", "bump", "--to", ">=2.0.0"},
+			expectedError: "Error: bump --to can't be used with --version-scheme calver. Use changie bump calver.",
+		},
+		{
+			name:          "Calver with semver",
+			args:          []string{"changie", "bump", "calver"},
+			expectedError: "Error: calver releases require --version-scheme calver.",
+		},
+		{
+			name:       "Bump without type with calver",
+			args:       []string{"changie", "--version-scheme", "calver", "bump"},
+			content:    released,
+			gitVersion: month + ".0",
+			expected:   "calver release " + month + ".1 done.\n",
+		},
+		{
+			name:       "First release with calver",
+			args:       []string{"changie", "--version-scheme", "calver", "changelog", "first-release"},
+			content:    "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Initial release\n",
+			gitVersion: "dev",
+			expected:   "first release " + month + ".0 done.\nRelease the next versions with changie bump calver.\n",
+		},
+		{
+			name:          "Semantic first release with calver",
+			args:          []string{"changie", "--version-scheme", "calver", "changelog", "first-release", "1.0.0"},
+			content:       "# Changelog\n\n## [Unreleased]\n",
+			gitVersion:    "dev",
+			expectedError: "Error: 1.0.0 is not a valid calendar version: invalid calendar version format: 1.0.0, expected YYYY.0M.MICRO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*bumpType = ""
+			*bumpTo = ""
+			*versionScheme = "semver"
+			*changelogFirstVersion = ""
+			initialVersionSet = false
+			*autoPush = false
+			mockGitManager := &MockGitManager{projectVersion: tt.gitVersion}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Version was tagged despite the incompatible scheme")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogFirstRelease(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogFirstVersion = ""
+		*initialVersion = "0.1.0"
+		initialVersionSet = false
+	}()
+
+	unreleased := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- First feature\n"
+
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		tags          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Default version",
+			args:     []string{"changie", "changelog", "first-release"},
+			content:  unreleased,
+			expected: "first release 0.1.0 done.\nRelease the next versions with changie major, minor or patch.\nDon't forget to git push and git push --tags.\n",
+		},
+		{
+			name:     "Version argument",
+			args:     []string{"changie", "changelog", "first-release", "1.0.0"},
+			content:  unreleased,
+			tags:     []string{"docs-draft"},
+			expected: "first release 1.0.0 done.\n",
+		},
+		{
+			name:     "Initial version flag",
+			args:     []string{"changie", "changelog", "first-release", "--initial-version", "0.0.1"},
+			content:  unreleased,
+			expected: "first release 0.0.1 done.\n",
+		},
+		{
+			name:          "Conflicting versions",
+			args:          []string{"changie", "changelog", "first-release", "1.0.0", "--initial-version", "0.0.1"},
+			content:       unreleased,
+			expectedError: "Error: the version argument 1.0.0 and --initial-version 0.0.1 differ",
+		},
+		{
+			name:          "Invalid version",
+			args:          []string{"changie", "changelog", "first-release", "one"},
+			content:       unreleased,
+			expectedError: "Error: one is not a valid semantic version: invalid version format: one",
+		},
+		{
+			name:          "Existing release",
+			args:          []string{"changie", "changelog", "first-release"},
+			content:       "# Changelog\n\n## [Unreleased]\n\n## [0.1.0] - 2024-01-01\n",
+			expectedError: "Error: CHANGELOG.md already has the release 0.1.0. Use changie major, minor or patch for the next release.",
+		},
+		{
+			name:          "Existing tag",
+			args:          []string{"changie", "changelog", "first-release"},
+			content:       unreleased,
+			tags:          []string{"v0.1.0"},
+			expectedError: "Error: The version tag v0.1.0 already exists. Use changie major, minor or patch for the next release.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogFirstVersion = ""
+			*initialVersion = "0.1.0"
+			initialVersionSet = false
+			*autoPush = false
+			mockChangelogManager := &MockChangelogManager{changelogContent: tt.content}
+			mockGitManager := &MockGitManager{projectVersion: "dev", tags: tt.tags}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Version was tagged despite a failed check")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if mockChangelogManager.updateChangelogCalled != 1 || mockGitManager.tagVersionCalled != 1 {
+				t.Errorf("Expected the changelog to be updated and tagged once, got %d and %d", mockChangelogManager.updateChangelogCalled, mockGitManager.tagVersionCalled)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestIdempotentBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *idempotent = false }()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.0.1] - 2024-01-02
+
+## [1.0.0] - 2024-01-01
+`
+
+	tests := []struct {
+		name         string
+		args         []string
+		tags         []string
+		expected     string
+		expectTagged bool
+	}{
+		{"Retried patch release", []string{"changie", "patch", "--idempotent"}, []string{"1.0.0", "1.0.1"}, "Already at target version 1.0.1, nothing to do.\n", false},
+		{"Different bump type", []string{"changie", "minor", "--idempotent"}, []string{"1.0.0", "1.0.1"}, "minor release 1.1.0 done.\n", true},
+		{"Without idempotent", []string{"changie", "patch"}, []string{"1.0.0", "1.0.1"}, "patch release 1.0.2 done.\n", true},
+		{"Migrated to prefixed tags", []string{"changie", "patch", "--idempotent"}, []string{"1.0.0", "v1.0.1"}, "Already at target version v1.0.1, nothing to do.\n", false},
+		{"Migrated to unprefixed tags", []string{"changie", "patch", "--idempotent"}, []string{"v1.0.0", "1.0.1"}, "Already at target version 1.0.1, nothing to do.\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*idempotent = false
+			os.Args = tt.args
+			mockGitManager := &MockGitManager{projectVersion: tt.tags[len(tt.tags)-1], tags: tt.tags}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: content}, mockGitManager, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+			if (mockGitManager.tagVersionCalled > 0) != tt.expectTagged {
+				t.Errorf("Expected tagged: %v, tag called %d times", tt.expectTagged, mockGitManager.tagVersionCalled)
+			}
+		})
+	}
+}
+
+func TestBumpJSONOutput(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	*autoPush = false
+	os.Args = []string{"changie", "minor", "--json"}
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0", remoteURL: "/srv/git/changie.git"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := `{
+  "success": true,
+  "previous_version": "1.0.0",
+  "version": "1.1.0",
+  "tag": "1.1.0",
+  "bump_type": "minor",
+  "date": "` + time.Now().Format("2006-01-02") + `",
+  "commit": "0123456789abcdef0123456789abcdef01234567",
+  "pushed": false,
+  "entries": 0,
+  "sections": [],
+  "warnings": [
+    "The release has no changelog entries.",
+    "Could not detect the repository from /srv/git/changie.git, changelog links use the default repository URL."
+  ],
+  "warning_details": [
+    {
+      "code": "no_entries",
+      "message": "The release has no changelog entries."
+    },
+    {
+      "code": "repo_info_unavailable",
+      "message": "Could not detect the repository from /srv/git/changie.git, changelog links use the default repository URL.",
+      "error": "unsupported repository URL: /srv/git/changie.git"
+    }
+  ]
+}
+`
+	if !strings.HasSuffix("\n"+output, "\n"+expected) {
+		t.Errorf("Expected output to end with %q, got: %q", expected, output)
+	}
+}
+
+func TestBumpJSONRepoInfoWarning(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	*autoPush = false
+	os.Args = []string{"changie", "patch", "--json"}
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0", remoteURLErr: fmt.Errorf("no such remote 'origin'")}
+
+	stdout, _, err := captureStreams(t, func() error {
+		return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var result bumpOutput
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Expected JSON output, got: %q", stdout)
+	}
+	expected := bumpWarning{
+		Code:    "repo_info_unavailable",
+		Message: "Could not get the URL of the origin remote, changelog links use the default repository URL.",
+		Error:   "no such remote 'origin'",
+	}
+	if len(result.WarningDetails) != 2 || result.WarningDetails[1] != expected {
+		t.Errorf("Expected warning %+v, got: %+v", expected, result.WarningDetails)
+	}
+}
+
+func TestBumpTagPrefix(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*tagPrefix = false
+		tagPrefixSet = false
+		*versionScheme = "semver"
+	}()
+
+	tests := []struct {
+		name           string
+		args           []string
+		currentVersion string
+		expected       string
+	}{
+		{"Default", []string{"changie", "patch"}, "v1.0.0", "patch release 1.0.1 done.\n"},
+		{"Prefix", []string{"changie", "patch", "--prefix"}, "1.0.0", "Tagging version: v1.0.1\npatch release 1.0.1 done.\n"},
+		{"No prefix", []string{"changie", "minor", "--no-prefix"}, "v1.0.0", "Tagging version: 1.1.0\nminor release 1.1.0 done.\n"},
+		{"Calendar version", []string{"changie", "bump", "calver", "--prefix", "--version-scheme", "calver"}, "2024.01.3", fmt.Sprintf("Tagging version: v%s.0\ncalver release %[1]s.0 done.\n", time.Now().Format("2006.01"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*tagPrefix = false
+			tagPrefixSet = false
+			*autoPush = false
+			*bumpType = ""
+			*versionScheme = "semver"
+			mockGitManager := &MockGitManager{projectVersion: tt.currentVersion}
+			mockChangelogManager := &MockChangelogManager{changelogContent: "## [Unreleased]\n\n## [" + strings.TrimPrefix(tt.currentVersion, "v") + "] - 2024-01-01\n"}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestBumpShallowClone(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *strict = false }()
+
+	tests := []struct {
+		name          string
+		args          []string
+		shallowErr    error
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Warning",
+			args:     []string{"changie", "patch"},
+			expected: "Warning: The repository is a shallow clone, so tags may be missing and the current version may be wrong. Run git fetch --tags --unshallow to fetch all tags.\n",
+		},
+		{
+			name:          "Strict",
+			args:          []string{"changie", "patch", "--strict"},
+			expectedError: "Error: The repository is a shallow clone, so tags may be missing and the current version may be wrong. Run git fetch --tags --unshallow to fetch all tags.",
+		},
+		{
+			name:          "Git error",
+			args:          []string{"changie", "patch"},
+			shallowErr:    fmt.Errorf("error checking for a shallow clone: exit status 128"),
+			expectedError: "Error checking for a shallow clone: error checking for a shallow clone: exit status 128",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*strict = false
+			*autoPush = false
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0", shallow: true, shallowErr: tt.shallowErr}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Expected no tag")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestBumpEmptyCommit(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *emptyCommit = "error" }()
+
+	nothingToCommit := fmt.Errorf("%w for version 1.0.1, CHANGELOG.md and the additional files are unchanged", git.ErrNothingToCommit)
+	tests := []struct {
+		name          string
+		args          []string
+		commitErr     error
+		expected      string
+		expectedError string
+	}{
+		{
+			name:          "Error by default",
+			args:          []string{"changie", "patch"},
+			commitErr:     nothingToCommit,
+			expectedError: "Error committing changelog: nothing to commit for version 1.0.1, CHANGELOG.md and the additional files are unchanged",
+		},
+		{
+			name:      "Skipped",
+			args:      []string{"changie", "patch", "--empty-commit", "skip"},
+			commitErr: nothingToCommit,
+			expected:  "Warning: Nothing changed for the release commit, so only the tag was created.\n",
+		},
+		{
+			name:          "Other errors aren't skipped",
+			args:          []string{"changie", "patch", "--empty-commit", "skip"},
+			commitErr:     fmt.Errorf("permission denied"),
+			expectedError: "Error committing changelog: permission denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*emptyCommit = "error"
+			*autoPush = false
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0", commitChangelogErr: tt.commitErr}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				if mockGitManager.tagVersionCalled != 0 {
+					t.Error("Expected no tag after a failed commit")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if mockGitManager.tagVersionCalled != 1 {
+				t.Errorf("Expected the version to be tagged, got: %d", mockGitManager.tagVersionCalled)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestBumpReleaseContents(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *jsonOutput = false }()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature B
+
+### Fixed
+
+- Bug fix
+
+## [1.0.0] - 2024-01-01
+`
+
+	*autoPush = false
+	os.Args = []string{"changie", "minor"}
+
+	output, err := captureOutput(t, func() error {
+		return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Release contents: 2 Added, 1 Fixed\n") {
+		t.Errorf("Expected release contents summary, got: %q", output)
+	}
+	if strings.Contains(output, "no changelog entries") {
+		t.Errorf("Expected no empty release warning, got: %q", output)
+	}
+
+	os.Args = []string{"changie", "minor", "--json"}
+
+	output, err = captureOutput(t, func() error {
+		return run(&MockChangelogManager{changelogContent: content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := `  "entries": 3,
+  "sections": [
+    {
+      "section": "Added",
+      "entries": 2
+    },
+    {
+      "section": "Fixed",
+      "entries": 1
+    }
+  ],
+  "warnings": [],
+  "warning_details": []
+}
+`
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf("Expected output to end with %q, got: %q", expected, output)
+	}
+}
+
+func TestEmptyReleasePlaceholder(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*emptyPlaceholder = ""
+		*emptyPlaceholderSection = "Changed"
+	}()
+
+	empty := "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n"
+	withEntries := "# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- Bug fix\n\n## [1.0.0] - 2024-01-01\n"
+
+	tests := []struct {
+		name            string
+		args            []string
+		content         string
+		expectedSection string
+		expectedContent string
+		expected        string
+	}{
+		{
+			name:            "Placeholder",
+			args:            []string{"changie", "patch", "--empty-release-placeholder", "No notable changes."},
+			content:         empty,
+			expectedSection: "Changed",
+			expectedContent: "No notable changes.",
+			expected:        "Adding placeholder to the empty release: No notable changes.\nRelease contents: 1 Changed\n",
+		},
+		{
+			name:            "Placeholder section",
+			args:            []string{"changie", "patch", "--empty-release-placeholder", "Dependency updates only.", "--empty-release-section", "Security"},
+			content:         empty,
+			expectedSection: "Security",
+			expectedContent: "Dependency updates only.",
+			expected:        "Release contents: 1 Security\n",
+		},
+		{
+			name:     "Release with entries",
+			args:     []string{"changie", "patch", "--empty-release-placeholder", "No notable changes."},
+			content:  withEntries,
+			expected: "Release contents: 1 Fixed\n",
+		},
+		{
+			name:     "No placeholder",
+			args:     []string{"changie", "patch"},
+			content:  empty,
+			expected: "Warning: The release has no changelog entries.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*emptyPlaceholder = ""
+			*emptyPlaceholderSection = "Changed"
+			*autoPush = false
+			mockCM := &MockChangelogManager{changelogContent: tt.content}
+
+			output, err := captureOutput(t, func() error {
+				return run(mockCM, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if mockCM.addedSection != tt.expectedSection || mockCM.addedContent != tt.expectedContent {
+				t.Errorf("Expected placeholder %q in %q, got %q in %q", tt.expectedContent, tt.expectedSection, mockCM.addedContent, mockCM.addedSection)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.expected, output)
+			}
+			if tt.expectedContent != "" && strings.Contains(output, "no changelog entries") {
+				t.Errorf("Expected no empty release warning, got: %q", output)
+			}
+		})
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *baseURL = "" }()
+
+	*autoPush = false
+
+	t.Run("Overrides detected remote", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--base-url", "https://github.mycorp.com/team/project"}
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0", remoteURL: "/srv/git/changie.git"}
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if !strings.Contains(output, "Changelog links use the base URL: https://github.mycorp.com/team/project\n") {
+			t.Errorf("Expected base URL message, got: %q", output)
+		}
+		if strings.Contains(output, "Could not detect the repository") {
+			t.Errorf("Expected no remote detection warning, got: %q", output)
+		}
+	})
+
+	t.Run("Invalid base URL", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--base-url", "github.mycorp.com/team/project"}
+		mockChangelogManager := &MockChangelogManager{}
+
+		_, err := captureOutput(t, func() error {
+			return run(mockChangelogManager, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+		})
+
+		if err == nil || !strings.Contains(err.Error(), "Invalid base URL github.mycorp.com/team/project") {
+			t.Errorf("Expected invalid base URL error, got: %v", err)
+		}
+		if mockChangelogManager.updateChangelogCalled > 0 {
+			t.Error("Expected changelog not to be updated")
+		}
+	})
+}
+
+func TestSummaryFile(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *summaryFile = "" }()
+
+	*autoPush = false
+	dir := t.TempDir()
+
+	t.Run("Writes summary", func(t *testing.T) {
+		file := filepath.Join(dir, "release-summary.json")
+		os.Args = []string{"changie", "minor", "--summary-file", file}
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if !strings.Contains(output, "Wrote release summary to "+file+"\n") {
+			t.Errorf("Expected summary message, got: %q", output)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Expected summary file, got: %v", err)
+		}
+		var summary bumpOutput
+		if err := json.Unmarshal(content, &summary); err != nil {
+			t.Fatalf("Expected JSON summary, got: %v", err)
+		}
+		if summary.PreviousVersion != "1.0.0" || summary.Version != "1.1.0" || summary.Commit == "" || summary.Date == "" {
+			t.Errorf("Unexpected summary: %+v", summary)
+		}
+	})
+
+	t.Run("Failure is a warning", func(t *testing.T) {
+		file := filepath.Join(dir, "missing", "release-summary.json")
+		os.Args = []string{"changie", "minor", "--summary-file", file}
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if !strings.Contains(output, "Warning: Could not write the release summary to "+file) {
+			t.Errorf("Expected summary warning, got: %q", output)
+		}
+		if mockGitManager.tagVersionCalled != 1 {
+			t.Error("Expected the release to be tagged")
+		}
+	})
+}
+
+func TestProgressStderr(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *progressStderr = false }()
+
+	*autoPush = false
+	os.Args = []string{"changie", "minor", "--progress-stderr"}
+
+	stdout, stderr, err := captureStreams(t, func() error {
+		return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if stdout != "minor release 1.1.0 done.\n" {
+		t.Errorf("Expected only the release message on stdout, got: %q", stdout)
+	}
+	for _, expected := range []string{"New version: 1.1.0\n", "Tagging version: 1.1.0\n", "Don't forget to git push and git push --tags.\n"} {
+		if !strings.Contains(stderr, expected) {
+			t.Errorf("Expected stderr to contain %q, got: %q", expected, stderr)
+		}
+	}
+}
+
+func TestPrintTag(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*printTag = false
+		*jsonOutput = false
+	}()
+
+	tests := []struct {
+		bumpType string
+		expected string
+	}{
+		{"major", "2.0.0\n"},
+		{"minor", "1.1.0\n"},
+		{"patch", "1.0.1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bumpType, func(t *testing.T) {
+			*printTag = false
+			*autoPush = false
+			os.Args = []string{"changie", tt.bumpType, "--print-tag"}
+
+			stdout, stderr, err := captureStreams(t, func() error {
+				return run(&MockChangelogManager{}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if stdout != tt.expected {
+				t.Errorf("Expected only the tag %q on stdout, got: %q", tt.expected, stdout)
+			}
+			for _, expected := range []string{"Tagging version: ", tt.bumpType + " release ", "Don't forget to git push and git push --tags.\n"} {
+				if !strings.Contains(stderr, expected) {
+					t.Errorf("Expected stderr to contain %q, got: %q", expected, stderr)
+				}
+			}
+		})
+	}
+
+	t.Run("With JSON", func(t *testing.T) {
+		*printTag = false
+		*jsonOutput = false
+		os.Args = []string{"changie", "patch", "--print-tag", "--json"}
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		_, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err == nil || err.Error() != "Error: --print-tag can't be combined with --json." {
+			t.Errorf("Expected an error for --print-tag with --json, got: %v", err)
+		}
+		if mockGitManager.tagVersionCalled != 0 {
+			t.Error("Version was tagged despite conflicting flags")
+		}
+	})
+}
+
+func TestTagNotes(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *tagNotes = false }()
+
+	*autoPush = false
+
+	t.Run("Notes in tag message", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--tag-notes"}
+		content := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature A
+
+## [1.0.0] - 2024-01-01
+`
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		_, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{releasedContent: content}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		expected := "1.1.0\n\n### Added\n\n- Feature A\n"
+		if mockGitManager.tagMessage != expected {
+			t.Errorf("Expected tag message %q, got %q", expected, mockGitManager.tagMessage)
+		}
+		if mockGitManager.tagVersionCalled != 1 {
+			t.Errorf("Expected one tag, got %d", mockGitManager.tagVersionCalled)
+		}
+	})
+
+	t.Run("Excluded section", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--tag-notes", "--exclude-section", "Internal"}
+		defer func() { *excludeSections = nil }()
+		content := "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2024-02-01\n\n### Added\n\n- Feature A\n\n### Internal\n\n- Refactor B\n"
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		_, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{releasedContent: content}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		expected := "1.1.0\n\n### Added\n\n- Feature A\n"
+		if mockGitManager.tagMessage != expected {
+			t.Errorf("Expected tag message %q, got %q", expected, mockGitManager.tagMessage)
+		}
+	})
+
+	t.Run("Missing notes", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--tag-notes", "--no-changelog"}
+		defer func() { *skipChangelog = false }()
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if mockGitManager.tagMessage != "1.1.0\n" {
+			t.Errorf("Expected tag message with only the version, got %q", mockGitManager.tagMessage)
+		}
+		if !strings.Contains(output, "Warning: Could not read the release notes of 1.1.0") {
+			t.Errorf("Expected missing notes warning, got: %q", output)
+		}
+	})
+}
+
+func TestNotesFile(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*notesFile = false
+		*notesFileTemplate = changelog.DefaultNotesFile
+	}()
+
+	*autoPush = false
+	dir := t.TempDir()
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature A
+
+## [1.0.0] - 2024-01-01
+`
+
+	t.Run("Writes notes", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--notes-file", "--notes-file-template", filepath.Join(dir, "{{.Tag}}.notes.md")}
+		file := filepath.Join(dir, "1.1.0.notes.md")
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{releasedContent: content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if !strings.Contains(output, "Wrote release notes to "+file+"\n") {
+			t.Errorf("Expected notes file message, got: %q", output)
+		}
+		notes, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Expected notes file, got: %v", err)
+		}
+		if string(notes) != "### Added\n\n- Feature A\n" {
+			t.Errorf("Unexpected notes: %q", notes)
+		}
+	})
+
+	t.Run("Failure is a warning", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--notes-file", "--notes-file-template", filepath.Join(dir, "missing", "{{.Tag}}.md")}
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		output, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{releasedContent: content}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if !strings.Contains(output, "Warning: Could not write the release notes of 1.1.0 to a file") {
+			t.Errorf("Expected notes file warning, got: %q", output)
+		}
+		if mockGitManager.tagVersionCalled != 1 {
+			t.Error("Expected the release to be tagged")
+		}
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		os.Args = []string{"changie", "minor", "--notes-file", "--notes-file-template", "{{.Name}}.md"}
+		mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+		_, err := captureOutput(t, func() error {
+			return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+		})
+
+		if err == nil || !strings.HasPrefix(err.Error(), "Error: Invalid --notes-file-template:") {
+			t.Errorf("Expected invalid template error, got: %v", err)
+		}
+		if mockGitManager.tagVersionCalled != 0 {
+			t.Error("Expected no tag")
+		}
+	})
+}
+
+func TestCoAuthors(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *coAuthors = nil }()
+
+	*autoPush = false
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "Valid co-authors",
+			args: []string{"changie", "patch", "--co-author", "Jane Doe <jane@example.com>", "--co-author", "John Roe <john@example.com>"},
+		},
+		{
+			name:    "Missing name",
+			args:    []string{"changie", "patch", "--co-author", "jane@example.com"},
+			wantErr: "Error: Invalid co-author jane@example.com, expected \"Name <email>\".",
+		},
+		{
+			name:    "Invalid email",
+			args:    []string{"changie", "patch", "--co-author", "Jane Doe <jane>"},
+			wantErr: "Error: Invalid co-author Jane Doe <jane>, expected \"Name <email>\".",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*coAuthors = nil
+			os.Args = tt.args
+			mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+			_, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{}, mockGitManager, &MockSemverManager{})
+			})
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				if mockGitManager.commitChangelogCalled != 1 {
+					t.Error("Expected the changelog to be committed")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got: %v", tt.wantErr, err)
+			}
+			if mockGitManager.commitChangelogCalled != 0 {
+				t.Error("Expected no commit")
+			}
+		})
+	}
+}
+
+func TestLastLine(t *testing.T) {
+	tests := map[string]string{
+		"failed to push changes: exit status 128\nCommand output: fatal: Could not resolve host: github.com\n": "Command output: fatal: Could not resolve host: github.com",
+		"single line": "single line",
+		"first\nfatal: the remote end hung up unexpectedly\n\n": "fatal: the remote end hung up unexpectedly",
+	}
+	for text, expected := range tests {
+		if result := lastLine(text); result != expected {
+			t.Errorf("lastLine(%q) = %q, want %q", text, result, expected)
+		}
+	}
+}
+
+func TestChangelogVerifyLinks(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *changelogVerifyOnline = false }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases/tag/1.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `# Changelog
+
+## [1.1.0] - 2024-02-01
+
+## [1.0.0] - 2024-01-01
+
+[1.1.0]: ` + server.URL + `/compare/1.0.0...1.1.0
+[1.0.0]: ` + server.URL + `/releases/tag/1.0.0
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Offline",
+			args:     []string{"changie", "changelog", "verify-links"},
+			content:  content,
+			expected: "All 2 links in CHANGELOG.md are valid URLs. Use --online to check that they resolve.\n",
+		},
+		{
+			name:          "Invalid URL",
+			args:          []string{"changie", "changelog", "verify-links"},
+			content:       "## [1.0.0] - 2024-01-01\n\n[1.0.0]: github.com/peiman/changie/releases/tag/1.0.0\n",
+			expected:      "Broken link [1.0.0] on line 3: github.com/peiman/changie/releases/tag/1.0.0: invalid URL: expected an http or https URL\n",
+			expectedError: "Error: 1 of 1 links in CHANGELOG.md are broken.",
+		},
+		{
+			name:          "Online",
+			args:          []string{"changie", "changelog", "verify-links", "--online", "--timeout", "5s"},
+			content:       content,
+			expected:      "Broken link [1.1.0] on line 7: " + server.URL + "/compare/1.0.0...1.1.0: HTTP 404 Not Found\n",
+			expectedError: "Error: 1 of 2 links in CHANGELOG.md are broken.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogVerifyOnline = false
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, &MockGitManager{projectVersion: "1.0.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestChangelogShow(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*changelogShowVersion = ""
+		*changelogShowUnreleased = false
+		*jsonOutput = false
+		*notesHeader = ""
+		*notesFooter = ""
+		*excludeSections = nil
+	}()
+
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature A
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+	footerFile := filepath.Join(t.TempDir(), "footer.md")
+	if err := os.WriteFile(footerFile, []byte("Install with `go install github.com/peiman/changie@{{.Version}}`\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unreleased := strings.Replace(content, "## [Unreleased]\n", "## [Unreleased]\n\n### Added\n\n- Feature B\n\n### Fixed\n\n- Fix A\n", 1)
+
+	tests := []struct {
+		name          string
+		args          []string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Latest release",
+			args:     []string{"changie", "changelog", "show"},
+			expected: "### Added\n\n- Feature A\n",
+		},
+		{
+			name:     "With header and footer",
+			args:     []string{"changie", "--notes-header", "# Changie {{.Version}} ({{.Date}})", "--notes-footer", "@" + footerFile, "changelog", "show", "1.0.0"},
+			expected: "# Changie 1.0.0 (2024-01-01)\n\n### Added\n\n- Initial release\n\nInstall with `go install github.com/peiman/changie@1.0.0`\n",
+		},
+		{
+			name:          "Unknown version",
+			args:          []string{"changie", "changelog", "show", "2.0.0"},
+			expectedError: "Error getting release notes: version 2.0.0 not found in changelog",
+		},
+		{
+			name:          "Invalid template",
+			args:          []string{"changie", "--notes-header", "{{.Name}}", "changelog", "show"},
+			expectedError: "Error: Invalid release notes template",
+		},
+		{
+			name:     "Unreleased",
+			args:     []string{"changie", "changelog", "show", "--unreleased"},
+			content:  unreleased,
+			expected: "### Added\n\n- Feature B\n\n### Fixed\n\n- Fix A\n",
+		},
+		{
+			name:     "Empty Unreleased",
+			args:     []string{"changie", "changelog", "show", "--unreleased"},
+			expected: "The Unreleased section has no entries.\n",
+		},
+		{
+			name:     "Unreleased as JSON",
+			args:     []string{"changie", "changelog", "show", "--unreleased", "--json"},
+			content:  unreleased,
+			expected: "{\n  \"version\": \"Unreleased\",\n  \"sections\": [\n    {\n      \"name\": \"Added\",\n      \"entries\": [\n        \"Feature B\"\n      ]\n    },\n    {\n      \"name\": \"Fixed\",\n      \"entries\": [\n        \"Fix A\"\n      ]\n    }\n  ]\n}\n",
+		},
+		{
+			name:     "Excluded section",
+			args:     []string{"changie", "changelog", "show", "--unreleased", "--exclude-section", "fixed"},
+			content:  unreleased,
+			expected: "### Added\n\n- Feature B\n",
+		},
+		{
+			name:     "Excluded section as JSON",
+			args:     []string{"changie", "changelog", "show", "--unreleased", "--json", "--exclude-section", "Fixed"},
+			content:  unreleased,
+			expected: "{\n  \"version\": \"Unreleased\",\n  \"sections\": [\n    {\n      \"name\": \"Added\",\n      \"entries\": [\n        \"Feature B\"\n      ]\n    }\n  ]\n}\n",
+		},
+		{
+			name:          "Unreleased with a version",
+			args:          []string{"changie", "changelog", "show", "1.0.0", "--unreleased"},
+			expectedError: "Error: --unreleased can't be combined with a version.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			*changelogShowVersion = ""
+			*changelogShowUnreleased = false
+			*jsonOutput = false
+			*notesHeader = ""
+			*notesFooter = ""
+			*excludeSections = nil
+			if tt.content == "" {
+				tt.content = content
+			}
+
+			output, err := captureOutput(t, func() error {
+				return run(&MockChangelogManager{changelogContent: tt.content}, &MockGitManager{projectVersion: "1.1.0"}, &MockSemverManager{})
+			})
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+					t.Errorf("Expected error %q, got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix("\n"+output, "\n"+tt.expected) {
+				t.Errorf("Expected output to end with %q, got: %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestInvalidVersionHeaderOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() { *versionHeader = changelog.DefaultVersionHeader }()
+
+	os.Args = []string{"changie", "minor", "--version-header", "## {{.Version}} ({{.Channel}})"}
+	mockChangelogManager := &MockChangelogManager{}
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+	_, err := captureOutput(t, func() error {
+		return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.HasPrefix(err.Error(), "Error: Invalid version header: version header template must yield a header like") {
+		t.Errorf("Expected version header error, got: %v", err)
+	}
+	if mockChangelogManager.updateChangelogCalled > 0 || mockGitManager.tagVersionCalled > 0 {
+		t.Error("Expected no release actions")
+	}
+}
+
+func TestInvalidNotesTemplateOnBump(t *testing.T) {
+	isTestMode = true
+	defer func() { isTestMode = false }()
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	defer func() {
+		*notesFooter = ""
+		*tagNotes = false
+	}()
+
+	os.Args = []string{"changie", "minor", "--tag-notes", "--notes-footer", "Released {{.Version"}
+	mockChangelogManager := &MockChangelogManager{}
+	mockGitManager := &MockGitManager{projectVersion: "1.0.0"}
+
+	_, err := captureOutput(t, func() error {
+		return run(mockChangelogManager, mockGitManager, &MockSemverManager{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "Invalid release notes template: invalid notes footer template") {
+		t.Errorf("Expected template error, got: %v", err)
+	}
+	if mockChangelogManager.updateChangelogCalled > 0 || mockGitManager.tagVersionCalled > 0 {
+		t.Error("Expected no release actions")
 	}
 }
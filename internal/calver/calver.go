@@ -0,0 +1,71 @@
+// Package calver provides functionality for working with calendar versions
+// of the form YYYY.0M.MICRO, e.g. 2024.01.0.
+package calver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is a calendar version
+type Version struct {
+	Year  int
+	Month int
+	Micro int // Release number within the month, starting at 0
+}
+
+// String formats the version as YYYY.0M.MICRO
+func (v Version) String() string {
+	return fmt.Sprintf("%04d.%02d.%d", v.Year, v.Month, v.Micro)
+}
+
+// ParseVersion parses a YYYY.0M.MICRO version. A "v" prefix is allowed.
+func ParseVersion(version string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != 3 || len(parts[0]) != 4 || len(parts[1]) != 2 {
+		return Version{}, fmt.Errorf("invalid calendar version format: %s, expected YYYY.0M.MICRO", version)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 {
+			return Version{}, fmt.Errorf("invalid calendar version number: %s", part)
+		}
+		numbers[i] = num
+	}
+	if numbers[1] < 1 || numbers[1] > 12 {
+		return Version{}, fmt.Errorf("invalid calendar version month: %s", parts[1])
+	}
+	return Version{Year: numbers[0], Month: numbers[1], Micro: numbers[2]}, nil
+}
+
+// Next returns the version released at the given time after the current
+// version. Within the same month, the micro number is increased. In a new
+// month, it starts at 0 again. Without a current version, given as "" or
+// "dev", the first version of the month is returned. Suffixes such as
+// "-dev.3+abc1234" on the current version are ignored.
+func Next(current string, now time.Time) (string, error) {
+	next := Version{Year: now.Year(), Month: int(now.Month())}
+	if current == "" || current == "dev" {
+		return next.String(), nil
+	}
+
+	if i := strings.IndexAny(current, "-+"); i != -1 {
+		current = current[:i]
+	}
+	v, err := ParseVersion(current)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case v.Year > next.Year || v.Year == next.Year && v.Month > next.Month:
+		return "", fmt.Errorf("current version %s is newer than %s", current, now.Format("2006-01"))
+	case v.Year == next.Year && v.Month == next.Month:
+		next.Micro = v.Micro + 1
+	}
+	return next.String(), nil
+}
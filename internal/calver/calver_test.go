@@ -0,0 +1,63 @@
+package calver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Version
+		wantErr  bool
+	}{
+		{"2024.01.0", Version{2024, 1, 0}, false},
+		{"v2024.12.15", Version{2024, 12, 15}, false},
+		{"2024.1.0", Version{}, true},
+		{"24.01.0", Version{}, true},
+		{"2024.13.0", Version{}, true},
+		{"2024.00.0", Version{}, true},
+		{"2024.01", Version{}, true},
+		{"2024.01.x", Version{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseVersion(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseVersion(%s) error = %v, wantErr %v", test.input, err, test.wantErr)
+		}
+		if result != test.expected {
+			t.Errorf("ParseVersion(%s) = %v, expected %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		current  string
+		expected string
+		wantErr  bool
+	}{
+		{"", "2024.03.0", false},
+		{"dev", "2024.03.0", false},
+		{"2024.03.0", "2024.03.1", false},
+		{"v2024.03.9", "2024.03.10", false},
+		{"2024.03.2-dev.3+abc1234", "2024.03.3", false},
+		{"2024.02.4", "2024.03.0", false},
+		{"2023.12.1", "2024.03.0", false},
+		{"2024.04.0", "", true},
+		{"1.2.3", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := Next(test.current, now)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Next(%s) error = %v, wantErr %v", test.current, err, test.wantErr)
+		}
+		if result != test.expected {
+			t.Errorf("Next(%s) = %s, expected %s", test.current, result, test.expected)
+		}
+	}
+}
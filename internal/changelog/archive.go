@@ -0,0 +1,176 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Archive is a changelog file holding the released versions of one year
+type Archive struct {
+	Year     int      `json:"year"`
+	File     string   `json:"file"`
+	Versions []string `json:"versions"` // Versions moved to the archive, newest first
+}
+
+const archiveLinePrefix = "Older releases are archived by year:"
+
+var archiveYearRegex = regexp.MustCompile(`\[(\d{4})\]\(`)
+
+// ArchiveFileName returns the name of the archive of the given year next to
+// the changelog file, e.g. CHANGELOG-2023.md for CHANGELOG.md
+func ArchiveFileName(changelogFile string, year int) string {
+	ext := filepath.Ext(changelogFile)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(changelogFile, ext), year, ext)
+}
+
+// SplitByYear removes the released versions dated before the given year from
+// the changelog, together with their links, and groups them by year. The
+// header of the returned changelog links to the archives, including the ones
+// it already linked to. Unreleased and undated versions are kept.
+func SplitByYear(c *Changelog, year int, changelogFile string) (*Changelog, map[int]*Changelog) {
+	archives := map[int]*Changelog{}
+	archived := map[string]int{}
+	var kept []*Version
+	for _, v := range c.Versions {
+		date, err := time.Parse("2006-01-02", v.Date)
+		if v.Name == "Unreleased" || err != nil || date.Year() >= year {
+			kept = append(kept, v)
+			continue
+		}
+		if archives[date.Year()] == nil {
+			archives[date.Year()] = &Changelog{}
+		}
+		archives[date.Year()].Versions = append(archives[date.Year()].Versions, v)
+		archived[v.Name] = date.Year()
+	}
+	if len(archives) == 0 {
+		return c, archives
+	}
+
+	var links []string
+	for _, link := range c.Links {
+		label := link[1:strings.Index(link, "]")]
+		if y, ok := archived[label]; ok {
+			archives[y].Links = append(archives[y].Links, link)
+			continue
+		}
+		links = append(links, link)
+	}
+
+	years := map[int]bool{}
+	for y := range archives {
+		years[y] = true
+	}
+	var header []string
+	for _, line := range c.Header {
+		if !strings.HasPrefix(line, archiveLinePrefix) {
+			header = append(header, line)
+			continue
+		}
+		for _, m := range archiveYearRegex.FindAllStringSubmatch(line, -1) {
+			y, _ := strconv.Atoi(m[1])
+			years[y] = true
+		}
+	}
+	for len(header) > 0 && header[len(header)-1] == "" {
+		header = header[:len(header)-1]
+	}
+	header = append(header, "", archiveLine(years, changelogFile))
+
+	return &Changelog{Header: header, Versions: kept, Links: links}, archives
+}
+
+// archiveLine renders the header line linking to the archives, newest first
+func archiveLine(years map[int]bool, changelogFile string) string {
+	var sorted []int
+	for y := range years {
+		sorted = append(sorted, y)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	var links []string
+	for _, y := range sorted {
+		links = append(links, fmt.Sprintf("[%d](%s)", y, filepath.Base(ArchiveFileName(changelogFile, y))))
+	}
+	return archiveLinePrefix + " " + strings.Join(links, ", ") + "."
+}
+
+// mergeArchive adds the versions and links of the archive to the existing
+// archive content. Versions already in the existing archive are skipped.
+func mergeArchive(existing string, archive *Changelog, year int, changelogFile string) *Changelog {
+	merged := Parse(existing)
+	if len(merged.Header) == 0 {
+		merged.Header = []string{
+			fmt.Sprintf("# Changelog %d", year),
+			"",
+			fmt.Sprintf("Releases of %d, archived from [%s](%s).", year, filepath.Base(changelogFile), filepath.Base(changelogFile)),
+		}
+	}
+
+	var versions []*Version
+	for _, v := range archive.Versions {
+		if merged.Version(v.Name) == nil {
+			versions = append(versions, v)
+		}
+	}
+	merged.Versions = append(versions, merged.Versions...)
+
+	var links []string
+	for _, link := range archive.Links {
+		if !contains(merged.Links, link) {
+			links = append(links, link)
+		}
+	}
+	merged.Links = append(links, merged.Links...)
+	return merged
+}
+
+// ArchiveChangelog moves the released versions dated before the given year
+// from the changelog file into one archive file per year, and returns the
+// archives newest first. Archives that already exist are added to. With
+// dryRun set, no files are changed.
+func ArchiveChangelog(changelogFile string, year int, dryRun bool) ([]Archive, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	main, split := SplitByYear(Parse(string(content)), year, changelogFile)
+	var archives []Archive
+	files := map[string]string{}
+	for y, archive := range split {
+		file := ArchiveFileName(changelogFile, y)
+		result := Archive{Year: y, File: file}
+		for _, v := range archive.Versions {
+			result.Versions = append(result.Versions, v.Name)
+		}
+		archives = append(archives, result)
+
+		existing, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+		files[file] = mergeArchive(string(existing), archive, y, changelogFile).String()
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].Year > archives[j].Year })
+	if dryRun || len(archives) == 0 {
+		return archives, nil
+	}
+
+	// Write the archives first so that no versions are lost if writing fails
+	for _, archive := range archives {
+		if err := os.WriteFile(archive.File, []byte(files[archive.File]), 0644); err != nil {
+			return nil, fmt.Errorf("error writing archive: %w", err)
+		}
+	}
+	if err := os.WriteFile(changelogFile, []byte(main.String()), 0644); err != nil {
+		return nil, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return archives, nil
+}
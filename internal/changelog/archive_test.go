@@ -0,0 +1,218 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveFileName(t *testing.T) {
+	tests := map[string]string{
+		"CHANGELOG.md":      "CHANGELOG-2023.md",
+		"docs/CHANGELOG.md": "docs/CHANGELOG-2023.md",
+		"HISTORY":           "HISTORY-2023",
+	}
+	for file, expected := range tests {
+		if got := ArchiveFileName(file, 2023); got != expected {
+			t.Errorf("ArchiveFileName(%q) = %q, want %q", file, got, expected)
+		}
+	}
+}
+
+func TestArchiveChangelog(t *testing.T) {
+	content := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+## [2.0.0] - 2024-03-01
+
+### Added
+
+- New feature
+
+## [1.1.0] - 2023-06-01
+
+### Fixed
+
+- Bug fix
+
+## [1.0.0] - 2022-12-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/2.0.0...HEAD
+[2.0.0]: https://github.com/peiman/changie/compare/1.1.0...2.0.0
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+	expectedMain := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+Older releases are archived by year: [2023](CHANGELOG-2023.md), [2022](CHANGELOG-2022.md).
+
+## [Unreleased]
+
+## [2.0.0] - 2024-03-01
+
+### Added
+
+- New feature
+
+[Unreleased]: https://github.com/peiman/changie/compare/2.0.0...HEAD
+[2.0.0]: https://github.com/peiman/changie/compare/1.1.0...2.0.0
+`
+	expected2023 := `# Changelog 2023
+
+Releases of 2023, archived from [CHANGELOG.md](CHANGELOG.md).
+
+## [1.1.0] - 2023-06-01
+
+### Fixed
+
+- Bug fix
+
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+`
+	expected2022 := `# Changelog 2022
+
+Releases of 2022, archived from [CHANGELOG.md](CHANGELOG.md).
+
+## [1.0.0] - 2022-12-01
+
+### Added
+
+- Initial release
+
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+
+	t.Run("Dry run", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "CHANGELOG.md")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		expectedArchives := []Archive{
+			{Year: 2023, File: filepath.Join(dir, "CHANGELOG-2023.md"), Versions: []string{"1.1.0"}},
+			{Year: 2022, File: filepath.Join(dir, "CHANGELOG-2022.md"), Versions: []string{"1.0.0"}},
+		}
+
+		archives, err := ArchiveChangelog(file, 2024, true)
+		if err != nil {
+			t.Fatalf("ArchiveChangelog() error = %v", err)
+		}
+		if !reflect.DeepEqual(archives, expectedArchives) {
+			t.Errorf("ArchiveChangelog() = %v, want %v", archives, expectedArchives)
+		}
+		result, _ := os.ReadFile(file)
+		if string(result) != content {
+			t.Errorf("Dry run changed the changelog:\n%s", result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "CHANGELOG-2023.md")); !os.IsNotExist(err) {
+			t.Errorf("Dry run wrote an archive file")
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "CHANGELOG.md")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ArchiveChangelog(file, 2024, false); err != nil {
+			t.Fatalf("ArchiveChangelog() error = %v", err)
+		}
+		for name, expected := range map[string]string{"CHANGELOG.md": expectedMain, "CHANGELOG-2023.md": expected2023, "CHANGELOG-2022.md": expected2022} {
+			result, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatalf("Error reading %s: %v", name, err)
+			}
+			if string(result) != expected {
+				t.Errorf("%s =\n%s\nwant\n%s", name, result, expected)
+			}
+		}
+
+		// Archiving again moves nothing and keeps the files
+		archives, err := ArchiveChangelog(file, 2024, false)
+		if err != nil || len(archives) != 0 {
+			t.Errorf("Second ArchiveChangelog() = %v, %v, want no archives", archives, err)
+		}
+		result, _ := os.ReadFile(file)
+		if string(result) != expectedMain {
+			t.Errorf("Second run changed the changelog:\n%s", result)
+		}
+	})
+
+	t.Run("Adds to existing archive", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "CHANGELOG.md")
+		if err := os.WriteFile(file, []byte(expectedMain), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "CHANGELOG-2024.md"), []byte(`# Changelog 2024
+
+## [1.2.0] - 2024-01-01
+
+- Older release
+
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		archives, err := ArchiveChangelog(file, 2025, false)
+		if err != nil {
+			t.Fatalf("ArchiveChangelog() error = %v", err)
+		}
+		if len(archives) != 1 || archives[0].Year != 2024 {
+			t.Errorf("ArchiveChangelog() = %v, want the 2024 archive", archives)
+		}
+
+		expectedMain := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+Older releases are archived by year: [2024](CHANGELOG-2024.md), [2023](CHANGELOG-2023.md), [2022](CHANGELOG-2022.md).
+
+## [Unreleased]
+
+[Unreleased]: https://github.com/peiman/changie/compare/2.0.0...HEAD
+`
+		expected2024 := `# Changelog 2024
+
+## [2.0.0] - 2024-03-01
+
+### Added
+
+- New feature
+
+## [1.2.0] - 2024-01-01
+
+- Older release
+
+[2.0.0]: https://github.com/peiman/changie/compare/1.1.0...2.0.0
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+`
+		result, _ := os.ReadFile(file)
+		if string(result) != expectedMain {
+			t.Errorf("CHANGELOG.md =\n%s\nwant\n%s", result, expectedMain)
+		}
+		result, _ = os.ReadFile(filepath.Join(dir, "CHANGELOG-2024.md"))
+		if string(result) != expected2024 {
+			t.Errorf("CHANGELOG-2024.md =\n%s\nwant\n%s", result, expected2024)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := ArchiveChangelog(filepath.Join(t.TempDir(), "CHANGELOG.md"), 2024, false); err == nil {
+			t.Error("Expected an error for a missing changelog")
+		}
+	})
+}
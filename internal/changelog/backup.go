@@ -0,0 +1,47 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// backupTimeFormat sorts backups of the same file by the time they were made
+const backupTimeFormat = "20060102-150405.000"
+
+// BackupFileName returns the name of a backup of the file made at the given time,
+// e.g. CHANGELOG.md.bak-20240101-120000.000
+func BackupFileName(file string, t time.Time) string {
+	return file + ".bak-" + t.Format(backupTimeFormat)
+}
+
+// BackupFile copies the file to a timestamped backup next to it and returns
+// the backup name. Only the newest keep backups of the file are kept, older
+// ones are removed. With keep 0 or less, all backups are kept.
+func BackupFile(file string, keep int) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", file, err)
+	}
+	backup := BackupFileName(file, time.Now())
+	if err := os.WriteFile(backup, content, 0644); err != nil {
+		return "", fmt.Errorf("error writing backup: %w", err)
+	}
+
+	if keep > 0 {
+		backups, err := filepath.Glob(file + ".bak-*")
+		if err != nil {
+			return "", fmt.Errorf("error listing backups: %w", err)
+		}
+		sort.Strings(backups)
+		for len(backups) > keep {
+			if err := os.Remove(backups[0]); err != nil {
+				return "", fmt.Errorf("error removing old backup: %w", err)
+			}
+			backups = backups[1:]
+		}
+	}
+	return backup, nil
+}
@@ -0,0 +1,57 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestBackupFileName(t *testing.T) {
+	backupTime := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	if got := BackupFileName("CHANGELOG.md", backupTime); got != "CHANGELOG.md.bak-20240102-030405.006" {
+		t.Errorf("BackupFileName() = %q", got)
+	}
+}
+
+func TestBackupFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "CHANGELOG.md")
+	if err := os.WriteFile(file, []byte("# Changelog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var old []string
+	for _, ts := range []string{"20230101-000000.000", "20230102-000000.000", "20230103-000000.000"} {
+		name := file + ".bak-" + ts
+		if err := os.WriteFile(name, []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		old = append(old, name)
+	}
+
+	backup, err := BackupFile(file, 2)
+	if err != nil {
+		t.Fatalf("BackupFile() error = %v", err)
+	}
+	if content, err := os.ReadFile(backup); err != nil || string(content) != "# Changelog\n" {
+		t.Errorf("Backup %s = %q, %v, want the changelog content", backup, content, err)
+	}
+
+	backups, _ := filepath.Glob(file + ".bak-*")
+	sort.Strings(backups)
+	if len(backups) != 2 || backups[0] != old[2] || backups[1] != backup {
+		t.Errorf("Expected the newest old backup and the new backup to be kept, got: %v", backups)
+	}
+
+	if _, err := BackupFile(file, 0); err != nil {
+		t.Fatalf("BackupFile() error = %v", err)
+	}
+	if backups, _ := filepath.Glob(file + ".bak-*"); len(backups) < 2 {
+		t.Errorf("Expected all backups to be kept with keep 0, got: %v", backups)
+	}
+
+	if _, err := BackupFile(filepath.Join(dir, "MISSING.md"), 2); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,73 @@
+package changelog
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodCount is the number of releases in a month or quarter
+type PeriodCount struct {
+	Period   string `json:"period"`
+	Releases int    `json:"releases"`
+}
+
+// ReleasesPerPeriod counts the released versions per "month" or "quarter",
+// oldest first and including periods without releases. Versions without a
+// YYYY-MM-DD date, including Unreleased, are counted as undated.
+func (c *Changelog) ReleasesPerPeriod(period string) ([]PeriodCount, int, error) {
+	if period != "month" && period != "quarter" {
+		return nil, 0, fmt.Errorf("invalid period %q, expected month or quarter", period)
+	}
+
+	counts := make(map[string]int)
+	var first, last time.Time
+	undated := 0
+	for _, v := range c.Versions {
+		if v.Name == "Unreleased" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", v.Date)
+		if err != nil {
+			undated++
+			continue
+		}
+		start := periodStart(date, period)
+		counts[periodLabel(start, period)]++
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+
+	result := []PeriodCount{}
+	if first.IsZero() {
+		return result, undated, nil
+	}
+	step := 1
+	if period == "quarter" {
+		step = 3
+	}
+	for t := first; !t.After(last); t = t.AddDate(0, step, 0) {
+		label := periodLabel(t, period)
+		result = append(result, PeriodCount{Period: label, Releases: counts[label]})
+	}
+	return result, undated, nil
+}
+
+// periodStart returns the first day of the month or quarter of the date
+func periodStart(date time.Time, period string) time.Time {
+	month := date.Month()
+	if period == "quarter" {
+		month = (month-1)/3*3 + 1
+	}
+	return time.Date(date.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func periodLabel(start time.Time, period string) string {
+	if period == "quarter" {
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	}
+	return start.Format("2006-01")
+}
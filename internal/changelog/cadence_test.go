@@ -0,0 +1,75 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleasesPerPeriod(t *testing.T) {
+	c := Parse(`# Changelog
+
+## [Unreleased]
+
+## [1.3.0] - 2024-04-02
+
+## [1.2.0] - 2024-01-30
+
+## [1.1.0] - 2024-01-10
+
+## [1.0.1] - TBD
+
+## [1.0.0] - 2023-11-20
+`)
+
+	tests := []struct {
+		name     string
+		period   string
+		expected []PeriodCount
+		wantErr  bool
+	}{
+		{
+			name:   "Per month",
+			period: "month",
+			expected: []PeriodCount{
+				{"2023-11", 1},
+				{"2023-12", 0},
+				{"2024-01", 2},
+				{"2024-02", 0},
+				{"2024-03", 0},
+				{"2024-04", 1},
+			},
+		},
+		{
+			name:   "Per quarter",
+			period: "quarter",
+			expected: []PeriodCount{
+				{"2023-Q4", 1},
+				{"2024-Q1", 2},
+				{"2024-Q2", 1},
+			},
+		},
+		{
+			name:    "Invalid period",
+			period:  "week",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, undated, err := c.ReleasesPerPeriod(tt.period)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReleasesPerPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(counts, tt.expected) {
+				t.Errorf("ReleasesPerPeriod() = %v, want %v", counts, tt.expected)
+			}
+			if undated != 1 {
+				t.Errorf("Expected 1 undated version, got %d", undated)
+			}
+		})
+	}
+}
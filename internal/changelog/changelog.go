@@ -12,8 +12,35 @@ import (
 	"github.com/peiman/changie/internal/semver"
 )
 
+// DefaultTitle is the text of the "# " heading of new changelogs
+const DefaultTitle = "Changelog"
+
+// ValidateTitle checks that the title can be written as the single "# "
+// heading of a changelog, e.g. a translation of "Changelog"
+func ValidateTitle(title string) error {
+	switch {
+	case strings.TrimSpace(title) == "":
+		return fmt.Errorf("title is empty")
+	case strings.ContainsAny(title, "\r\n"):
+		return fmt.Errorf("title %q must be a single line", title)
+	case strings.HasPrefix(strings.TrimSpace(title), "#"):
+		return fmt.Errorf("title %q must not start with #, the heading marker is added", title)
+	}
+	return nil
+}
+
 // InitProject initializes the project with a new CHANGELOG.md file
 func InitProject(changelogFile string) error {
+	return InitProjectWithTitle(changelogFile, DefaultTitle)
+}
+
+// InitProjectWithTitle initializes the project with a new changelog file
+// headed by the given title instead of "Changelog"
+func InitProjectWithTitle(changelogFile, title string) error {
+	if err := ValidateTitle(title); err != nil {
+		return err
+	}
+
 	// Check if CHANGELOG.md already exists
 	if _, err := os.Stat(changelogFile); err == nil {
 		return fmt.Errorf("CHANGELOG.md already exists. Please rename or remove the existing file before running changie init.\n\n" +
@@ -21,15 +48,7 @@ func InitProject(changelogFile string) error {
 			"following the Keep a Changelog format: https://keepachangelog.com/")
 	}
 
-	content := `# Changelog
-
-All notable changes to this project will be documented in this file.
-
-The format is based on [Keep a Changelog](https://keepachangelog.com),
-and this project adheres to [Semantic Versioning (SemVer)](https://semver.org).
-
-## [Unreleased]
-`
+	content := strings.Join([]string{"# " + strings.TrimSpace(title), "", headerIntro, "", headerKeepChangelog, headerSemver, "", "## [Unreleased]", ""}, "\n")
 	if err := os.WriteFile(changelogFile, []byte(content), 0644); err != nil {
 		return err
 	}
@@ -45,28 +64,123 @@ func GetLatestChangelogVersion(content string) (string, error) {
 	return matches[1], nil
 }
 
+// GetLatestStableVersion returns the highest released version in the changelog,
+// skipping prereleases such as 1.2.0-rc.1. Build metadata is ignored, and
+// headers that aren't valid versions are skipped.
+func GetLatestStableVersion(content string) (string, error) {
+	latest := ""
+	for _, v := range Parse(content).Versions {
+		name := strings.SplitN(v.Name, "+", 2)[0]
+		if strings.Contains(name, "-") {
+			continue
+		}
+		if _, err := semver.ParseVersion(name); err != nil {
+			continue
+		}
+		if latest == "" {
+			latest = name
+			continue
+		}
+		if result, _ := semver.Compare(name, latest); result > 0 {
+			latest = name
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no stable version found in changelog")
+	}
+	return latest, nil
+}
+
+// GetVersionSection returns the release notes of a version, its sections
+// without the version header
+func GetVersionSection(content, version string) (string, error) {
+	v := Parse(content).Version(version)
+	if v == nil {
+		return "", fmt.Errorf("version %s not found in changelog", version)
+	}
+	notes := strings.TrimRight(strings.Join(v.Lines()[2:], "\n"), "\n")
+	if notes == "" {
+		return "", nil
+	}
+	return notes + "\n", nil
+}
+
 var execCommand = exec.Command
 
+// sectionOrder is the canonical Keep a Changelog order of sections
+var sectionOrder = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// ValidSections returns the accepted section names in the canonical order
+func ValidSections() []string {
+	return append([]string{}, sectionOrder...)
+}
+
+// UpdateOptions controls how UpdateChangelogWithOptions releases a new version
+type UpdateOptions struct {
+	Provider       string // Remote repository provider used for comparison links
+	CanonicalOrder bool   // Reorder the sections of the released version into the canonical order
+	Strict         bool   // Refuse to update a changelog with duplicate version headers
+	LinkStyle      string // "compare" (default) or "tag" to link every version to its release tag
+	RepositoryURL  string // Web URL of the repository, overrides the provider's default URL
+	VersionHeader  string // Template of the release header, DefaultVersionHeader when empty
+	Channel        string // Release channel available as {{.Channel}} in the version header
+	DefaultBranch  string // Branch Unreleased is compared with on Azure DevOps, which can't compare with HEAD, "main" when empty
+	// Tags maps versions without a "v" prefix to their git tag, so links
+	// point to the actual tags when the history mixes v1.0.0 and 1.0.0.
+	// Versions without a tag, such as the new version, are linked as is.
+	Tags map[string]string
+}
+
 // UpdateChangelog updates the CHANGELOG.md file with the new version
 func UpdateChangelog(file string, version string, provider string) error {
+	return UpdateChangelogWithOptions(file, version, UpdateOptions{Provider: provider})
+}
+
+// UpdateChangelogWithOptions updates the CHANGELOG.md file with the new version
+func UpdateChangelogWithOptions(file string, version string, opts UpdateOptions) error {
 	content, err := os.ReadFile(file)
 	if err != nil {
 		return err
 	}
 
+	if opts.Strict {
+		if issues := checkDuplicateVersions(string(content)); len(issues) > 0 {
+			messages := make([]string, len(issues))
+			for i, issue := range issues {
+				messages[i] = issue.Message
+			}
+			return fmt.Errorf("changelog has duplicate version headers: %s", strings.Join(messages, "; "))
+		}
+	}
+
+	headerTemplate, err := ParseHeaderTemplate(opts.VersionHeader)
+	if err != nil {
+		return err
+	}
+	versionHeader, err := headerTemplate.Render(HeaderData{Version: version, Date: time.Now().Format("2006-01-02"), Channel: opts.Channel})
+	if err != nil {
+		return err
+	}
+
 	lines := strings.Split(string(content), "\n")
 	var newLines []string
 	unreleasedAdded := false
 	versionAdded := false
 
 	for _, line := range lines {
-		if strings.HasPrefix(line, "## [Unreleased]") && !unreleasedAdded {
-			newLines = append(newLines, "## [Unreleased]", "")
-			newLines = append(newLines, fmt.Sprintf("## [%s] - %s", version, time.Now().Format("2006-01-02")))
+		if isUnreleasedHeader(line) && !unreleasedAdded {
+			// A placeholder like "- TBD" stays on Unreleased and the release gets
+			// the actual date. A scheduled date only applies to this release.
+			header := strings.TrimSpace(line)
+			if unreleasedDate(header) != "" {
+				header = "## [Unreleased]"
+			}
+			newLines = append(newLines, header, "")
+			newLines = append(newLines, versionHeader)
 			unreleasedAdded = true
 			versionAdded = true
 		} else if strings.HasPrefix(line, "## [") && !versionAdded {
-			newLines = append(newLines, fmt.Sprintf("## [%s] - %s", version, time.Now().Format("2006-01-02")))
+			newLines = append(newLines, versionHeader)
 			newLines = append(newLines, line)
 			versionAdded = true
 		} else {
@@ -74,8 +188,12 @@ func UpdateChangelog(file string, version string, provider string) error {
 		}
 	}
 
+	if opts.CanonicalOrder {
+		newLines = reorderVersionSections(newLines, version)
+	}
+
 	// Update comparison links
-	updatedLines := updateDiffLinks(newLines, version, provider)
+	updatedLines := updateDiffLinks(newLines, version, newLinkFormat(opts), opts.LinkStyle)
 
 	return os.WriteFile(file, []byte(strings.Join(updatedLines, "\n")), 0644)
 }
@@ -89,22 +207,48 @@ func ReformatChangelog(changelogFile string) error {
 	lines := strings.Split(string(content), "\n")
 	var reformattedLines []string
 	lastLineWasEmpty := false
+	inCodeBlock := false
+	inList := false
 
 	for _, line := range lines {
 		trimmedLine := strings.TrimSpace(line)
 
+		// Code blocks are copied verbatim, and indentation is kept for lines
+		// nested under a list item so sub-bullets stay attached to their entry.
+		if isCodeFence(trimmedLine) || inCodeBlock {
+			if isCodeFence(trimmedLine) {
+				inCodeBlock = !inCodeBlock
+			}
+			reformattedLines = append(reformattedLines, strings.TrimRight(line, " \t\r"))
+			lastLineWasEmpty = false
+			continue
+		}
+		if inList && trimmedLine != "" && strings.TrimLeft(line, " \t") != line && !strings.HasPrefix(trimmedLine, "#") {
+			reformattedLines = append(reformattedLines, strings.TrimRight(line, " \t\r"))
+			lastLineWasEmpty = false
+			continue
+		}
+		if trimmedLine != "" {
+			inList = isEntryLine(trimmedLine)
+		}
+
 		switch {
 		case strings.HasPrefix(trimmedLine, "# "):
 			reformattedLines = append(reformattedLines, trimmedLine, "")
 			lastLineWasEmpty = true
-		case trimmedLine == "All notable changes to this project will be documented in this file.":
+		case trimmedLine == headerIntro:
 			reformattedLines = append(reformattedLines, trimmedLine, "")
 			lastLineWasEmpty = true
 		case strings.Contains(trimmedLine, "Keep a Changelog"):
-			reformattedLines = append(reformattedLines, "The format is based on [Keep a Changelog](https://keepachangelog.com),")
+			// A link to a Keep a Changelog version is kept, only migrate changes it
+			if strings.Contains(trimmedLine, "keepachangelog.com/en/") {
+				reformattedLines = append(reformattedLines, trimmedLine)
+			} else {
+				reformattedLines = append(reformattedLines, "The format is based on [Keep a Changelog](https://keepachangelog.com),")
+			}
 			lastLineWasEmpty = false
 		case strings.Contains(trimmedLine, "Semantic Versioning"):
-			reformattedLines = append(reformattedLines, "and this project adheres to [Semantic Versioning (SemVer)](https://semver.org).", "")
+			reformattedLines = append(reformattedLines, headerSemver, "")
 			lastLineWasEmpty = true
 		case strings.HasPrefix(trimmedLine, "## "):
 			if !lastLineWasEmpty {
@@ -143,23 +287,67 @@ func ReformatChangelog(changelogFile string) error {
 	return nil
 }
 
+// AddOptions controls how AddChangelogSectionWithOptions writes a new entry
+type AddOptions struct {
+	WrapWidth int // Wrap the entry text at this column, 0 disables wrapping
+}
+
+// BatchEntry is an entry to add to a section of the Unreleased part of the changelog
+type BatchEntry struct {
+	Section string
+	Content string
+}
+
 // AddChangelogSection adds a new section to the Unreleased part of the changelog
 func AddChangelogSection(changelogFile, section, content string) (bool, error) {
+	return AddChangelogSectionWithOptions(changelogFile, section, content, AddOptions{})
+}
+
+// AddChangelogSectionWithOptions adds a new section to the Unreleased part of the changelog
+func AddChangelogSectionWithOptions(changelogFile, section, content string, opts AddOptions) (bool, error) {
+	_, skipped, err := ApplyEntriesWithOptions(changelogFile, []BatchEntry{{Section: section, Content: content}}, opts)
+	return skipped > 0, err
+}
+
+// ApplyEntries adds the entries to the Unreleased part of the changelog in a
+// single read and write, and returns how many were added and how many were
+// skipped as duplicates
+func ApplyEntries(changelogFile string, entries []BatchEntry) (int, int, error) {
+	return ApplyEntriesWithOptions(changelogFile, entries, AddOptions{})
+}
+
+// ApplyEntriesWithOptions adds the entries to the Unreleased part of the
+// changelog in a single read and write. Entries that duplicate an existing
+// entry, or an earlier entry of the batch, are skipped. When any entry is
+// invalid, the errors of all invalid entries are returned and nothing is written.
+func ApplyEntriesWithOptions(changelogFile string, batch []BatchEntry, opts AddOptions) (int, int, error) {
+	var invalid []string
+	for i, entry := range batch {
+		switch {
+		case !contains(sectionOrder, entry.Section):
+			invalid = append(invalid, fmt.Sprintf("entry %d: unknown section %q", i+1, entry.Section))
+		case strings.TrimSpace(entry.Content) == "":
+			invalid = append(invalid, fmt.Sprintf("entry %d: empty content", i+1))
+		}
+	}
+	if len(invalid) > 0 {
+		return 0, 0, fmt.Errorf("invalid entries: %s", strings.Join(invalid, "; "))
+	}
+
 	// Read the entire file
 	existingContent, err := os.ReadFile(changelogFile)
 	if err != nil {
-		return false, fmt.Errorf("error reading changelog: %w", err)
+		return 0, 0, fmt.Errorf("error reading changelog: %w", err)
 	}
 
 	lines := strings.Split(string(existingContent), "\n")
 	var newLines []string
 	unreleasedIndex := -1
-	sectionOrder := []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
 	sections := make(map[string][]string)
 
 	// Find the [Unreleased] section
 	for i, line := range lines {
-		if strings.HasPrefix(line, "## [Unreleased]") {
+		if isUnreleasedHeader(line) {
 			unreleasedIndex = i
 			break
 		}
@@ -175,27 +363,59 @@ func AddChangelogSection(changelogFile, section, content string) (bool, error) {
 		newLines = append(newLines, "")
 	}
 
-	// Process existing entries in [Unreleased]
+	// Process existing entries in [Unreleased]. Indented sub-bullets and fenced
+	// code blocks belong to the entry above them, so they are kept verbatim and
+	// headings inside a code block are not treated as section boundaries.
+	// Wrapped continuation lines are joined to their entry for duplicate detection.
 	currentSection := ""
+	nextVersionIndex := -1
+	inCodeBlock := false
+	joining := false
+	entries := make(map[string][]string)
 	for i := unreleasedIndex + 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if strings.HasPrefix(line, "## [") {
-			break
+		line := strings.TrimRight(lines[i], " \t\r")
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+		} else if !inCodeBlock {
+			if strings.HasPrefix(trimmedLine, "## [") {
+				nextVersionIndex = i
+				break
+			}
+			if strings.HasPrefix(trimmedLine, "### ") {
+				currentSection = strings.TrimPrefix(trimmedLine, "### ")
+				joining = false
+				continue
+			}
 		}
-		if strings.HasPrefix(line, "### ") {
-			currentSection = strings.TrimPrefix(line, "### ")
+		if currentSection == "" || (trimmedLine == "" && !inCodeBlock) {
+			joining = false
 			continue
 		}
-		if line != "" && currentSection != "" {
-			sections[currentSection] = append(sections[currentSection], line)
+		switch {
+		case !inCodeBlock && line == trimmedLine && isEntryLine(line):
+			entries[currentSection] = append(entries[currentSection], line)
+			joining = true
+		case joining && !inCodeBlock && !isCodeFence(trimmedLine) && !isEntryLine(trimmedLine) && line != trimmedLine:
+			last := len(entries[currentSection]) - 1
+			entries[currentSection][last] += " " + trimmedLine
+		default:
+			joining = false
 		}
+		sections[currentSection] = append(sections[currentSection], line)
 	}
 
 	// Add the new content to the appropriate section, but only if it doesn't already exist
-	newEntry := fmt.Sprintf("- %s", content)
-	isDuplicate := contains(sections[section], newEntry)
-	if !isDuplicate {
-		sections[section] = append(sections[section], newEntry)
+	added, skipped := 0, 0
+	for _, entry := range batch {
+		newEntry := fmt.Sprintf("- %s", entry.Content)
+		if containsEntry(entries[entry.Section], newEntry) {
+			skipped++
+			continue
+		}
+		sections[entry.Section] = append(sections[entry.Section], wrapEntry(entry.Content, opts.WrapWidth)...)
+		entries[entry.Section] = append(entries[entry.Section], newEntry)
+		added++
 	}
 
 	// Add sections in the correct order
@@ -208,11 +428,8 @@ func AddChangelogSection(changelogFile, section, content string) (bool, error) {
 	}
 
 	// Add the rest of the file
-	for i := unreleasedIndex + 1; i < len(lines); i++ {
-		if strings.HasPrefix(lines[i], "## [") {
-			newLines = append(newLines, lines[i:]...)
-			break
-		}
+	if nextVersionIndex != -1 {
+		newLines = append(newLines, lines[nextVersionIndex:]...)
 	}
 
 	// Remove any trailing empty lines
@@ -223,15 +440,114 @@ func AddChangelogSection(changelogFile, section, content string) (bool, error) {
 	// Write the updated content back to the file
 	err = os.WriteFile(changelogFile, []byte(strings.Join(newLines, "\n")), 0644)
 	if err != nil {
-		return false, fmt.Errorf("error writing changelog: %w", err)
+		return 0, 0, fmt.Errorf("error writing changelog: %w", err)
 	}
 	// Reformat the entire changelog after adding the new section
 	err = ReformatChangelog(changelogFile)
 	if err != nil {
-		return false, fmt.Errorf("error reformatting changelog: %w", err)
+		return 0, 0, fmt.Errorf("error reformatting changelog: %w", err)
+	}
+
+	return added, skipped, nil
+}
+
+// MoveVersion moves a version block of the changelog file before or after
+// another version
+func MoveVersion(changelogFile, version, target string, after bool) error {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	c := Parse(string(content))
+	if err := c.MoveVersion(version, target, after); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(c.String()), 0644); err != nil {
+		return fmt.Errorf("error writing changelog: %w", err)
+	}
+	return nil
+}
+
+// reorderVersionSections sorts the sections of the given version block into the canonical order
+func reorderVersionSections(lines []string, version string) []string {
+	start := -1
+	end := len(lines)
+	for i, line := range lines {
+		if start == -1 {
+			if strings.HasPrefix(line, fmt.Sprintf("## [%s]", version)) {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "## [") || linkLineRegex.MatchString(line) {
+			end = i
+			break
+		}
+	}
+	if start == -1 {
+		return lines
+	}
+
+	block := Parse(strings.Join(lines[start:end], "\n"))
+	block.Versions[0].SortSections(sectionOrder)
+
+	result := append([]string{}, lines[:start]...)
+	result = append(result, block.Versions[0].Lines()...)
+	return append(result, lines[end:]...)
+}
+
+// isUnreleasedHeader reports whether a line is the Unreleased version header,
+// optionally followed by a placeholder date such as "## [Unreleased] - TBD"
+func isUnreleasedHeader(line string) bool {
+	matches := versionHeaderRegex.FindStringSubmatch(strings.TrimSpace(line))
+	return matches != nil && matches[1] == "Unreleased"
+}
+
+// unreleasedDate returns the scheduled date of an Unreleased header, or an
+// empty string if it has no date or only a placeholder such as TBD
+func unreleasedDate(line string) string {
+	matches := versionHeaderRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil || matches[1] != "Unreleased" {
+		return ""
+	}
+	if _, err := time.Parse("2006-01-02", strings.TrimSpace(matches[2])); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(matches[2])
+}
+
+// SetUnreleasedDate sets the scheduled release date on the Unreleased header,
+// e.g. "## [Unreleased] - 2024-07-01". An empty date clears it.
+func SetUnreleasedDate(content, date string) (string, error) {
+	header := "## [Unreleased]"
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return "", fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+		}
+		header += " - " + date
 	}
 
-	return isDuplicate, nil
+	lines := strings.Split(content, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock && isUnreleasedHeader(line) {
+			lines[i] = header
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("no [Unreleased] section found in changelog")
+}
+
+// isCodeFence reports whether a trimmed line opens or closes a fenced code block
+func isCodeFence(trimmedLine string) bool {
+	return strings.HasPrefix(trimmedLine, "```") || strings.HasPrefix(trimmedLine, "~~~")
 }
 
 // Helper function to check if a slice contains a string
@@ -244,7 +560,7 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-func updateDiffLinks(lines []string, newVersion, provider string) []string {
+func updateDiffLinks(lines []string, newVersion string, links linkFormat, linkStyle string) []string {
 	var updatedLines []string
 	var versions []string
 	linkLines := map[string]string{}
@@ -274,22 +590,85 @@ func updateDiffLinks(lines []string, newVersion, provider string) []string {
 		return result > 0
 	})
 
-	baseURL := getCompareURL(provider)
-
 	// Update comparison links
-	newLinkLines := []string{fmt.Sprintf("[Unreleased]: %s/compare/%s...HEAD", baseURL, versions[0])}
+	newLinkLines := []string{fmt.Sprintf("[Unreleased]: %s", links.compare(versions[0], "HEAD"))}
 	for i := 0; i < len(versions)-1; i++ {
-		newLinkLines = append(newLinkLines, fmt.Sprintf("[%s]: %s/compare/%s...%s", versions[i], baseURL, versions[i+1], versions[i]))
+		if linkStyle == "tag" {
+			newLinkLines = append(newLinkLines, fmt.Sprintf("[%s]: %s", versions[i], links.tag(versions[i])))
+			continue
+		}
+		newLinkLines = append(newLinkLines, fmt.Sprintf("[%s]: %s", versions[i], links.compare(versions[i+1], versions[i])))
 	}
 	lastVersion := versions[len(versions)-1]
-	newLinkLines = append(newLinkLines, fmt.Sprintf("[%s]: %s/releases/tag/%s", lastVersion, baseURL, lastVersion))
+	newLinkLines = append(newLinkLines, fmt.Sprintf("[%s]: %s", lastVersion, links.tag(lastVersion)))
 
-	// Append updated link lines
+	// Append updated link lines after a single blank line
+	for len(updatedLines) > 0 && strings.TrimSpace(updatedLines[len(updatedLines)-1]) == "" {
+		updatedLines = updatedLines[:len(updatedLines)-1]
+	}
+	updatedLines = append(updatedLines, "")
 	updatedLines = append(updatedLines, newLinkLines...)
 
 	return updatedLines
 }
 
+// linkFormat builds the comparison and release links of a repository
+type linkFormat struct {
+	baseURL  string
+	provider string
+	tags     map[string]string // Git tag of each version, see UpdateOptions.Tags
+	branch   string            // See UpdateOptions.DefaultBranch
+}
+
+// newLinkFormat returns the link format of the repository given in opts, or
+// of the provider's default repository
+func newLinkFormat(opts UpdateOptions) linkFormat {
+	baseURL := opts.RepositoryURL
+	if baseURL == "" {
+		baseURL = getCompareURL(opts.Provider)
+	}
+	branch := opts.DefaultBranch
+	if branch == "" {
+		branch = "main"
+	}
+	return linkFormat{baseURL: strings.TrimSuffix(baseURL, "/"), provider: opts.Provider, tags: opts.Tags, branch: branch}
+}
+
+// ref returns the git tag of the version, or the version itself when it has
+// no known tag
+func (f linkFormat) ref(version string) string {
+	if tag, ok := f.tags[strings.TrimPrefix(version, "v")]; ok {
+		return tag
+	}
+	return version
+}
+
+// compare links to the changes between two tags, or between a tag and HEAD.
+// Azure DevOps can't compare against HEAD, so the default branch is used instead.
+func (f linkFormat) compare(from, to string) string {
+	from = f.ref(from)
+	if to != "HEAD" {
+		to = f.ref(to)
+	}
+	if f.provider == "azure" {
+		target := "GT" + to
+		if to == "HEAD" {
+			target = "GB" + f.branch
+		}
+		return fmt.Sprintf("%s/branchCompare?baseVersion=GT%s&targetVersion=%s", f.baseURL, from, target)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", f.baseURL, from, to)
+}
+
+// tag links to the release of a single tag
+func (f linkFormat) tag(version string) string {
+	version = f.ref(version)
+	if f.provider == "azure" {
+		return fmt.Sprintf("%s?version=GT%s", f.baseURL, version)
+	}
+	return fmt.Sprintf("%s/releases/tag/%s", f.baseURL, version)
+}
+
 func getCompareURL(provider string) string {
 	switch provider {
 	case "github":
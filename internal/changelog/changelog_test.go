@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -99,6 +100,62 @@ func TestInitProject(t *testing.T) {
 	}
 }
 
+func TestInitProjectWithTitle(t *testing.T) {
+	changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	if err := InitProjectWithTitle(changelogFile, "Journal des modifications"); err != nil {
+		t.Fatalf("InitProjectWithTitle() error = %v", err)
+	}
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Journal des modifications\n\n" + headerIntro + "\n\n" + headerKeepChangelog + "\n" + headerSemver + "\n\n## [Unreleased]\n"
+	if string(content) != expected {
+		t.Errorf("InitProjectWithTitle() wrote\n%s\nwant\n%s", content, expected)
+	}
+
+	// The title doesn't matter when reading versions or adding entries
+	if _, err := AddChangelogSection(changelogFile, "Added", "Neue Funktion"); err != nil {
+		t.Fatalf("AddChangelogSection() error = %v", err)
+	}
+	if err := UpdateChangelog(changelogFile, "1.0.0", "github"); err != nil {
+		t.Fatalf("UpdateChangelog() error = %v", err)
+	}
+	content, _ = os.ReadFile(changelogFile)
+	if !strings.HasPrefix(string(content), "# Journal des modifications\n") {
+		t.Errorf("Expected the title to be kept, got:\n%s", content)
+	}
+	if version, err := GetLatestChangelogVersion(string(content)); err != nil || version != "1.0.0" {
+		t.Errorf("GetLatestChangelogVersion() = %q, %v, want 1.0.0", version, err)
+	}
+
+	if err := InitProjectWithTitle(filepath.Join(t.TempDir(), "CHANGELOG.md"), "# Changelog"); err == nil {
+		t.Error("Expected an error for a title with a heading marker")
+	}
+}
+
+func TestValidateTitle(t *testing.T) {
+	tests := []struct {
+		title   string
+		wantErr bool
+	}{
+		{"Changelog", false},
+		{"Änderungsprotokoll", false},
+		{"Release history of changie", false},
+		{"", true},
+		{"  ", true},
+		{"Changelog\nMore", true},
+		{"# Changelog", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateTitle(tt.title); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTitle(%q) error = %v, wantErr %v", tt.title, err, tt.wantErr)
+		}
+	}
+}
+
 func TestAddChangelogSection(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -227,6 +284,201 @@ All notable changes to this project will be documented in this file.
 	}
 }
 
+func TestApplyEntries(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Existing fix
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+`
+
+	t.Run("Adds and dedupes", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+		if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		added, skipped, err := ApplyEntries(file, []BatchEntry{
+			{Section: "Added", Content: "Feature A"},
+			{Section: "Fixed", Content: "Existing fix"},
+			{Section: "Security", Content: "Patch B"},
+			{Section: "Added", Content: "Feature A"},
+			{Section: "Added", Content: "Feature C"},
+		})
+		if err != nil {
+			t.Fatalf("ApplyEntries() error = %v", err)
+		}
+		if added != 3 || skipped != 2 {
+			t.Errorf("ApplyEntries() = %d added, %d skipped, want 3 and 2", added, skipped)
+		}
+
+		expected := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature C
+
+### Fixed
+
+- Existing fix
+
+### Security
+
+- Patch B
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+`
+		content, _ := os.ReadFile(file)
+		if !compareIgnoreWhitespace(string(content), expected) {
+			t.Errorf("Changelog content doesn't match expected.\nGot:\n%s\nExpected:\n%s", content, expected)
+		}
+	})
+
+	t.Run("Invalid entries", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+		if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err := ApplyEntries(file, []BatchEntry{
+			{Section: "Added", Content: "Feature A"},
+			{Section: "Improved", Content: "Feature B"},
+			{Section: "Fixed", Content: " "},
+		})
+		expectedErr := `invalid entries: entry 2: unknown section "Improved"; entry 3: empty content`
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("ApplyEntries() error = %v, want %q", err, expectedErr)
+		}
+		content, _ := os.ReadFile(file)
+		if string(content) != initialContent {
+			t.Errorf("Changelog was changed despite invalid entries:\n%s", content)
+		}
+	})
+}
+
+func TestAddChangelogSectionWithNestedContent(t *testing.T) {
+	tests := []struct {
+		name            string
+		initialContent  string
+		section         string
+		content         string
+		expectedContent string
+		expectDuplicate bool
+	}{
+		{
+			name: "Append after entry with sub-bullets",
+			initialContent: `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Existing feature
+  - Detail one
+  - Detail two
+
+## [0.1.0] - 2023-01-01
+
+### Added
+
+- Initial release
+`,
+			section: "Added",
+			content: "Another feature",
+			expectedContent: `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Existing feature
+  - Detail one
+  - Detail two
+- Another feature
+
+## [0.1.0] - 2023-01-01
+
+### Added
+
+- Initial release
+`,
+		},
+		{
+			name: "Append after entry with code block",
+			initialContent: "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n" +
+				"- New config format:\n\n  ```yaml\n  ## [not a version]\n\n  - not an entry\n  ```\n\n" +
+				"## [0.1.0] - 2023-01-01\n\n### Added\n\n- Initial release\n",
+			section: "Changed",
+			content: "Another change",
+			expectedContent: "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n" +
+				"- New config format:\n  ```yaml\n  ## [not a version]\n\n  - not an entry\n  ```\n- Another change\n\n" +
+				"## [0.1.0] - 2023-01-01\n\n### Added\n\n- Initial release\n",
+		},
+		{
+			name:            "Code block content is not a duplicate",
+			initialContent:  "# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- Example:\n  ```\n- Crash on start\n  ```\n",
+			section:         "Fixed",
+			content:         "Crash on start",
+			expectedContent: "# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- Example:\n  ```\n- Crash on start\n  ```\n- Crash on start\n",
+		},
+		{
+			name:            "Duplicate top-level entry with sub-bullets",
+			initialContent:  "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Existing feature\n  - Detail\n",
+			section:         "Added",
+			content:         "Existing feature",
+			expectedContent: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Existing feature\n  - Detail\n",
+			expectDuplicate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tmpfile.Name())
+
+			if _, err := tmpfile.Write([]byte(tt.initialContent)); err != nil {
+				t.Fatal(err)
+			}
+
+			isDuplicate, err := AddChangelogSection(tmpfile.Name(), tt.section, tt.content)
+			if err != nil {
+				t.Fatalf("AddChangelogSection failed: %v", err)
+			}
+
+			if isDuplicate != tt.expectDuplicate {
+				t.Errorf("Expected isDuplicate to be %v, got %v", tt.expectDuplicate, isDuplicate)
+			}
+
+			content, err := os.ReadFile(tmpfile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != tt.expectedContent {
+				t.Errorf("Changelog content doesn't match expected.\nGot:\n%s\nExpected:\n%s", string(content), tt.expectedContent)
+			}
+		})
+	}
+}
+
 func compareIgnoreWhitespace(a, b string) bool {
 	a = strings.Join(strings.Fields(strings.TrimSpace(a)), " ")
 	b = strings.Join(strings.Fields(strings.TrimSpace(b)), " ")
@@ -484,3 +736,663 @@ func TestUpdateChangelogFormatting(t *testing.T) {
 		t.Errorf("UpdateChangelog produced incorrect output.\nExpected:\n%s\n\nGot:\n%s", expectedContent, string(updatedContent))
 	}
 }
+
+func TestUpdateChangelogCanonicalOrder(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Bug fix
+
+### Added
+
+- New feature
+
+## [1.0.0] - 2023-01-01
+
+### Fixed
+
+- Old fix
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+
+	expectedContent := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - ` + time.Now().Format("2006-01-02") + `
+
+### Added
+
+- New feature
+
+### Fixed
+
+- Bug fix
+
+## [1.0.0] - 2023-01-01
+
+### Fixed
+
+- Old fix
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.1.0...HEAD
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateChangelogWithOptions(tmpfile.Name(), "1.1.0", UpdateOptions{Provider: "github", CanonicalOrder: true})
+	if err != nil {
+		t.Fatalf("UpdateChangelogWithOptions failed: %v", err)
+	}
+
+	updatedContent, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(updatedContent) != expectedContent {
+		t.Errorf("UpdateChangelogWithOptions produced incorrect output.\nExpected:\n%s\n\nGot:\n%s", expectedContent, string(updatedContent))
+	}
+}
+
+func TestUpdateChangelogStrictDuplicateVersions(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+## [1.0.0] - 2023-01-01
+
+## [1.0.0] - 2023-01-01
+`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateChangelogWithOptions(tmpfile.Name(), "1.1.0", UpdateOptions{Provider: "github", Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "duplicate version header [1.0.0] on lines 5, 7") {
+		t.Errorf("Expected duplicate version error, got: %v", err)
+	}
+
+	content, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != initialContent {
+		t.Errorf("Changelog was modified despite duplicate version headers:\n%s", string(content))
+	}
+}
+
+func TestAddChangelogSectionWithWrapping(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Existing entry that was wrapped
+  over two lines
+`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := AddOptions{WrapWidth: 24}
+
+	isDuplicate, err := AddChangelogSectionWithOptions(tmpfile.Name(), "Added", "Existing entry that was wrapped over two lines", opts)
+	if err != nil {
+		t.Fatalf("AddChangelogSectionWithOptions failed: %v", err)
+	}
+	if !isDuplicate {
+		t.Error("Expected wrapped entry to be detected as duplicate")
+	}
+
+	isDuplicate, err = AddChangelogSectionWithOptions(tmpfile.Name(), "Added", "A new entry that needs wrapping", opts)
+	if err != nil {
+		t.Fatalf("AddChangelogSectionWithOptions failed: %v", err)
+	}
+	if isDuplicate {
+		t.Error("Expected new entry not to be a duplicate")
+	}
+
+	content, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Existing entry that was wrapped
+  over two lines
+- A new entry that needs
+  wrapping
+`
+	if string(content) != expectedContent {
+		t.Errorf("Changelog content doesn't match expected.\nGot:\n%s\nExpected:\n%s", string(content), expectedContent)
+	}
+}
+
+func TestUnreleasedWithPlaceholderDate(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased] - TBD
+
+### Added
+
+- Existing feature
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	isDuplicate, err := AddChangelogSection(tmpfile.Name(), "Fixed", "Bug fix")
+	if err != nil {
+		t.Fatalf("AddChangelogSection failed: %v", err)
+	}
+	if isDuplicate {
+		t.Error("Expected new entry not to be a duplicate")
+	}
+
+	isDuplicate, err = AddChangelogSection(tmpfile.Name(), "Added", "Existing feature")
+	if err != nil {
+		t.Fatalf("AddChangelogSection failed: %v", err)
+	}
+	if !isDuplicate {
+		t.Error("Expected existing entry under the dated Unreleased header to be a duplicate")
+	}
+
+	if err := UpdateChangelog(tmpfile.Name(), "1.1.0", "github"); err != nil {
+		t.Fatalf("UpdateChangelog failed: %v", err)
+	}
+	if err := ReformatChangelog(tmpfile.Name()); err != nil {
+		t.Fatalf("ReformatChangelog failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent := `# Changelog
+
+## [Unreleased] - TBD
+
+## [1.1.0] - ` + time.Now().Format("2006-01-02") + `
+
+### Added
+
+- Existing feature
+
+### Fixed
+
+- Bug fix
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.1.0...HEAD
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+	if string(content) != expectedContent {
+		t.Errorf("Unexpected changelog content.\nExpected:\n%s\n\nGot:\n%s", expectedContent, string(content))
+	}
+}
+
+func TestValidSections(t *testing.T) {
+	sections := ValidSections()
+	expected := []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+	if strings.Join(sections, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, sections)
+	}
+
+	sections[0] = "Modified"
+	if ValidSections()[0] != "Added" {
+		t.Error("Expected ValidSections to return a copy")
+	}
+}
+
+func TestGetVersionSection(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature A
+  - Detail
+
+### Fixed
+
+- Bug fix
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+
+	notes, err := GetVersionSection(content, "1.1.0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "### Added\n\n- Feature A\n  - Detail\n\n### Fixed\n\n- Bug fix\n"
+	if notes != expected {
+		t.Errorf("Expected %q, got %q", expected, notes)
+	}
+
+	if _, err := GetVersionSection(content, "2.0.0"); err == nil {
+		t.Error("Expected error for missing version, got nil")
+	}
+}
+
+func TestGetLatestStableVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Skips prereleases",
+			content:  "## [Unreleased]\n\n## [1.2.0-rc.1] - 2024-03-01\n\n## [1.1.0] - 2024-02-01\n\n## [1.0.0] - 2024-01-01\n",
+			expected: "1.1.0",
+		},
+		{
+			name:     "Ignores build metadata",
+			content:  "## [1.1.0+build.5] - 2024-02-01\n\n## [1.0.0] - 2024-01-01\n",
+			expected: "1.1.0",
+		},
+		{
+			name:     "Out of order headers",
+			content:  "## [1.0.0] - 2024-01-01\n\n## [1.10.0] - 2024-03-01\n\n## [1.9.0] - 2024-02-01\n",
+			expected: "1.10.0",
+		},
+		{
+			name:     "Skips malformed headers",
+			content:  "## [next] - TBD\n\n## [1.0] - 2024-02-01\n\n## [0.9.0] - 2024-01-01\n",
+			expected: "0.9.0",
+		},
+		{
+			name:    "Only prereleases",
+			content: "## [Unreleased]\n\n## [1.0.0-beta.1] - 2024-01-01\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := GetLatestStableVersion(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLatestStableVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.expected {
+				t.Errorf("GetLatestStableVersion() = %q, want %q", version, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateChangelogTagLinkStyle(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- New feature
+
+## [1.0.0] - 2023-01-01
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateChangelogWithOptions(tmpfile.Name(), "1.1.0", UpdateOptions{Provider: "github", LinkStyle: "tag"})
+	if err != nil {
+		t.Fatalf("UpdateChangelogWithOptions failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLinks := `[Unreleased]: https://github.com/peiman/changie/compare/1.1.0...HEAD
+[1.1.0]: https://github.com/peiman/changie/releases/tag/1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+	if !strings.HasSuffix(string(content), expectedLinks) {
+		t.Errorf("Expected links:\n%s\n\nGot:\n%s", expectedLinks, string(content))
+	}
+}
+
+func TestUpdateChangelogMixedTagPrefixes(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- New feature
+
+## [1.1.0] - 2023-02-01
+
+## [1.0.0] - 2023-01-01
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.1.0...HEAD
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+
+	tests := []struct {
+		name          string
+		tags          map[string]string
+		expectedLinks string
+	}{
+		{
+			name: "Migrated to prefixed tags",
+			tags: map[string]string{"1.0.0": "1.0.0", "1.1.0": "v1.1.0"},
+			expectedLinks: `[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD
+[1.2.0]: https://github.com/peiman/changie/compare/v1.1.0...1.2.0
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...v1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`,
+		},
+		{
+			name: "Migrated to unprefixed tags",
+			tags: map[string]string{"1.0.0": "v1.0.0", "1.1.0": "1.1.0"},
+			expectedLinks: `[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+[1.1.0]: https://github.com/peiman/changie/compare/v1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/v1.0.0`,
+		},
+		{
+			name: "No tags",
+			expectedLinks: `[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := UpdateChangelogWithOptions(file, "1.2.0", UpdateOptions{Provider: "github", Tags: tt.tags}); err != nil {
+				t.Fatalf("UpdateChangelogWithOptions failed: %v", err)
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(string(content), tt.expectedLinks) {
+				t.Errorf("Expected links:\n%s\n\nGot:\n%s", tt.expectedLinks, content)
+			}
+		})
+	}
+}
+
+func TestUpdateChangelogVersionHeaderTemplate(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- New feature
+
+## [1.0.0] - 2023-01-01 (stable)
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0`
+
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := UpdateOptions{Provider: "github", VersionHeader: "## [{{.Version}}] - {{.Date}} ({{.Channel}})", Channel: "stable"}
+	if err := UpdateChangelogWithOptions(file, "1.1.0", opts); err != nil {
+		t.Fatalf("UpdateChangelogWithOptions failed: %v", err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHeader := fmt.Sprintf("## [1.1.0] - %s (stable)\n", time.Now().Format("2006-01-02"))
+	if !strings.Contains(string(content), expectedHeader) {
+		t.Errorf("Expected header %q, got:\n%s", expectedHeader, content)
+	}
+	if version, err := GetLatestChangelogVersion(string(content)); err != nil || version != "1.1.0" {
+		t.Errorf("GetLatestChangelogVersion() = %q, %v, want 1.1.0", version, err)
+	}
+
+	opts.VersionHeader = "Release {{.Version}}"
+	if err := UpdateChangelogWithOptions(file, "1.2.0", opts); err == nil {
+		t.Error("Expected an error for a template that doesn't yield a version header")
+	}
+}
+
+func TestUpdateChangelogAzureLinks(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- New feature
+
+## [1.0.0] - 2023-01-01
+
+[Unreleased]: https://dev.azure.com/org/project/_git/repo/branchCompare?baseVersion=GT1.0.0&targetVersion=GBmain
+[1.0.0]: https://dev.azure.com/org/project/_git/repo?version=GT1.0.0`
+
+	// Unreleased is compared with the default branch, main when it's unknown
+	for branch, target := range map[string]string{"": "GBmain", "master": "GBmaster", "develop": "GBdevelop"} {
+		t.Run(target, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := UpdateChangelogWithOptions(file, "1.1.0", UpdateOptions{
+				Provider:      "azure",
+				RepositoryURL: "https://dev.azure.com/org/project/_git/repo",
+				DefaultBranch: branch,
+			})
+			if err != nil {
+				t.Fatalf("UpdateChangelogWithOptions failed: %v", err)
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedLinks := `[Unreleased]: https://dev.azure.com/org/project/_git/repo/branchCompare?baseVersion=GT1.1.0&targetVersion=` + target + `
+[1.1.0]: https://dev.azure.com/org/project/_git/repo/branchCompare?baseVersion=GT1.0.0&targetVersion=GT1.1.0
+[1.0.0]: https://dev.azure.com/org/project/_git/repo?version=GT1.0.0`
+			if !strings.HasSuffix(string(content), expectedLinks) {
+				t.Errorf("Expected links:\n%s\n\nGot:\n%s", expectedLinks, string(content))
+			}
+		})
+	}
+}
+
+func TestSetUnreleasedDate(t *testing.T) {
+	content := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- New feature\n\n## [1.0.0] - 2024-01-01\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		date     string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Set date",
+			content:  content,
+			date:     "2024-07-01",
+			expected: strings.Replace(content, "## [Unreleased]", "## [Unreleased] - 2024-07-01", 1),
+		},
+		{
+			name:     "Replace placeholder",
+			content:  strings.Replace(content, "## [Unreleased]", "## [Unreleased] - TBD", 1),
+			date:     "2024-07-01",
+			expected: strings.Replace(content, "## [Unreleased]", "## [Unreleased] - 2024-07-01", 1),
+		},
+		{
+			name:     "Clear date",
+			content:  strings.Replace(content, "## [Unreleased]", "## [Unreleased] - 2024-07-01", 1),
+			date:     "",
+			expected: content,
+		},
+		{
+			name:    "Invalid date",
+			content: content,
+			date:    "July 1st",
+			wantErr: true,
+		},
+		{
+			name:    "No Unreleased section",
+			content: "# Changelog\n\n## [1.0.0] - 2024-01-01\n",
+			date:    "2024-07-01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SetUnreleasedDate(tt.content, tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetUnreleasedDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("SetUnreleasedDate() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateChangelogStripsScheduledDate(t *testing.T) {
+	initialContent := `# Changelog
+
+## [Unreleased] - 2024-07-01
+
+### Added
+
+- New feature
+`
+
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateChangelog(tmpfile.Name(), "1.0.0", "github"); err != nil {
+		t.Fatalf("UpdateChangelog failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeaders := "## [Unreleased]\n\n## [1.0.0] - " + time.Now().Format("2006-01-02") + "\n"
+	if !strings.Contains(string(content), expectedHeaders) {
+		t.Errorf("Expected the scheduled date to be removed from Unreleased, got:\n%s", string(content))
+	}
+}
@@ -0,0 +1,120 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultDateFormat is the Keep a Changelog release date format, YYYY-MM-DD
+const DefaultDateFormat = "2006-01-02"
+
+// dateLayouts are the release date formats NormalizeDates understands.
+// Numeric formats with the day before the month or the month before the day
+// are ambiguous and not accepted.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-1-2",
+	"2006/01/02",
+	"2006/1/2",
+	"2006.01.02",
+	"2006.1.2",
+	"20060102",
+	time.RFC3339,
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// yankedSuffix marks a release that was pulled, e.g. "## [1.0.0] - 2024-01-01 [YANKED]"
+const yankedSuffix = " [YANKED]"
+
+// DatesResult is the result of normalizing the release dates of a changelog
+type DatesResult struct {
+	Fixes    []string // Dates rewritten in the target format
+	Warnings []string // Dates that couldn't be parsed and were left as they are
+	Content  string   // Changelog content with the dates rewritten
+}
+
+// NormalizeDates rewrites the release dates of the version headers in the
+// target format, a Go time layout such as DefaultDateFormat. Dates in any of
+// the formats of dateLayouts are understood. Dates that can't be parsed are
+// left as they are. Unreleased and code blocks are skipped.
+func NormalizeDates(content, targetFormat string) (string, error) {
+	result, err := normalizeDates(content, targetFormat)
+	return result.Content, err
+}
+
+func normalizeDates(content, targetFormat string) (DatesResult, error) {
+	if targetFormat == "" {
+		targetFormat = DefaultDateFormat
+	}
+	sample := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	if parsed, err := time.Parse(targetFormat, sample.Format(targetFormat)); err != nil || !parsed.Equal(sample) {
+		return DatesResult{}, fmt.Errorf("invalid date format %q, expected a Go time layout such as %s", targetFormat, DefaultDateFormat)
+	}
+
+	result := DatesResult{}
+	lines := strings.Split(content, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		matches := versionHeaderRegex.FindStringSubmatch(trimmedLine)
+		if inCodeBlock || matches == nil || matches[1] == "Unreleased" || strings.TrimSpace(matches[2]) == "" {
+			continue
+		}
+
+		date := strings.TrimSpace(matches[2])
+		suffix := ""
+		if strings.HasSuffix(strings.ToUpper(date), yankedSuffix) {
+			date, suffix = date[:len(date)-len(yankedSuffix)], date[len(date)-len(yankedSuffix):]
+		}
+		parsed, ok := parseDate(date)
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("line %d: can't parse the date %q of [%s]", i+1, date, matches[1]))
+			continue
+		}
+		if formatted := parsed.Format(targetFormat); formatted != date {
+			lines[i] = fmt.Sprintf("## [%s] - %s%s", matches[1], formatted, suffix)
+			result.Fixes = append(result.Fixes, fmt.Sprintf("[%s] %s -> %s", matches[1], date, formatted))
+		}
+	}
+
+	result.Content = strings.Join(lines, "\n")
+	return result, nil
+}
+
+// parseDate parses a release date in any of the formats of dateLayouts
+func parseDate(date string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FixDates normalizes the release dates of the changelog file, see
+// NormalizeDates. With check set, the file is left untouched.
+func FixDates(changelogFile, targetFormat string, check bool) (DatesResult, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return DatesResult{}, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	result, err := normalizeDates(string(content), targetFormat)
+	if err != nil || check || len(result.Fixes) == 0 {
+		return result, err
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(result.Content), 0644); err != nil {
+		return result, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return result, nil
+}
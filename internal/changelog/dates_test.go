@@ -0,0 +1,104 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDates(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased] - TBD
+
+## [1.3.0] - 2024-3-5
+
+## [1.2.0] - March 1, 2024 [YANKED]
+
+## [1.1.0] - soon
+
+` + "```" + `
+## [0.1.0] - 2020-1-1
+` + "```" + `
+
+## [1.0.0] - 2024/01/02
+`
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "Default format",
+			expected: strings.NewReplacer("2024-3-5", "2024-03-05", "March 1, 2024", "2024-03-01", "2024/01/02", "2024-01-02").Replace(content),
+		},
+		{
+			name:     "Custom format",
+			format:   "02.01.2006",
+			expected: strings.NewReplacer("2024-3-5", "05.03.2024", "March 1, 2024", "01.03.2024", "2024/01/02", "02.01.2024").Replace(content),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeDates(content, tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("NormalizeDates() =\n%s\nwant\n%s", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Fixes and warnings", func(t *testing.T) {
+		result, err := normalizeDates(content, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expectedFixes := []string{"[1.3.0] 2024-3-5 -> 2024-03-05", "[1.2.0] March 1, 2024 -> 2024-03-01", "[1.0.0] 2024/01/02 -> 2024-01-02"}
+		if !reflect.DeepEqual(result.Fixes, expectedFixes) {
+			t.Errorf("Fixes = %v, want %v", result.Fixes, expectedFixes)
+		}
+		expectedWarnings := []string{`line 9: can't parse the date "soon" of [1.1.0]`}
+		if !reflect.DeepEqual(result.Warnings, expectedWarnings) {
+			t.Errorf("Warnings = %v, want %v", result.Warnings, expectedWarnings)
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		if _, err := NormalizeDates(content, "YYYY-MM-DD"); err == nil || !strings.Contains(err.Error(), "invalid date format") {
+			t.Errorf("Expected an invalid date format error, got: %v", err)
+		}
+	})
+}
+
+func TestFixDates(t *testing.T) {
+	content := "## [1.0.0] - 2024-1-1\n"
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := FixDates(file, "", true)
+	if err != nil || len(result.Fixes) != 1 {
+		t.Fatalf("FixDates() with check = %v, %v, want one fix", result, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != content {
+		t.Errorf("Check changed the changelog: %q", data)
+	}
+
+	if _, err := FixDates(file, "", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "## [1.0.0] - 2024-01-01\n" {
+		t.Errorf("Unexpected content: %q", data)
+	}
+
+	if _, err := FixDates(filepath.Join(t.TempDir(), "missing.md"), "", false); err == nil {
+		t.Error("Expected an error for a missing changelog")
+	}
+}
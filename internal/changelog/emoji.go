@@ -0,0 +1,59 @@
+package changelog
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSectionEmoji maps changelog sections to their conventional gitmoji
+var DefaultSectionEmoji = map[string]string{
+	"Added":      "✨",
+	"Changed":    "♻️",
+	"Deprecated": "🗑️",
+	"Removed":    "🔥",
+	"Fixed":      "🐛",
+	"Security":   "🔒️",
+}
+
+// AddEmojiPrefix prepends the emoji mapped to the section to the entry content.
+// Overrides take precedence over DefaultSectionEmoji. Content that already
+// starts with an emoji, or a section without a mapping, is returned unchanged.
+func AddEmojiPrefix(section, content string, overrides map[string]string) string {
+	emoji, ok := overrides[section]
+	if !ok {
+		emoji = DefaultSectionEmoji[section]
+	}
+	if emoji == "" || stripEmojiPrefix(content) != content {
+		return content
+	}
+	return emoji + " " + content
+}
+
+// stripEmojiPrefix removes a leading emoji and the space following it
+func stripEmojiPrefix(text string) string {
+	rest := strings.TrimLeftFunc(text, func(r rune) bool {
+		return unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) || r == '\u200d' || r == '\ufe0f'
+	})
+	if rest == text || !strings.HasPrefix(rest, " ") {
+		return text
+	}
+	return strings.TrimLeft(rest, " ")
+}
+
+// normalizeEntry returns the entry text used for duplicate detection, without
+// the emoji prefix and the metadata annotation
+func normalizeEntry(entry string) string {
+	text, _ := SplitMetadata(strings.TrimPrefix(entry, "- "))
+	return stripEmojiPrefix(text)
+}
+
+// containsEntry reports whether entries already holds an entry equivalent to entry
+func containsEntry(entries []string, entry string) bool {
+	normalized := normalizeEntry(entry)
+	for _, e := range entries {
+		if normalizeEntry(e) == normalized {
+			return true
+		}
+	}
+	return false
+}
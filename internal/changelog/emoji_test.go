@@ -0,0 +1,42 @@
+package changelog
+
+import (
+	"testing"
+)
+
+func TestAddEmojiPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		section   string
+		content   string
+		overrides map[string]string
+		expected  string
+	}{
+		{"Default mapping", "Fixed", "Crash on start", nil, "🐛 Crash on start"},
+		{"Override mapping", "Fixed", "Crash on start", map[string]string{"Fixed": "🚑️"}, "🚑️ Crash on start"},
+		{"Unknown section", "Internal", "Refactor", nil, "Refactor"},
+		{"Already prefixed", "Added", "✨ New feature", nil, "✨ New feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddEmojiPrefix(tt.section, tt.content, tt.overrides); got != tt.expected {
+				t.Errorf("AddEmojiPrefix(%q, %q) = %q, expected %q", tt.section, tt.content, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContainsEntryIgnoresEmoji(t *testing.T) {
+	entries := []string{"- 🐛 Crash on start", "- Plain entry"}
+
+	if !containsEntry(entries, "- Crash on start") {
+		t.Error("Expected entry without emoji to match entry with emoji")
+	}
+	if !containsEntry(entries, "- 🐛 Plain entry") {
+		t.Error("Expected entry with emoji to match entry without emoji")
+	}
+	if containsEntry(entries, "- Another entry") {
+		t.Error("Expected different entry not to match")
+	}
+}
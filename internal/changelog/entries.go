@@ -0,0 +1,128 @@
+package changelog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/peiman/changie/internal/semver"
+)
+
+// maxLineLength is the longest changelog line ScanEntries accepts
+const maxLineLength = 16 * 1024 * 1024
+
+// Entries returns all entries of the changelog with their version, date and
+// section, in file order. Nested lines are joined to the entry text.
+func (c *Changelog) Entries() []Match {
+	entries := []Match{}
+	for _, v := range c.Versions {
+		for _, s := range v.Sections {
+			for _, e := range s.Entries {
+				entries = append(entries, Match{Version: v.Name, Date: v.Date, Section: s.Name, Entry: e.joinedText()})
+			}
+		}
+	}
+	return entries
+}
+
+// EntriesBetween returns the entries of the released versions after from up
+// to and including to, in file order, e.g. for the changes between two tags.
+// With includeFrom the from version is included, and with excludeTo the to
+// version is left out. Versions may have a "v" prefix.
+func (c *Changelog) EntriesBetween(from, to string, includeFrom, excludeTo bool) ([]Match, error) {
+	versions, err := c.VersionsBetween(from, to, includeFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Match{}
+	for _, v := range versions {
+		if order, _ := semver.Compare(v.Name, to); excludeTo && order == 0 {
+			continue
+		}
+		for _, s := range v.Sections {
+			for _, e := range s.Entries {
+				entries = append(entries, Match{Version: v.Name, Date: v.Date, Section: s.Name, Entry: e.joinedText()})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ScanEntries reads the changelog line by line and calls fn for every entry
+// as soon as it is complete, so large changelogs don't need to be held in
+// memory. The entries are the same as those of Parse(content).Entries().
+// An error returned by fn stops the scan and is returned.
+func ScanEntries(r io.Reader, fn func(Match) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+
+	var version, date, section string
+	var entry *Match
+	inVersion, inSection, inCodeBlock := false, false, false
+	flush := func() error {
+		if entry == nil {
+			return nil
+		}
+		m := *entry
+		entry = nil
+		return fn(m)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmedLine := strings.TrimSpace(line)
+
+		if inCodeBlock || isCodeFence(trimmedLine) {
+			if isCodeFence(trimmedLine) {
+				inCodeBlock = !inCodeBlock
+			}
+			if entry != nil && trimmedLine != "" {
+				entry.Entry += " " + trimmedLine
+			}
+			continue
+		}
+
+		if matches := versionHeaderRegex.FindStringSubmatch(trimmedLine); matches != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+			version, date = matches[1], strings.TrimSpace(matches[2])
+			inVersion, inSection = true, false
+			continue
+		}
+		if !inVersion {
+			continue
+		}
+		if linkLineRegex.MatchString(trimmedLine) {
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "### ") {
+			if err := flush(); err != nil {
+				return err
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+			inSection = true
+			continue
+		}
+		if trimmedLine == "" || !inSection {
+			continue
+		}
+
+		if line == trimmedLine && isEntryLine(line) {
+			if err := flush(); err != nil {
+				return err
+			}
+			entry = &Match{Version: version, Date: date, Section: section, Entry: line[2:]}
+		} else if entry != nil {
+			entry.Entry += " " + trimmedLine
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
@@ -0,0 +1,187 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const entriesContent = `# Changelog
+
+- Not an entry, the header has no versions
+
+## [Unreleased]
+
+Notes before the first section
+
+### Added
+
+- Wrapped entry that continues
+  on the next line
+- Entry with code:
+
+  ` + "```" + `
+  - not an entry
+  ` + "```" + `
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix A [severity=high]
+Section text that isn't an entry
+* Fix B
++ Fix C
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+- Entry after a link
+`
+
+func TestEntries(t *testing.T) {
+	expected := []Match{
+		{Version: "Unreleased", Section: "Added", Entry: "Wrapped entry that continues on the next line"},
+		{Version: "Unreleased", Section: "Added", Entry: "Entry with code: ``` - not an entry ```"},
+		{Version: "1.0.0", Date: "2024-01-01", Section: "Fixed", Entry: "Fix A [severity=high] Section text that isn't an entry"},
+		{Version: "1.0.0", Date: "2024-01-01", Section: "Fixed", Entry: "Fix B"},
+		{Version: "1.0.0", Date: "2024-01-01", Section: "Fixed", Entry: "Fix C"},
+		{Version: "1.0.0", Date: "2024-01-01", Section: "Fixed", Entry: "Entry after a link"},
+	}
+
+	if entries := Parse(entriesContent).Entries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Entries() = %v, want %v", entries, expected)
+	}
+
+	var scanned []Match
+	err := ScanEntries(strings.NewReader(entriesContent), func(m Match) error {
+		scanned = append(scanned, m)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanEntries() error = %v", err)
+	}
+	if !reflect.DeepEqual(scanned, expected) {
+		t.Errorf("ScanEntries() = %v, want %v", scanned, expected)
+	}
+}
+
+func TestEntriesBetween(t *testing.T) {
+	c := Parse(`# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Unreleased feature
+
+## [1.2.0] - 2024-03-01
+
+### Added
+
+- Feature C
+
+### Fixed
+
+- Fix B
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Fix A
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`)
+	fixA := Match{Version: "1.1.0", Date: "2024-02-01", Section: "Fixed", Entry: "Fix A"}
+	featureC := Match{Version: "1.2.0", Date: "2024-03-01", Section: "Added", Entry: "Feature C"}
+	fixB := Match{Version: "1.2.0", Date: "2024-03-01", Section: "Fixed", Entry: "Fix B"}
+	initial := Match{Version: "1.0.0", Date: "2024-01-01", Section: "Added", Entry: "Initial release"}
+
+	tests := []struct {
+		name        string
+		from, to    string
+		includeFrom bool
+		excludeTo   bool
+		expected    []Match
+		wantErr     bool
+	}{
+		{name: "After from up to to", from: "1.0.0", to: "1.2.0", expected: []Match{featureC, fixB, fixA}},
+		{name: "Tags with a v prefix", from: "v1.0.0", to: "v1.2.0", expected: []Match{featureC, fixB, fixA}},
+		{name: "Include from", from: "1.0.0", to: "1.1.0", includeFrom: true, expected: []Match{fixA, initial}},
+		{name: "Strictly between", from: "1.0.0", to: "1.2.0", excludeTo: true, expected: []Match{fixA}},
+		{name: "Empty range", from: "1.1.0", to: "1.1.0", expected: []Match{}},
+		{name: "Reversed range", from: "1.2.0", to: "1.0.0", wantErr: true},
+		{name: "Invalid version", from: "main", to: "1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := c.EntriesBetween(tt.from, tt.to, tt.includeFrom, tt.excludeTo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EntriesBetween() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(entries, tt.expected) {
+				t.Errorf("EntriesBetween() = %v, want %v", entries, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScanEntriesStopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := ScanEntries(strings.NewReader(entriesContent), func(Match) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("ScanEntries() = %v after %d calls, want the callback error after 1 call", err, calls)
+	}
+}
+
+// largeChangelog returns a changelog of several megabytes
+func largeChangelog() string {
+	var b strings.Builder
+	b.WriteString("# Changelog\n\n## [Unreleased]\n")
+	for i := 5000; i > 0; i-- {
+		fmt.Fprintf(&b, "\n## [%d.0.0] - 2024-01-01\n", i)
+		for _, section := range ValidSections()[:4] {
+			fmt.Fprintf(&b, "\n### %s\n\n", section)
+			for j := 0; j < 5; j++ {
+				fmt.Fprintf(&b, "- Change %d of release %d with a description that is long\n  enough to wrap onto a second line\n", j, i)
+			}
+		}
+	}
+	return b.String()
+}
+
+func BenchmarkScanEntries(b *testing.B) {
+	content := largeChangelog()
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		if err := ScanEntries(strings.NewReader(content), func(Match) error {
+			count++
+			return nil
+		}); err != nil || count != 100000 {
+			b.Fatalf("ScanEntries() = %d entries, %v", count, err)
+		}
+	}
+}
+
+func BenchmarkParseEntries(b *testing.B) {
+	content := largeChangelog()
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if entries := Parse(content).Entries(); len(entries) != 100000 {
+			b.Fatalf("Entries() = %d entries", len(entries))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// EntryData is available as {{.Text}}, {{.Issue}} and {{.Section}} in entry
+// templates
+type EntryData struct {
+	Text    string
+	Issue   string
+	Section string
+}
+
+// EntryTemplates render new entries with a template per section
+type EntryTemplates map[string]*template.Template
+
+// ParseEntryTemplates parses entry templates by section, e.g. Fixed mapped to
+// "{{.Text}} (fixes #{{.Issue}})". The sections must be Keep a Changelog
+// sections, and each template is rendered with sample data so mistakes are
+// reported before an entry is added.
+func ParseEntryTemplates(templates map[string]string) (EntryTemplates, error) {
+	parsed := EntryTemplates{}
+	for section, text := range templates {
+		if !contains(sectionOrder, section) {
+			return nil, fmt.Errorf("unknown section %s, expected one of %s", section, strings.Join(sectionOrder, ", "))
+		}
+		sample := EntryData{Text: "Fix login", Issue: "123", Section: section}
+		tmpl, err := validateTemplate(section+" entry", text, sample)
+		if err != nil {
+			return nil, err
+		}
+		parsed[section] = tmpl
+
+		entry, err := parsed.Render(section, sample)
+		if err != nil {
+			return nil, err
+		}
+		if entry == "" || strings.Contains(entry, "\n") {
+			return nil, fmt.Errorf("entry template for %s must yield a single line, got %q", section, entry)
+		}
+	}
+	return parsed, nil
+}
+
+// Render returns the entry for the section. Sections without a template use
+// the text as is.
+func (t EntryTemplates) Render(section string, data EntryData) (string, error) {
+	tmpl, ok := t[section]
+	if !ok {
+		return data.Text, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering %s template: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
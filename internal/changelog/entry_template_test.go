@@ -0,0 +1,55 @@
+package changelog
+
+import "testing"
+
+func TestParseEntryTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates map[string]string
+		wantErr   bool
+	}{
+		{"No templates", nil, false},
+		{"Valid", map[string]string{"Fixed": "{{.Text}} (fixes #{{.Issue}})", "Added": "{{.Text}}{{if .Issue}} (#{{.Issue}}){{end}}"}, false},
+		{"Unknown section", map[string]string{"Bugs": "{{.Text}}"}, true},
+		{"Syntax error", map[string]string{"Fixed": "{{.Text"}, true},
+		{"Unknown field", map[string]string{"Fixed": "{{.Title}}"}, true},
+		{"Empty result", map[string]string{"Fixed": "{{if .Issue}}{{end}}"}, true},
+		{"Multiple lines", map[string]string{"Fixed": "{{.Text}}\nsee #{{.Issue}}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEntryTemplates(tt.templates); (err != nil) != tt.wantErr {
+				t.Errorf("ParseEntryTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryTemplatesRender(t *testing.T) {
+	templates, err := ParseEntryTemplates(map[string]string{
+		"Fixed": "{{.Text}} (fixes #{{.Issue}})",
+		"Added": "{{.Text}}{{if .Issue}} (#{{.Issue}}){{end}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		section  string
+		data     EntryData
+		expected string
+	}{
+		{"Fixed", EntryData{Text: "Fix login", Issue: "42"}, "Fix login (fixes #42)"},
+		{"Added", EntryData{Text: "Add export"}, "Add export"},
+		{"Added", EntryData{Text: "Add export", Issue: "7"}, "Add export (#7)"},
+		{"Changed", EntryData{Text: "Change defaults", Issue: "9"}, "Change defaults"},
+	}
+
+	for _, tt := range tests {
+		got, err := templates.Render(tt.section, tt.data)
+		if err != nil || got != tt.expected {
+			t.Errorf("Render(%s, %+v) = %q, %v, want %q", tt.section, tt.data, got, err, tt.expected)
+		}
+	}
+}
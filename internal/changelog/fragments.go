@@ -0,0 +1,104 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Fragment is a changelog entry kept in its own file until release.
+// Fragment files are named <id>.<section>.md, e.g. 123.added.md, where the
+// section is one of the Keep a Changelog sections in any letter case.
+type Fragment struct {
+	Path    string
+	ID      string
+	Section string
+	Entries []string
+}
+
+// ParseFragmentName extracts the id and the canonical section name from a fragment file name
+func ParseFragmentName(name string) (string, string, error) {
+	parts := strings.Split(strings.TrimSuffix(name, ".md"), ".")
+	if !strings.HasSuffix(name, ".md") || len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid fragment name %s, expected <id>.<section>.md", name)
+	}
+	for _, section := range sectionOrder {
+		if strings.EqualFold(parts[1], section) {
+			return parts[0], section, nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid fragment name %s, unknown section %s", name, parts[1])
+}
+
+// ReadFragments reads all fragment files in dir, ordered by section and id.
+// Each bullet line in a fragment is an entry; a fragment without bullets is a single entry.
+func ReadFragments(dir string) ([]Fragment, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fragment directory: %w", err)
+	}
+
+	var fragments []Fragment
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		id, section, err := ParseFragmentName(file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, file.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fragment: %w", err)
+		}
+		entries := parseFragmentEntries(string(content))
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("fragment %s is empty", file.Name())
+		}
+		fragments = append(fragments, Fragment{Path: path, ID: id, Section: section, Entries: entries})
+	}
+
+	sort.SliceStable(fragments, func(i, j int) bool {
+		if fragments[i].Section != fragments[j].Section {
+			return sectionRank(fragments[i].Section) < sectionRank(fragments[j].Section)
+		}
+		return fragments[i].ID < fragments[j].ID
+	})
+	return fragments, nil
+}
+
+// parseFragmentEntries splits fragment content into entries
+func parseFragmentEntries(content string) []string {
+	var entries []string
+	var text []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		switch {
+		case isEntryLine(trimmedLine):
+			entries = append(entries, trimmedLine[2:])
+		case trimmedLine == "":
+		case len(entries) > 0:
+			entries[len(entries)-1] += " " + trimmedLine
+		default:
+			text = append(text, trimmedLine)
+		}
+	}
+	if len(entries) == 0 && len(text) > 0 {
+		entries = append(entries, strings.Join(text, " "))
+	}
+	return entries
+}
+
+// sectionRank returns the position of the section in the canonical order
+func sectionRank(section string) int {
+	for i, s := range sectionOrder {
+		if s == section {
+			return i
+		}
+	}
+	return len(sectionOrder)
+}
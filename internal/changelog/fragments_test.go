@@ -0,0 +1,98 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFragmentName(t *testing.T) {
+	tests := []struct {
+		name            string
+		expectedID      string
+		expectedSection string
+		expectError     bool
+	}{
+		{"123.added.md", "123", "Added", false},
+		{"fix-login.Fixed.md", "fix-login", "Fixed", false},
+		{"123.md", "", "", true},
+		{"123.improved.md", "", "", true},
+		{"123.added.txt", "", "", true},
+	}
+
+	for _, tt := range tests {
+		id, section, err := ParseFragmentName(tt.name)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseFragmentName(%s) expected an error, got none", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseFragmentName(%s) returned an error: %v", tt.name, err)
+		}
+		if id != tt.expectedID || section != tt.expectedSection {
+			t.Errorf("ParseFragmentName(%s) = %s, %s, expected %s, %s", tt.name, id, section, tt.expectedID, tt.expectedSection)
+		}
+	}
+}
+
+func TestReadFragments(t *testing.T) {
+	dir, err := os.MkdirTemp("", "changie-fragments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"2.fixed.md":   "Crash on start\nwhen offline\n",
+		"1.fixed.md":   "- First fix\n- Second fix\n",
+		"3.added.md":   "New feature\n",
+		".gitkeep":     "",
+		"README.txt":   "Fragments go here",
+		"4.Changed.md": "- Changed behavior",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fragments, err := ReadFragments(dir)
+	if err != nil {
+		t.Fatalf("ReadFragments failed: %v", err)
+	}
+
+	expected := []struct {
+		id      string
+		section string
+		entries []string
+	}{
+		{"3", "Added", []string{"New feature"}},
+		{"4", "Changed", []string{"Changed behavior"}},
+		{"1", "Fixed", []string{"First fix", "Second fix"}},
+		{"2", "Fixed", []string{"Crash on start when offline"}},
+	}
+	if len(fragments) != len(expected) {
+		t.Fatalf("Expected %d fragments, got %d: %+v", len(expected), len(fragments), fragments)
+	}
+	for i, e := range expected {
+		f := fragments[i]
+		if f.ID != e.id || f.Section != e.section || len(f.Entries) != len(e.entries) {
+			t.Errorf("Fragment %d: expected %+v, got %+v", i, e, f)
+			continue
+		}
+		for j := range e.entries {
+			if f.Entries[j] != e.entries[j] {
+				t.Errorf("Fragment %d entry %d: expected %q, got %q", i, j, e.entries[j], f.Entries[j])
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "5.unknown.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFragments(dir); err == nil {
+		t.Error("Expected an error for an invalid fragment name")
+	}
+}
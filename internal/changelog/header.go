@@ -0,0 +1,58 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultVersionHeader is the Keep a Changelog version header, e.g. "## [1.2.3] - 2024-01-01"
+const DefaultVersionHeader = "## [{{.Version}}] - {{.Date}}"
+
+// HeaderData is available as {{.Version}}, {{.Date}} and {{.Channel}} in
+// version header templates
+type HeaderData struct {
+	Version string
+	Date    string
+	Channel string
+}
+
+// HeaderTemplate renders the header of a released version
+type HeaderTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseHeaderTemplate parses a version header template. An empty text uses
+// DefaultVersionHeader. The template is rendered with sample data, and must
+// yield a single "## [<version>]" header line so the version can be read back.
+func ParseHeaderTemplate(text string) (*HeaderTemplate, error) {
+	if text == "" {
+		text = DefaultVersionHeader
+	}
+	sample := HeaderData{Version: "1.0.0", Date: "2006-01-02", Channel: "stable"}
+	tmpl, err := validateTemplate("version header", text, sample)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &HeaderTemplate{tmpl: tmpl}
+	header, err := t.Render(sample)
+	if err != nil {
+		return nil, err
+	}
+	matches := versionHeaderRegex.FindStringSubmatch(header)
+	if strings.Contains(header, "\n") || matches == nil || matches[1] != sample.Version {
+		return nil, fmt.Errorf("version header template must yield a header like \"## [{{.Version}}] - {{.Date}}\", got %q", header)
+	}
+	return t, nil
+}
+
+// Render returns the version header for the data
+func (t *HeaderTemplate) Render(data HeaderData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering version header template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,49 @@
+package changelog
+
+import (
+	"testing"
+)
+
+func TestHeaderTemplate(t *testing.T) {
+	data := HeaderData{Version: "1.2.3", Date: "2024-01-01", Channel: "stable"}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"Default", "", "## [1.2.3] - 2024-01-01"},
+		{"Channel", "## [{{.Version}}] - {{.Date}} ({{.Channel}})", "## [1.2.3] - 2024-01-01 (stable)"},
+		{"Without date", "## [{{.Version}}]", "## [1.2.3]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseHeaderTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseHeaderTemplate() error = %v", err)
+			}
+			header, err := tmpl.Render(data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if header != tt.expected {
+				t.Errorf("Render() = %q, want %q", header, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseHeaderTemplateErrors(t *testing.T) {
+	for _, text := range []string{
+		"## [{{.Version}",
+		"## [{{.Name}}]",
+		"## {{.Version}} - {{.Date}}",
+		"## [v{{.Version}}] - {{.Date}}",
+		"## [{{.Version}}]\n\n{{.Date}}",
+	} {
+		if _, err := ParseHeaderTemplate(text); err == nil {
+			t.Errorf("ParseHeaderTemplate(%q) expected an error", text)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package changelog
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LinkReference is a "[label]: url" link reference definition
+type LinkReference struct {
+	Label string
+	URL   string
+	Line  int // 1-based line number
+}
+
+var versionLabelRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:[-+].*)?$`)
+
+// RemoveOrphanLinks removes the link reference definitions of versions that
+// have no version header, such as links left behind by a deleted version.
+// The Unreleased link, links that aren't for versions, and the order of the
+// remaining links are kept.
+func RemoveOrphanLinks(content string) (string, int, error) {
+	result, removed, err := removeOrphanLinks(content)
+	return result, len(removed), err
+}
+
+// FixLinks removes the orphan version links of the changelog file and returns
+// the versions whose links were removed
+func FixLinks(changelogFile string) ([]string, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	result, removed, err := removeOrphanLinks(string(content))
+	if err != nil || len(removed) == 0 {
+		return nil, err
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(result), 0644); err != nil {
+		return nil, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return removed, nil
+}
+
+// LinkReferences returns the link reference definitions of the changelog,
+// skipping code blocks
+func LinkReferences(content string) []LinkReference {
+	var links []LinkReference
+	inCodeBlock := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+		}
+		if inCodeBlock || !linkLineRegex.MatchString(trimmedLine) {
+			continue
+		}
+		parts := strings.SplitN(trimmedLine, "]:", 2)
+		links = append(links, LinkReference{
+			Label: strings.TrimPrefix(parts[0], "["),
+			URL:   strings.TrimSpace(parts[1]),
+			Line:  i + 1,
+		})
+	}
+	return links
+}
+
+// ValidateLinkURL checks that link is an absolute http or https URL
+func ValidateLinkURL(link string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL: expected an http or https URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL: missing host")
+	}
+	return nil
+}
+
+// CheckLink requests link and returns an error unless it resolves. HEAD is
+// tried first, with a fallback to GET for servers that don't allow HEAD.
+func CheckLink(client *http.Client, link string) error {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequest(method, link, nil)
+		if err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed {
+			break
+		}
+	}
+	if status >= 400 {
+		return fmt.Errorf("HTTP %d %s", status, http.StatusText(status))
+	}
+	return nil
+}
+
+func removeOrphanLinks(content string) (string, []string, error) {
+	versions := map[string]bool{}
+	for _, v := range Parse(content).Versions {
+		versions[v.Name] = true
+	}
+	if len(versions) == 0 {
+		return "", nil, fmt.Errorf("no version sections found in changelog")
+	}
+
+	var lines, removed []string
+	inCodeBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+		}
+		if !inCodeBlock && linkLineRegex.MatchString(trimmedLine) {
+			label := trimmedLine[1:strings.Index(trimmedLine, "]")]
+			if versionLabelRegex.MatchString(label) && !versions[label] {
+				removed = append(removed, label)
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), removed, nil
+}
+
+// AddMissingLinks adds a link reference definition for every version header,
+// including Unreleased, that has none, and returns the versions that got a
+// link. The links are built like those of a release, from the provider,
+// repository URL, link style and tags of opts, and are placed among the
+// existing links in version order. Existing links are kept as they are.
+// Unreleased can't be linked before the first release.
+func AddMissingLinks(content string, opts UpdateOptions) (string, []string) {
+	_, order := versionHeaderLines(content)
+	existing := map[string]int{}
+	lastLink := -1
+	for _, link := range LinkReferences(content) {
+		existing[link.Label] = link.Line - 1
+		lastLink = link.Line - 1
+	}
+
+	links := newLinkFormat(opts)
+
+	// New links go before the link of the next older version, after the
+	// last link, or at the end when the changelog has no links yet (-1)
+	lines := strings.Split(content, "\n")
+	inserts := map[int][]string{}
+	var added []string
+	for i, version := range order {
+		if _, ok := existing[version]; ok {
+			continue
+		}
+		older := ""
+		for _, v := range order[i+1:] {
+			if v != "Unreleased" {
+				older = v
+				break
+			}
+		}
+
+		var link string
+		switch {
+		case version == "Unreleased" && older == "":
+			continue
+		case version == "Unreleased":
+			link = links.compare(older, "HEAD")
+		case older == "" || opts.LinkStyle == "tag":
+			link = links.tag(version)
+		default:
+			link = links.compare(older, version)
+		}
+
+		at := -1
+		if lastLink >= 0 {
+			at = lastLink + 1
+		}
+		for _, v := range order[i+1:] {
+			if line, ok := existing[v]; ok {
+				at = line
+				break
+			}
+		}
+		inserts[at] = append(inserts[at], fmt.Sprintf("[%s]: %s", version, link))
+		added = append(added, version)
+	}
+	if len(added) == 0 {
+		return content, nil
+	}
+
+	var result []string
+	for i := 0; i <= len(lines); i++ {
+		result = append(result, inserts[i]...)
+		if i < len(lines) {
+			result = append(result, lines[i])
+		}
+	}
+	if end := inserts[-1]; len(end) > 0 {
+		for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+			result = result[:len(result)-1]
+		}
+		result = append(append(append(result, ""), end...), "")
+	}
+	return strings.Join(result, "\n"), added
+}
+
+// FixMissingLinks adds the missing version links to the changelog file, see
+// AddMissingLinks, and returns the versions that got a link
+func FixMissingLinks(changelogFile string, opts UpdateOptions) ([]string, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	result, added := AddMissingLinks(string(content), opts)
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(result), 0644); err != nil {
+		return nil, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return added, nil
+}
@@ -0,0 +1,276 @@
+package changelog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRemoveOrphanLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		removed  int
+		wantErr  bool
+	}{
+		{
+			name: "Deleted version",
+			content: `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+## [1.0.0] - 2024-01-01
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+			expected: `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+
+## [1.0.0] - 2024-01-01
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+			removed: 1,
+		},
+		{
+			name: "Keeps other links",
+			content: `# Changelog
+
+## [1.0.0] - 2024-01-01
+
+- See the [docs] and the [1.0.0-rc.1] notes
+
+[docs]: https://example.com/docs
+[1.0.0-rc.1]: https://github.com/peiman/changie/releases/tag/1.0.0-rc.1
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+[v0.9.0]: https://github.com/peiman/changie/releases/tag/v0.9.0
+`,
+			expected: `# Changelog
+
+## [1.0.0] - 2024-01-01
+
+- See the [docs] and the [1.0.0-rc.1] notes
+
+[docs]: https://example.com/docs
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+			removed: 2,
+		},
+		{
+			name: "Nothing to remove",
+			content: `## [1.0.0] - 2024-01-01
+
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+			expected: `## [1.0.0] - 2024-01-01
+
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+		},
+		{
+			name:    "No versions",
+			content: "# Changelog\n\n[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, removed, err := RemoveOrphanLinks(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if removed != tt.removed {
+				t.Errorf("Expected %d removed links, got %d", tt.removed, removed)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLinkReferences(t *testing.T) {
+	content := "## [1.0.0] - 2024-01-01\n\n```\n[example]: https://example.com/ignored\n```\n\n[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD\n[1.0.0]:   https://github.com/peiman/changie/releases/tag/1.0.0\n"
+
+	expected := []LinkReference{
+		{Label: "Unreleased", URL: "https://github.com/peiman/changie/compare/1.0.0...HEAD", Line: 7},
+		{Label: "1.0.0", URL: "https://github.com/peiman/changie/releases/tag/1.0.0", Line: 8},
+	}
+	if links := LinkReferences(content); !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, links)
+	}
+}
+
+func TestValidateLinkURL(t *testing.T) {
+	tests := []struct {
+		link    string
+		wantErr bool
+	}{
+		{"https://github.com/peiman/changie/compare/1.0.0...1.1.0", false},
+		{"http://example.com", false},
+		{"github.com/peiman/changie", true},
+		{"ftp://example.com/changie", true},
+		{"https://", true},
+		{"https://exa mple.com", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateLinkURL(tt.link); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateLinkURL(%q) error = %v, wantErr %v", tt.link, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/found":
+		case r.URL.Path == "/get-only" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/get-only":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if err := CheckLink(server.Client(), server.URL+"/found"); err != nil {
+		t.Errorf("Expected link to resolve, got: %v", err)
+	}
+	if err := CheckLink(server.Client(), server.URL+"/get-only"); err != nil {
+		t.Errorf("Expected GET fallback to resolve, got: %v", err)
+	}
+	if err := CheckLink(server.Client(), server.URL+"/missing"); err == nil || err.Error() != "HTTP 404 Not Found" {
+		t.Errorf("Expected HTTP 404 error, got: %v", err)
+	}
+
+	server.Close()
+	if err := CheckLink(server.Client(), server.URL+"/found"); err == nil || !strings.HasPrefix(err.Error(), "request failed") {
+		t.Errorf("Expected request error, got: %v", err)
+	}
+}
+
+func TestFixLinks(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	initialContent := "## [1.0.0] - 2024-01-01\n\n[1.1.0]: https://example.com/1.1.0\n[1.0.0]: https://example.com/1.0.0\n"
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := FixLinks(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(removed, []string{"1.1.0"}) {
+		t.Errorf("Expected [1.1.0] to be removed, got %v", removed)
+	}
+	content, _ := os.ReadFile(tmpfile.Name())
+	if string(content) != "## [1.0.0] - 2024-01-01\n\n[1.0.0]: https://example.com/1.0.0\n" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+}
+
+func TestAddMissingLinks(t *testing.T) {
+	versions := "## [Unreleased]\n\n## [1.2.0] - 2024-03-01\n\n## [1.1.0] - 2024-02-01\n\n## [1.0.0] - 2024-01-01\n"
+	tests := []struct {
+		name          string
+		content       string
+		opts          UpdateOptions
+		expectedLinks string
+		expectedAdded []string
+	}{
+		{
+			name:          "No links",
+			content:       versions,
+			opts:          UpdateOptions{Provider: "github"},
+			expectedLinks: "\n[Unreleased]: https://github.com/peiman/changie/compare/1.2.0...HEAD\n[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0\n[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0\n[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0\n",
+			expectedAdded: []string{"Unreleased", "1.2.0", "1.1.0", "1.0.0"},
+		},
+		{
+			name:          "Missing links placed in version order",
+			content:       versions + "\n[1.2.0]: https://example.com/custom\n[1.0.0]: https://example.com/1.0.0\n",
+			opts:          UpdateOptions{RepositoryURL: "https://github.com/acme/app/", Tags: map[string]string{"1.1.0": "v1.1.0", "1.0.0": "v1.0.0"}},
+			expectedLinks: "\n[Unreleased]: https://github.com/acme/app/compare/1.2.0...HEAD\n[1.2.0]: https://example.com/custom\n[1.1.0]: https://github.com/acme/app/compare/v1.0.0...v1.1.0\n[1.0.0]: https://example.com/1.0.0\n",
+			expectedAdded: []string{"Unreleased", "1.1.0"},
+		},
+		{
+			name:          "Tag style",
+			content:       versions + "\n[Unreleased]: https://example.com/HEAD\n",
+			opts:          UpdateOptions{Provider: "github", LinkStyle: "tag"},
+			expectedLinks: "\n[Unreleased]: https://example.com/HEAD\n[1.2.0]: https://github.com/peiman/changie/releases/tag/1.2.0\n[1.1.0]: https://github.com/peiman/changie/releases/tag/1.1.0\n[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0\n",
+			expectedAdded: []string{"1.2.0", "1.1.0", "1.0.0"},
+		},
+		{
+			name:          "Unreleased before the first release",
+			content:       "## [Unreleased]\n",
+			opts:          UpdateOptions{Provider: "github"},
+			expectedAdded: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, added := AddMissingLinks(tt.content, tt.opts)
+			if !reflect.DeepEqual(added, tt.expectedAdded) {
+				t.Errorf("AddMissingLinks() added %v, want %v", added, tt.expectedAdded)
+			}
+			expected := tt.content
+			if tt.expectedLinks != "" {
+				expected = versions + tt.expectedLinks
+			}
+			if result != expected {
+				t.Errorf("AddMissingLinks() =\n%s\nwant\n%s", result, expected)
+			}
+		})
+	}
+}
+
+func TestFixMissingLinks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := os.WriteFile(file, []byte("## [1.0.0] - 2024-01-01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := FixMissingLinks(file, UpdateOptions{Provider: "github"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(added, []string{"1.0.0"}) {
+		t.Errorf("Expected a link for 1.0.0, got %v", added)
+	}
+	content, _ := os.ReadFile(file)
+	if string(content) != "## [1.0.0] - 2024-01-01\n\n[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0\n" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+
+	if _, err := FixMissingLinks(filepath.Join(t.TempDir(), "missing.md"), UpdateOptions{}); err == nil {
+		t.Error("Expected an error for a missing changelog")
+	}
+}
@@ -0,0 +1,117 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxEntryLength is the entry length above which lint warns
+const maxEntryLength = 200
+
+var multipleSpacesRegex = regexp.MustCompile(`(\S) {2,}`)
+
+// LintOptions controls the style LintChangelog enforces
+type LintOptions struct {
+	Punctuation string // "keep" (default), "none" to strip a trailing period, or "period" to end entries with one
+}
+
+// LintResult is the outcome of linting a changelog
+type LintResult struct {
+	Errors   []string // Structural problems that can't be fixed automatically
+	Fixes    []string // Issues with a safe correction, applied by --fix
+	Warnings []string // Subjective issues, such as mood and length, that are only reported
+	Content  string   // Changelog content with the fixes applied
+}
+
+// LintChangelog checks the changelog content for style issues and returns the
+// content with the safe corrections applied
+func LintChangelog(content string, opts LintOptions) LintResult {
+	result := LintResult{}
+	for _, issue := range ValidateChangelog(content) {
+		result.Errors = append(result.Errors, issue.String())
+	}
+
+	// UpdateChangelog doesn't end the file with a newline, which isn't worth a fix
+	c := Parse(content)
+	if normalized := c.String(); normalized != content && normalized != content+"\n" {
+		result.Fixes = append(result.Fixes, "normalized blank lines and spacing")
+	}
+
+	for _, v := range c.Versions {
+		for _, s := range v.Sections {
+			for _, e := range s.Entries {
+				where := fmt.Sprintf("[%s] %s", v.Name, s.Name)
+				result.Fixes = append(result.Fixes, lintEntry(e, where, opts)...)
+				result.Warnings = append(result.Warnings, entryWarnings(e, where)...)
+			}
+		}
+	}
+
+	result.Content = c.String()
+	return result
+}
+
+// lintEntry applies the safe corrections to the entry and describes them
+func lintEntry(e *Entry, where string, opts LintOptions) []string {
+	var fixes []string
+	original := e.Text
+
+	if collapsed := multipleSpacesRegex.ReplaceAllString(e.Text, "$1 "); collapsed != e.Text {
+		e.Text = collapsed
+		fixes = append(fixes, fmt.Sprintf("%s: collapsed double spaces in %q", where, original))
+	}
+
+	if r, size := utf8.DecodeRuneInString(e.Text); unicode.IsLower(r) {
+		e.Text = string(unicode.ToUpper(r)) + e.Text[size:]
+		fixes = append(fixes, fmt.Sprintf("%s: capitalized %q", where, original))
+	}
+
+	// Entries with continuation lines end on a nested line, so their punctuation is left alone
+	if len(e.Nested) == 0 {
+		switch {
+		case opts.Punctuation == "none" && strings.HasSuffix(e.Text, ".") && !strings.HasSuffix(e.Text, ".."):
+			e.Text = strings.TrimSuffix(e.Text, ".")
+			fixes = append(fixes, fmt.Sprintf("%s: removed the trailing period of %q", where, original))
+		case opts.Punctuation == "period" && !strings.HasSuffix(e.Text, ".") && !strings.HasSuffix(e.Text, "!") && !strings.HasSuffix(e.Text, "?"):
+			e.Text += "."
+			fixes = append(fixes, fmt.Sprintf("%s: added a trailing period to %q", where, original))
+		}
+	}
+
+	return fixes
+}
+
+// entryWarnings reports subjective issues of the entry
+func entryWarnings(e *Entry, where string) []string {
+	var warnings []string
+	if fields := strings.Fields(e.Text); len(fields) > 0 && len(fields[0]) > 4 && strings.HasSuffix(strings.ToLower(fields[0]), "ing") {
+		warnings = append(warnings, fmt.Sprintf("%s: %q starts with %q, use the past tense or imperative mood", where, e.Text, fields[0]))
+	}
+	if length := len(strings.Join(append([]string{e.Text}, e.Nested...), " ")); length > maxEntryLength {
+		warnings = append(warnings, fmt.Sprintf("%s: %q is %d characters long, consider shortening it", where, e.Text, length))
+	}
+	return warnings
+}
+
+// LintChangelogFile lints the changelog file. With fix set, the corrections are
+// written to the file, unless check is set as well.
+func LintChangelogFile(changelogFile string, opts LintOptions, fix, check bool) (LintResult, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return LintResult{}, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	result := LintChangelog(string(content), opts)
+	if !fix || check || len(result.Fixes) == 0 {
+		return result, nil
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(result.Content), 0644); err != nil {
+		return result, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return result, nil
+}
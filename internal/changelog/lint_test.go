@@ -0,0 +1,169 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintChangelog(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+- new  feature.
+- Adding support for things
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Bug fix
+  - Detail.
+`
+
+	tests := []struct {
+		name     string
+		opts     LintOptions
+		fixes    []string
+		expected string
+	}{
+		{
+			name: "Keep punctuation",
+			opts: LintOptions{Punctuation: "keep"},
+			fixes: []string{
+				"normalized blank lines and spacing",
+				`[Unreleased] Added: collapsed double spaces in "new  feature."`,
+				`[Unreleased] Added: capitalized "new  feature."`,
+			},
+			expected: "- New feature.\n- Adding support for things\n",
+		},
+		{
+			name: "Strip periods",
+			opts: LintOptions{Punctuation: "none"},
+			fixes: []string{
+				"normalized blank lines and spacing",
+				`[Unreleased] Added: collapsed double spaces in "new  feature."`,
+				`[Unreleased] Added: capitalized "new  feature."`,
+				`[Unreleased] Added: removed the trailing period of "new  feature."`,
+			},
+			expected: "- New feature\n- Adding support for things\n",
+		},
+		{
+			name: "Add periods",
+			opts: LintOptions{Punctuation: "period"},
+			fixes: []string{
+				"normalized blank lines and spacing",
+				`[Unreleased] Added: collapsed double spaces in "new  feature."`,
+				`[Unreleased] Added: capitalized "new  feature."`,
+				`[Unreleased] Added: added a trailing period to "Adding support for things"`,
+			},
+			expected: "- New feature.\n- Adding support for things.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LintChangelog(content, tt.opts)
+			if !reflect.DeepEqual(result.Fixes, tt.fixes) {
+				t.Errorf("Expected fixes %q, got %q", tt.fixes, result.Fixes)
+			}
+			if !strings.Contains(result.Content, "### Added\n\n"+tt.expected) {
+				t.Errorf("Expected fixed entries %q, got:\n%s", tt.expected, result.Content)
+			}
+			if !strings.Contains(result.Content, "- Bug fix\n  - Detail.\n") {
+				t.Errorf("Expected nested entry lines to be kept, got:\n%s", result.Content)
+			}
+			expectedWarning := `starts with "Adding", use the past tense or imperative mood`
+			if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], expectedWarning) {
+				t.Errorf("Expected mood warning, got %q", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestLintChangelogClean(t *testing.T) {
+	content := "# Changelog\n\n## [1.0.0] - 2024-01-01\n\n### Added\n\n- Initial release\n"
+	result := LintChangelog(content, LintOptions{})
+	if len(result.Errors) > 0 || len(result.Fixes) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("Expected no issues, got %+v", result)
+	}
+	if result.Content != content {
+		t.Errorf("Expected content to be unchanged, got:\n%s", result.Content)
+	}
+}
+
+// TestLintChangelogAfterRelease lints the changelog as changie writes it on
+// the first and later releases
+func TestLintChangelogAfterRelease(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := InitProject(file); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		if _, err := AddChangelogSection(file, "Added", "Feature of "+version); err != nil {
+			t.Fatal(err)
+		}
+		if err := UpdateChangelog(file, version, "github"); err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := LintChangelog(string(content), LintOptions{})
+		if len(result.Errors) > 0 || len(result.Fixes) > 0 || len(result.Warnings) > 0 {
+			t.Errorf("Expected no issues after releasing %s, got %+v", version, result)
+		}
+	}
+}
+
+func TestLintChangelogWarningsAndErrors(t *testing.T) {
+	content := "## [1.0.0] - 2024-01-02\n\n### Added\n\n- " + strings.Repeat("x", 201) + "\n\n## [1.0.0] - 2024-01-01\n"
+	result := LintChangelog(content, LintOptions{})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "duplicate version header [1.0.0]") {
+		t.Errorf("Expected duplicate version error, got %q", result.Errors)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "is 201 characters long") {
+		t.Errorf("Expected length warning, got %q", result.Warnings)
+	}
+}
+
+func TestLintChangelogFile(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	initialContent := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- new feature\n"
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := LintChangelogFile(tmpfile.Name(), LintOptions{}, true, true)
+	if err != nil || len(result.Fixes) != 1 {
+		t.Errorf("Expected check to report one fix, got %q, err=%v", result.Fixes, err)
+	}
+	content, _ := os.ReadFile(tmpfile.Name())
+	if string(content) != initialContent {
+		t.Error("Expected check mode not to modify the file")
+	}
+
+	if _, err := LintChangelogFile(tmpfile.Name(), LintOptions{}, true, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, _ = os.ReadFile(tmpfile.Name())
+	if !strings.Contains(string(content), "- New feature\n") {
+		t.Errorf("Expected fixes to be written, got:\n%s", content)
+	}
+
+	result, err = LintChangelogFile(tmpfile.Name(), LintOptions{}, true, true)
+	if err != nil || len(result.Fixes) != 0 {
+		t.Errorf("Expected fixed file to pass the check, got %q, err=%v", result.Fixes, err)
+	}
+}
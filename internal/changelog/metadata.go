@@ -0,0 +1,90 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	metadataKeyRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	metadataRegex    = regexp.MustCompile(`\s*\[([A-Za-z][A-Za-z0-9_-]*=[^\[\],=]+(?:, [A-Za-z][A-Za-z0-9_-]*=[^\[\],=]+)*)\]$`)
+)
+
+// ValidateMetadata checks that metadata keys are identifiers and that values
+// can be written in the bracketed annotation of an entry
+func ValidateMetadata(meta map[string]string) error {
+	for key, value := range meta {
+		if !metadataKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid metadata key %q, expected letters, digits, - and _", key)
+		}
+		if strings.TrimSpace(value) != value || value == "" || strings.ContainsAny(value, "[],=") {
+			return fmt.Errorf("invalid metadata value %q for %s", value, key)
+		}
+	}
+	return nil
+}
+
+// AddMetadata appends the metadata to the entry content as a bracketed
+// annotation, e.g. "Fix login [severity=high, team=auth]". Metadata already on
+// the entry is kept unless overridden. Keys are sorted so the result is stable.
+func AddMetadata(content string, meta map[string]string) string {
+	if len(meta) == 0 {
+		return content
+	}
+
+	text, existing := SplitMetadata(content)
+	merged := map[string]string{}
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range meta {
+		merged[key] = value
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + merged[key]
+	}
+	return text + " [" + strings.Join(pairs, ", ") + "]"
+}
+
+// SplitMetadata separates a trailing metadata annotation from the entry text.
+// The metadata is nil when the text has no annotation.
+func SplitMetadata(text string) (string, map[string]string) {
+	matches := metadataRegex.FindStringSubmatchIndex(text)
+	if matches == nil {
+		return text, nil
+	}
+
+	meta := map[string]string{}
+	for _, pair := range strings.Split(text[matches[2]:matches[3]], ", ") {
+		parts := strings.SplitN(pair, "=", 2)
+		meta[parts[0]] = parts[1]
+	}
+	return text[:matches[0]], meta
+}
+
+// Metadata returns the metadata annotation of the entry, or nil when it has none
+func (e *Entry) Metadata() map[string]string {
+	_, meta := SplitMetadata(e.joinedText())
+	return meta
+}
+
+// joinedText returns the entry text with its nested lines joined by spaces,
+// so wrapped entries read as a single line
+func (e *Entry) joinedText() string {
+	text := e.Text
+	for _, line := range e.Nested {
+		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" {
+			text += " " + trimmedLine
+		}
+	}
+	return text
+}
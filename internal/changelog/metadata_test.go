@@ -0,0 +1,104 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		meta     map[string]string
+		expected string
+	}{
+		{"No metadata", "Fix login", nil, "Fix login"},
+		{"Single pair", "Fix login", map[string]string{"severity": "high"}, "Fix login [severity=high]"},
+		{"Sorted pairs", "Fix login", map[string]string{"team": "auth", "severity": "high"}, "Fix login [severity=high, team=auth]"},
+		{"Merged with existing", "Fix login [severity=low, team=auth]", map[string]string{"severity": "high"}, "Fix login [severity=high, team=auth]"},
+		{"Markdown link kept", "Fix [login](https://example.com)", map[string]string{"severity": "high"}, "Fix [login](https://example.com) [severity=high]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddMetadata(tt.content, tt.meta); got != tt.expected {
+				t.Errorf("AddMetadata(%q) = %q, want %q", tt.content, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitMetadata(t *testing.T) {
+	tests := []struct {
+		text         string
+		expectedText string
+		expectedMeta map[string]string
+	}{
+		{"Fix login [severity=high]", "Fix login", map[string]string{"severity": "high"}},
+		{"Fix login [severity=high, team=auth]", "Fix login", map[string]string{"severity": "high", "team": "auth"}},
+		{"Fix login", "Fix login", nil},
+		{"See [docs]", "See [docs]", nil},
+		{"Support [a=b] syntax in the middle", "Support [a=b] syntax in the middle", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			text, meta := SplitMetadata(tt.text)
+			if text != tt.expectedText || !reflect.DeepEqual(meta, tt.expectedMeta) {
+				t.Errorf("SplitMetadata(%q) = %q, %v, want %q, %v", tt.text, text, meta, tt.expectedText, tt.expectedMeta)
+			}
+		})
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    map[string]string
+		wantErr bool
+	}{
+		{"Valid", map[string]string{"severity": "high", "issue-id": "PROJ-12"}, false},
+		{"Invalid key", map[string]string{"1st": "yes"}, true},
+		{"Empty value", map[string]string{"severity": ""}, true},
+		{"Bracket in value", map[string]string{"severity": "high]"}, true},
+		{"Comma in value", map[string]string{"teams": "a,b"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMetadata(tt.meta); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryMetadata(t *testing.T) {
+	c := Parse(`## [Unreleased]
+
+### Fixed
+
+- Fix login for users with long
+  names [severity=high, team=auth]
+- Plain entry
+`)
+	entries := c.Version("Unreleased").Section("Fixed").Entries
+
+	if meta := entries[0].Metadata(); !reflect.DeepEqual(meta, map[string]string{"severity": "high", "team": "auth"}) {
+		t.Errorf("Metadata() of wrapped entry = %v", meta)
+	}
+	if meta := entries[1].Metadata(); meta != nil {
+		t.Errorf("Metadata() of plain entry = %v, want nil", meta)
+	}
+}
+
+func TestContainsEntryIgnoresMetadata(t *testing.T) {
+	entries := []string{"- Fix login [severity=high]", "- Plain entry"}
+
+	if !containsEntry(entries, "- Fix login") {
+		t.Error("Expected entry without metadata to match entry with metadata")
+	}
+	if !containsEntry(entries, "- Plain entry [severity=low]") {
+		t.Error("Expected entry with metadata to match entry without metadata")
+	}
+}
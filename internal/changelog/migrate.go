@@ -0,0 +1,73 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Lines of the current Keep a Changelog header template
+const (
+	headerIntro         = "All notable changes to this project will be documented in this file."
+	headerKeepChangelog = "The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),"
+	headerSemver        = "and this project adheres to [Semantic Versioning (SemVer)](https://semver.org)."
+)
+
+// MigrateHeader replaces the header of the changelog, everything before the
+// first version, with the current template. The title is kept, and the
+// version sections are left untouched.
+func MigrateHeader(content string) (string, error) {
+	lines := strings.Split(content, "\n")
+	first := -1
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+		}
+		if !inCodeBlock && versionHeaderRegex.MatchString(trimmedLine) {
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return "", fmt.Errorf("no version sections found in changelog")
+	}
+
+	title := "# " + DefaultTitle
+	for _, line := range lines[:first] {
+		if trimmedLine := strings.TrimSpace(line); strings.HasPrefix(trimmedLine, "# ") {
+			title = trimmedLine
+			break
+		}
+	}
+
+	header := []string{title, "", headerIntro, "", headerKeepChangelog, headerSemver, ""}
+	return strings.Join(append(header, lines[first:]...), "\n"), nil
+}
+
+// MigrateChangelog updates the header of the changelog file to the current
+// template and reports whether anything changed. With check set, the file is
+// left untouched.
+func MigrateChangelog(changelogFile string, check bool) (bool, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return false, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	migrated, err := MigrateHeader(string(content))
+	if err != nil {
+		return false, err
+	}
+	if migrated == string(content) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(migrated), 0644); err != nil {
+		return false, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return true, nil
+}
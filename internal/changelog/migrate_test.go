@@ -0,0 +1,140 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateHeader(t *testing.T) {
+	versions := `## [Unreleased]
+
+### Added
+- Entry that is not reformatted
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+	expected := `# Change Log
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning (SemVer)](https://semver.org).
+
+` + versions
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "Keep a Changelog 1.0.0",
+			content: `# Change Log
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](http://keepachangelog.com/en/1.0.0/)
+and this project adheres to [Semantic Versioning](http://semver.org/spec/v2.0.0.html).
+
+` + versions,
+			expected: expected,
+		},
+		{
+			name:     "Already migrated",
+			content:  expected,
+			expected: expected,
+		},
+		{
+			name:     "Missing header",
+			content:  versions,
+			expected: "# Changelog" + expected[len("# Change Log"):],
+		},
+		{
+			name:    "No versions",
+			content: "# Changelog\n\nAll notable changes to this project will be documented in this file.\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MigrateHeader(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMigrateChangelog(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	initialContent := "# Changelog\n\nThe format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/).\n\n## [Unreleased]\n"
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := MigrateChangelog(tmpfile.Name(), true)
+	if err != nil || !changed {
+		t.Errorf("Expected check to report changes, got changed=%v err=%v", changed, err)
+	}
+	content, _ := os.ReadFile(tmpfile.Name())
+	if string(content) != initialContent {
+		t.Error("Expected check mode not to modify the file")
+	}
+
+	changed, err = MigrateChangelog(tmpfile.Name(), false)
+	if err != nil || !changed {
+		t.Errorf("Expected changes to be written, got changed=%v err=%v", changed, err)
+	}
+
+	changed, err = MigrateChangelog(tmpfile.Name(), true)
+	if err != nil || changed {
+		t.Errorf("Expected migrated file to pass the check, got changed=%v err=%v", changed, err)
+	}
+}
+
+// TestAddEntryKeepsHeader adds an entry to a changelog with a Keep a Changelog
+// 1.0.0 header, which only migrate updates
+func TestAddEntryKeepsHeader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	header := "The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),"
+	initialContent := "# Changelog\n\nAll notable changes to this project will be documented in this file.\n\n" + header + "\nand this project adheres to [Semantic Versioning (SemVer)](https://semver.org).\n\n## [Unreleased]\n"
+	if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AddChangelogSection(file, "Added", "New feature"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := os.ReadFile(file)
+	if !strings.Contains(string(content), "\n"+header+"\n") {
+		t.Errorf("Expected the 1.0.0 header to be kept, got:\n%s", content)
+	}
+	if changed, err := MigrateChangelog(file, true); err != nil || !changed {
+		t.Errorf("Expected migrate to still report changes, got changed=%v err=%v", changed, err)
+	}
+}
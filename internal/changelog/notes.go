@@ -0,0 +1,138 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NotesData is available as {{.Version}} and {{.Date}} in release notes templates
+type NotesData struct {
+	Version string
+	Date    string
+}
+
+// NotesTemplate wraps the release notes of a version in a header and footer
+type NotesTemplate struct {
+	header *template.Template
+	footer *template.Template
+}
+
+// ParseNotesTemplate parses the header and footer templates. Either can be
+// empty. The templates are also rendered with sample data, so errors such as
+// unknown fields are reported before the notes are needed.
+func ParseNotesTemplate(header, footer string) (*NotesTemplate, error) {
+	t := &NotesTemplate{}
+	var err error
+	if t.header, err = parseNotesPart("header", header); err != nil {
+		return nil, err
+	}
+	if t.footer, err = parseNotesPart("footer", footer); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func parseNotesPart(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	return validateTemplate("notes "+name, text, NotesData{Version: "1.0.0", Date: "2006-01-02"})
+}
+
+// Render returns the notes between the rendered header and footer, separated
+// by blank lines
+func (t *NotesTemplate) Render(notes string, data NotesData) (string, error) {
+	header, err := renderNotesPart(t.header, data)
+	if err != nil {
+		return "", err
+	}
+	footer, err := renderNotesPart(t.footer, data)
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	for _, text := range []string{header, notes, footer} {
+		if text = strings.Trim(text, "\n"); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return strings.Join(parts, "\n\n") + "\n", nil
+}
+
+func renderNotesPart(tmpl *template.Template, data NotesData) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering %s template: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// DefaultNotesFile is the name of the release notes file of a tag, e.g. "v1.2.3.notes.md"
+const DefaultNotesFile = "{{.Tag}}.notes.md"
+
+// NotesFileData is available as {{.Tag}} and {{.Version}} in notes file name templates
+type NotesFileData struct {
+	Tag     string
+	Version string
+}
+
+// NotesFileTemplate renders the name of the release notes file of a tag
+type NotesFileTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseNotesFileTemplate parses a notes file name template. An empty text uses
+// DefaultNotesFile. The template is rendered with sample data, and must yield
+// a single line file name.
+func ParseNotesFileTemplate(text string) (*NotesFileTemplate, error) {
+	if text == "" {
+		text = DefaultNotesFile
+	}
+	sample := NotesFileData{Tag: "v1.0.0", Version: "1.0.0"}
+	tmpl, err := validateTemplate("notes file", text, sample)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &NotesFileTemplate{tmpl: tmpl}
+	if _, err := t.Render(sample); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render returns the name of the notes file for the data
+func (t *NotesFileTemplate) Render(data NotesFileData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering notes file template: %w", err)
+	}
+	name := strings.TrimSpace(buf.String())
+	if name == "" || strings.Contains(name, "\n") {
+		return "", fmt.Errorf("notes file template must yield a single line file name, got %q", name)
+	}
+	return name, nil
+}
+
+// ExcludeSections removes the sections with the given names from every
+// version of the changelog content, e.g. internal sections that are left out
+// of published release notes. The content is returned as is without names.
+func ExcludeSections(content string, names []string) string {
+	if len(names) == 0 {
+		return content
+	}
+	c := Parse(content)
+	for _, v := range c.Versions {
+		v.RemoveSections(names)
+	}
+	return c.String()
+}
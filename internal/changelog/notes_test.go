@@ -0,0 +1,120 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotesTemplate(t *testing.T) {
+	notes := "### Added\n\n- Feature A\n"
+	data := NotesData{Version: "1.1.0", Date: "2024-02-01"}
+
+	tests := []struct {
+		name     string
+		header   string
+		footer   string
+		notes    string
+		expected string
+	}{
+		{
+			name:     "No templates",
+			notes:    notes,
+			expected: notes,
+		},
+		{
+			name:     "Header and footer",
+			header:   "# Release {{.Version}} ({{.Date}})\n",
+			footer:   "Install with `go install github.com/peiman/changie@{{.Version}}`",
+			notes:    notes,
+			expected: "# Release 1.1.0 (2024-02-01)\n\n### Added\n\n- Feature A\n\nInstall with `go install github.com/peiman/changie@1.1.0`\n",
+		},
+		{
+			name:     "Empty notes",
+			header:   "Release {{.Version}}",
+			expected: "Release 1.1.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseNotesTemplate(tt.header, tt.footer)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result, err := tmpl.Render(tt.notes, data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseNotesTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		footer   string
+		expected string
+	}{
+		{"Syntax error", "Release {{.Version", "", "invalid notes header template"},
+		{"Unknown field", "", "Released on {{.Day}}", "error rendering notes footer template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNotesTemplate(tt.header, tt.footer)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNotesFileTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		err      string
+	}{
+		{"Default", "", "v1.2.0.notes.md", ""},
+		{"Custom", "release-notes/{{.Version}}.md", "release-notes/1.2.0.md", ""},
+		{"Syntax error", "{{.Tag", "", "invalid notes file template"},
+		{"Unknown field", "{{.Name}}.md", "", "error rendering notes file template"},
+		{"Empty name", "{{if false}}x{{end}}", "", "must yield a single line file name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseNotesFileTemplate(tt.text)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got: %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			name, err := tmpl.Render(NotesFileData{Tag: "v1.2.0", Version: "1.2.0"})
+			if err != nil || name != tt.expected {
+				t.Errorf("Render() = %q, %v, want %q", name, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExcludeSections(t *testing.T) {
+	content := "## [1.0.0] - 2024-01-01\n\n### Added\n\n- Feature A\n\n### Internal\n\n- Refactor B\n"
+
+	if result := ExcludeSections(content, nil); result != content {
+		t.Errorf("Expected content without names to be kept, got %q", result)
+	}
+	notes, err := GetVersionSection(ExcludeSections(content, []string{"Internal"}), "1.0.0")
+	if err != nil || notes != "### Added\n\n- Feature A\n" {
+		t.Errorf("Expected notes without Internal, got %q, %v", notes, err)
+	}
+}
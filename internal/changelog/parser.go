@@ -0,0 +1,289 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/peiman/changie/internal/semver"
+)
+
+// Changelog is a structured view of a Keep a Changelog formatted file
+type Changelog struct {
+	Header   []string   // Title and introduction lines before the first version
+	Versions []*Version // Version blocks in file order, including Unreleased
+	Links    []string   // Link reference definitions, usually at the end of the file
+}
+
+// Version is a "## [x.y.z] - date" block of the changelog
+type Version struct {
+	Name     string   // "Unreleased" or the version number
+	Date     string   // Text after the " - " separator, empty when absent
+	Header   string   // Original header line
+	Body     []string // Lines between the header and the first section
+	Sections []*Section
+}
+
+// Section is a "### Name" block within a version
+type Section struct {
+	Name    string
+	Body    []string // Lines before the first entry
+	Entries []*Entry
+}
+
+// Entry is a top-level bullet together with its nested lines
+type Entry struct {
+	Text   string   // Bullet text without the leading "- ", "* " or "+ "
+	Nested []string // Indented sub-bullets, code blocks and continuation lines
+}
+
+var (
+	versionHeaderRegex = regexp.MustCompile(`^## \[([^\]]+)\](?:\s*-\s*(.*))?$`)
+	linkLineRegex      = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// Parse reads changelog content into its structured form
+func Parse(content string) *Changelog {
+	c := &Changelog{}
+	var version *Version
+	var section *Section
+	var entry *Entry
+	inCodeBlock := false
+
+	for _, rawLine := range strings.Split(content, "\n") {
+		line := strings.TrimRight(rawLine, " \t\r")
+		trimmedLine := strings.TrimSpace(line)
+
+		if inCodeBlock || isCodeFence(trimmedLine) {
+			if isCodeFence(trimmedLine) {
+				inCodeBlock = !inCodeBlock
+			}
+			switch {
+			case entry != nil:
+				entry.Nested = append(entry.Nested, line)
+			case section != nil:
+				section.Body = append(section.Body, line)
+			case version != nil:
+				version.Body = append(version.Body, line)
+			default:
+				c.Header = append(c.Header, line)
+			}
+			continue
+		}
+
+		if matches := versionHeaderRegex.FindStringSubmatch(trimmedLine); matches != nil {
+			version = &Version{Name: matches[1], Date: strings.TrimSpace(matches[2]), Header: trimmedLine}
+			c.Versions = append(c.Versions, version)
+			section, entry = nil, nil
+			continue
+		}
+		if version == nil {
+			c.Header = append(c.Header, line)
+			continue
+		}
+		if linkLineRegex.MatchString(trimmedLine) {
+			c.Links = append(c.Links, trimmedLine)
+			entry = nil
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "### ") {
+			section = &Section{Name: strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))}
+			version.Sections = append(version.Sections, section)
+			entry = nil
+			continue
+		}
+		if trimmedLine == "" {
+			continue
+		}
+
+		switch {
+		case section == nil:
+			version.Body = append(version.Body, line)
+		case line == trimmedLine && isEntryLine(line):
+			entry = &Entry{Text: line[2:]}
+			section.Entries = append(section.Entries, entry)
+		case entry != nil:
+			entry.Nested = append(entry.Nested, line)
+		default:
+			section.Body = append(section.Body, line)
+		}
+	}
+
+	// Remove trailing blank lines from the header
+	for len(c.Header) > 0 && c.Header[len(c.Header)-1] == "" {
+		c.Header = c.Header[:len(c.Header)-1]
+	}
+
+	return c
+}
+
+// isEntryLine reports whether a trimmed line starts a "- ", "* " or "+ " list item
+func isEntryLine(trimmedLine string) bool {
+	return strings.HasPrefix(trimmedLine, "- ") || strings.HasPrefix(trimmedLine, "* ") || strings.HasPrefix(trimmedLine, "+ ")
+}
+
+// String renders the changelog using the standard changie formatting
+func (c *Changelog) String() string {
+	var lines []string
+	if len(c.Header) > 0 {
+		lines = append(lines, c.Header...)
+		lines = append(lines, "")
+	}
+	for _, v := range c.Versions {
+		lines = append(lines, v.Lines()...)
+	}
+	if len(c.Links) > 0 {
+		lines = append(lines, c.Links...)
+	}
+
+	// Remove trailing blank lines and end with a single newline
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Version returns the version block with the given name, or nil if it doesn't exist
+func (c *Changelog) Version(name string) *Version {
+	for _, v := range c.Versions {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// VersionsBetween returns the released versions newer than from and up to and
+// including to, in file order. With includeFrom, the from version is included
+// as well. Versions that aren't valid semantic versions, such as Unreleased,
+// are skipped.
+func (c *Changelog) VersionsBetween(from, to string, includeFrom bool) ([]*Version, error) {
+	order, err := semver.Compare(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %s..%s: %w", from, to, err)
+	}
+	if order > 0 {
+		return nil, fmt.Errorf("version %s is newer than %s", from, to)
+	}
+
+	var versions []*Version
+	for _, v := range c.Versions {
+		afterFrom, err := semver.Compare(v.Name, from)
+		if err != nil {
+			continue
+		}
+		beforeTo, _ := semver.Compare(v.Name, to)
+		if (afterFrom > 0 || (includeFrom && afterFrom == 0)) && beforeTo <= 0 {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// Lines renders the version block, followed by a blank separator line
+func (v *Version) Lines() []string {
+	lines := []string{v.Header, ""}
+	if len(v.Body) > 0 {
+		lines = append(lines, v.Body...)
+		lines = append(lines, "")
+	}
+	for _, s := range v.Sections {
+		lines = append(lines, "### "+s.Name, "")
+		if len(s.Body) > 0 {
+			lines = append(lines, s.Body...)
+		}
+		for _, e := range s.Entries {
+			lines = append(lines, e.Lines()...)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// Section returns the section with the given name, or nil if it doesn't exist
+func (v *Version) Section(name string) *Section {
+	for _, s := range v.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// EntryCount returns the number of entries across all sections of the version
+func (v *Version) EntryCount() int {
+	count := 0
+	for _, s := range v.Sections {
+		count += len(s.Entries)
+	}
+	return count
+}
+
+// SortSections orders the sections of the version by the given section order.
+// Sections not in the order keep their relative position after the known ones.
+func (v *Version) SortSections(order []string) {
+	rank := func(name string) int {
+		for i, s := range order {
+			if s == name {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sort.SliceStable(v.Sections, func(i, j int) bool {
+		return rank(v.Sections[i].Name) < rank(v.Sections[j].Name)
+	})
+}
+
+// RemoveSections removes the sections with the given names from the version.
+// Names are compared case-insensitively.
+func (v *Version) RemoveSections(names []string) {
+	var kept []*Section
+	for _, s := range v.Sections {
+		excluded := false
+		for _, name := range names {
+			excluded = excluded || strings.EqualFold(s.Name, name)
+		}
+		if !excluded {
+			kept = append(kept, s)
+		}
+	}
+	v.Sections = kept
+}
+
+// Lines renders the entry as a bullet followed by its nested lines
+func (e *Entry) Lines() []string {
+	return append([]string{"- " + e.Text}, e.Nested...)
+}
+
+// MoveVersion moves the version block before or after the target version.
+// Links are left as they are.
+func (c *Changelog) MoveVersion(name, target string, after bool) error {
+	if name == target {
+		return fmt.Errorf("can't move version %s relative to itself", name)
+	}
+	v := c.Version(name)
+	if v == nil {
+		return fmt.Errorf("version %s not found", name)
+	}
+	if c.Version(target) == nil {
+		return fmt.Errorf("version %s not found", target)
+	}
+
+	var versions []*Version
+	for _, other := range c.Versions {
+		switch {
+		case other == v:
+			continue
+		case other.Name == target && after:
+			versions = append(versions, other, v)
+		case other.Name == target:
+			versions = append(versions, v, other)
+		default:
+			versions = append(versions, other)
+		}
+	}
+	c.Versions = versions
+	return nil
+}
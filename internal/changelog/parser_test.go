@@ -0,0 +1,210 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Fixed
+
+- Bug fix
+  - With detail
+
+### Added
+
+- Feature A
+- Feature B
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+
+	c := Parse(content)
+
+	if len(c.Header) != 3 {
+		t.Errorf("Expected 3 header lines, got %d: %q", len(c.Header), c.Header)
+	}
+	if len(c.Versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(c.Versions))
+	}
+	if c.Versions[0].Name != "Unreleased" || c.Versions[0].Date != "" {
+		t.Errorf("Unexpected first version: %+v", c.Versions[0])
+	}
+	if c.Versions[1].Name != "1.0.0" || c.Versions[1].Date != "2023-01-01" {
+		t.Errorf("Unexpected second version: %+v", c.Versions[1])
+	}
+	if got := c.Versions[0].EntryCount(); got != 3 {
+		t.Errorf("Expected 3 unreleased entries, got %d", got)
+	}
+	fixed := c.Version("Unreleased").Section("Fixed")
+	if fixed == nil || len(fixed.Entries) != 1 || len(fixed.Entries[0].Nested) != 1 {
+		t.Errorf("Expected Fixed section with one nested entry, got %+v", fixed)
+	}
+	if len(c.Links) != 2 {
+		t.Errorf("Expected 2 links, got %d", len(c.Links))
+	}
+	if c.Version("2.0.0") != nil {
+		t.Error("Expected nil for a missing version")
+	}
+
+	if got := c.String(); got != content {
+		t.Errorf("Rendered changelog does not match input.\nGot:\n%s\nExpected:\n%s", got, content)
+	}
+}
+
+func TestSortSections(t *testing.T) {
+	v := &Version{Sections: []*Section{{Name: "Security"}, {Name: "Custom"}, {Name: "Fixed"}, {Name: "Added"}}}
+
+	v.SortSections(sectionOrder)
+
+	expected := []string{"Added", "Fixed", "Security", "Custom"}
+	for i, name := range expected {
+		if v.Sections[i].Name != name {
+			t.Errorf("Section %d: expected %s, got %s", i, name, v.Sections[i].Name)
+		}
+	}
+}
+
+func TestRemoveSections(t *testing.T) {
+	v := &Version{Sections: []*Section{{Name: "Added"}, {Name: "Internal"}, {Name: "Fixed"}}}
+
+	v.RemoveSections([]string{"internal", "Security"})
+
+	if len(v.Sections) != 2 || v.Sections[0].Name != "Added" || v.Sections[1].Name != "Fixed" {
+		t.Errorf("Expected Added and Fixed to be kept, got %v", v.Sections)
+	}
+}
+
+func TestVersionsBetween(t *testing.T) {
+	c := Parse(`# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Unreleased feature
+
+## [1.2.0] - 2024-03-01
+
+### Added
+
+- Feature C
+
+## [1.1.0] - 2024-02-01
+
+### Fixed
+
+- Fix B
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Feature A
+`)
+
+	tests := []struct {
+		name        string
+		from, to    string
+		includeFrom bool
+		expected    []string
+		wantErr     bool
+	}{
+		{"Exclusive from", "1.0.0", "1.2.0", false, []string{"1.2.0", "1.1.0"}, false},
+		{"Inclusive from", "1.0.0", "1.2.0", true, []string{"1.2.0", "1.1.0", "1.0.0"}, false},
+		{"Single version", "1.1.0", "1.1.0", true, []string{"1.1.0"}, false},
+		{"Versions not in changelog", "0.9.0", "1.1.5", false, []string{"1.1.0", "1.0.0"}, false},
+		{"Prefixed versions", "v1.1.0", "v1.2.0", false, []string{"1.2.0"}, false},
+		{"Reversed range", "1.2.0", "1.0.0", false, nil, true},
+		{"Invalid version", "1.0", "1.2.0", false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, err := c.VersionsBetween(tt.from, tt.to, tt.includeFrom)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VersionsBetween() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, v := range versions {
+				names = append(names, v.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("VersionsBetween() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoveVersion(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-03-01
+
+## [1.0.1] - 2024-02-15
+
+## [1.1.0] - 2024-02-01
+
+## [1.0.0] - 2024-01-01
+
+[1.2.0]: https://github.com/peiman/changie/compare/1.1.0...1.2.0
+`
+
+	tests := []struct {
+		name          string
+		version       string
+		target        string
+		after         bool
+		expected      []string
+		expectedError string
+	}{
+		{"Move after", "1.0.1", "1.1.0", true, []string{"Unreleased", "1.2.0", "1.1.0", "1.0.1", "1.0.0"}, ""},
+		{"Move before", "1.1.0", "1.0.1", false, []string{"Unreleased", "1.2.0", "1.1.0", "1.0.1", "1.0.0"}, ""},
+		{"Move to the end", "1.2.0", "1.0.0", true, []string{"Unreleased", "1.0.1", "1.1.0", "1.0.0", "1.2.0"}, ""},
+		{"Missing version", "2.0.0", "1.0.0", false, nil, "version 2.0.0 not found"},
+		{"Missing target", "1.0.1", "0.9.0", false, nil, "version 0.9.0 not found"},
+		{"Same version", "1.0.1", "1.0.1", false, nil, "can't move version 1.0.1 relative to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Parse(content)
+			err := c.MoveVersion(tt.version, tt.target, tt.after)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("MoveVersion() error = %v, want %q", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoveVersion() error = %v", err)
+			}
+
+			var names []string
+			for _, v := range c.Versions {
+				names = append(names, v.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("MoveVersion() order = %v, want %v", names, tt.expected)
+			}
+			if len(c.Links) != 1 {
+				t.Errorf("Expected the links to be kept, got %v", c.Links)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenameReferences replaces the markdown links to the old changelog file name
+// in the content with links to the new name, and returns the number of links
+// replaced
+func RenameReferences(content, oldName, newName string) (string, int) {
+	oldLink := "](" + filepath.Base(oldName) + ")"
+	count := strings.Count(content, oldLink)
+	return strings.ReplaceAll(content, oldLink, "]("+filepath.Base(newName)+")"), count
+}
+
+// RenameFile renames the changelog file with the given move function, e.g.
+// os.Rename or git mv, and updates the links to itself in the renamed file.
+// It fails if the target already exists. The number of links updated is
+// returned.
+func RenameFile(from, to string, move func(from, to string) error) (int, error) {
+	if _, err := os.Stat(to); err == nil {
+		return 0, fmt.Errorf("%s already exists", to)
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("error checking %s: %w", to, err)
+	}
+	content, err := os.ReadFile(from)
+	if err != nil {
+		return 0, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	if err := move(from, to); err != nil {
+		return 0, err
+	}
+
+	updated, count := RenameReferences(string(content), from, to)
+	if count == 0 {
+		return 0, nil
+	}
+	if err := os.WriteFile(to, []byte(updated), 0644); err != nil {
+		return 0, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,74 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameReferences(t *testing.T) {
+	content := "Older releases are in [CHANGELOG-2023.md](CHANGELOG-2023.md).\nSee [the changelog](CHANGELOG.md) and [notes](docs/CHANGELOG.md).\n"
+
+	updated, count := RenameReferences(content, "CHANGELOG.md", "HISTORY.md")
+	expected := "Older releases are in [CHANGELOG-2023.md](CHANGELOG-2023.md).\nSee [the changelog](HISTORY.md) and [notes](docs/CHANGELOG.md).\n"
+	if updated != expected || count != 1 {
+		t.Errorf("RenameReferences() = %q, %d, want %q, 1", updated, count, expected)
+	}
+}
+
+func TestRenameFile(t *testing.T) {
+	content := "# Changelog\n\nThis is [the changelog](CHANGELOG.md).\n"
+
+	t.Run("Rename", func(t *testing.T) {
+		dir := t.TempDir()
+		from, to := filepath.Join(dir, "CHANGELOG.md"), filepath.Join(dir, "HISTORY.md")
+		if err := os.WriteFile(from, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		count, err := RenameFile(from, to, os.Rename)
+		if err != nil || count != 1 {
+			t.Fatalf("RenameFile() = %d, %v, want 1, nil", count, err)
+		}
+		if _, err := os.Stat(from); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be moved", from)
+		}
+		result, _ := os.ReadFile(to)
+		if expected := "# Changelog\n\nThis is [the changelog](HISTORY.md).\n"; string(result) != expected {
+			t.Errorf("Renamed file =\n%s\nwant\n%s", result, expected)
+		}
+	})
+
+	t.Run("Target exists", func(t *testing.T) {
+		dir := t.TempDir()
+		from, to := filepath.Join(dir, "CHANGELOG.md"), filepath.Join(dir, "HISTORY.md")
+		for _, file := range []string{from, to} {
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		moved := false
+		_, err := RenameFile(from, to, func(string, string) error {
+			moved = true
+			return nil
+		})
+		if err == nil || moved {
+			t.Errorf("Expected an error without moving the file, got moved=%v err=%v", moved, err)
+		}
+	})
+
+	t.Run("Move fails", func(t *testing.T) {
+		dir := t.TempDir()
+		from := filepath.Join(dir, "CHANGELOG.md")
+		if err := os.WriteFile(from, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		moveErr := errors.New("git mv failed")
+		if _, err := RenameFile(from, filepath.Join(dir, "HISTORY.md"), func(string, string) error { return moveErr }); !errors.Is(err, moveErr) {
+			t.Errorf("RenameFile() error = %v, want %v", err, moveErr)
+		}
+	})
+}
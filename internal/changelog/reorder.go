@@ -0,0 +1,43 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+)
+
+// ReorderSections sorts the sections of the Unreleased version into the Keep a
+// Changelog order, or the sections of every version with all set. Sections
+// that aren't Keep a Changelog sections go after the known ones, in the order
+// they were in. Entries are left as they are.
+func ReorderSections(content string, all bool) string {
+	c := Parse(content)
+	for _, v := range c.Versions {
+		if all || v.Name == "Unreleased" {
+			v.SortSections(sectionOrder)
+		}
+	}
+	return c.String()
+}
+
+// ReorderChangelog reorders the sections of the changelog file and reports
+// whether anything changed. With check set, the file is left untouched.
+func ReorderChangelog(changelogFile string, all, check bool) (bool, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return false, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	formatted := Parse(string(content)).String()
+	reordered := ReorderSections(string(content), all)
+	if reordered == formatted {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(reordered), 0644); err != nil {
+		return false, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,132 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReorderSections(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Fix login
+
+### Notes
+
+- Custom section
+
+### Added
+
+- Feature B
+- Feature A
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix typo
+
+### Added
+
+- Initial release
+`
+	expectedUnreleased := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature B
+- Feature A
+
+### Fixed
+
+- Fix login
+
+### Notes
+
+- Custom section
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix typo
+
+### Added
+
+- Initial release
+`
+	expectedAll := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature B
+- Feature A
+
+### Fixed
+
+- Fix login
+
+### Notes
+
+- Custom section
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+
+### Fixed
+
+- Fix typo
+`
+
+	if got := ReorderSections(content, false); got != expectedUnreleased {
+		t.Errorf("ReorderSections() =\n%s\nwant\n%s", got, expectedUnreleased)
+	}
+	if got := ReorderSections(content, true); got != expectedAll {
+		t.Errorf("ReorderSections(all) =\n%s\nwant\n%s", got, expectedAll)
+	}
+	if got := ReorderSections(expectedAll, true); got != expectedAll {
+		t.Errorf("ReorderSections() of an ordered changelog =\n%s\nwant it unchanged", got)
+	}
+}
+
+func TestReorderChangelog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	initialContent := "# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- Fix login\n\n### Added\n\n- Feature A\n"
+	if err := os.WriteFile(file, []byte(initialContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := ReorderChangelog(file, false, true)
+	if err != nil || !changed {
+		t.Errorf("Expected check to report changes, got changed=%v err=%v", changed, err)
+	}
+	content, _ := os.ReadFile(file)
+	if string(content) != initialContent {
+		t.Error("Expected check mode not to modify the file")
+	}
+
+	changed, err = ReorderChangelog(file, false, false)
+	if err != nil || !changed {
+		t.Errorf("Expected changes to be written, got changed=%v err=%v", changed, err)
+	}
+
+	changed, err = ReorderChangelog(file, false, true)
+	if err != nil || changed {
+		t.Errorf("Expected reordered file to pass the check, got changed=%v err=%v", changed, err)
+	}
+
+	if _, err := ReorderChangelog(filepath.Join(t.TempDir(), "CHANGELOG.md"), false, false); err == nil {
+		t.Error("Expected an error for a missing changelog")
+	}
+}
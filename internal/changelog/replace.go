@@ -0,0 +1,59 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReplaceOrAddEntry replaces the text of the entry of the Unreleased section
+// that matches match, or adds newContent when no entry matches. It reports
+// whether an entry was replaced.
+func ReplaceOrAddEntry(changelogFile, section, match, newContent string) (bool, error) {
+	return ReplaceOrAddEntryWithOptions(changelogFile, section, match, newContent, AddOptions{})
+}
+
+// ReplaceOrAddEntryWithOptions replaces the text of the entry of the
+// Unreleased section that matches match, or adds newContent when no entry
+// matches. Entries are matched like duplicates, ignoring their emoji prefix
+// and metadata. Sub-bullets and code blocks of the replaced entry are kept.
+// It reports whether an entry was replaced.
+func ReplaceOrAddEntryWithOptions(changelogFile, section, match, newContent string, opts AddOptions) (bool, error) {
+	if !contains(sectionOrder, section) {
+		return false, fmt.Errorf("unknown section %q", section)
+	}
+	if strings.TrimSpace(newContent) == "" {
+		return false, fmt.Errorf("empty content")
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return false, fmt.Errorf("error reading changelog: %w", err)
+	}
+	c := Parse(string(content))
+
+	var entry *Entry
+	if unreleased := c.Version("Unreleased"); unreleased != nil {
+		if s := unreleased.Section(section); s != nil {
+			for _, e := range s.Entries {
+				if normalizeEntry(e.continuedText()) == normalizeEntry(match) {
+					entry = e
+					break
+				}
+			}
+		}
+	}
+	if entry == nil {
+		_, _, err := ApplyEntriesWithOptions(changelogFile, []BatchEntry{{Section: section, Content: newContent}}, opts)
+		return false, err
+	}
+
+	wrapped := wrapEntry(strings.TrimSpace(newContent), opts.WrapWidth)
+	entry.Nested = append(wrapped[1:], entry.Nested[entry.continuationLines():]...)
+	entry.Text = strings.TrimPrefix(wrapped[0], "- ")
+
+	if err := os.WriteFile(changelogFile, []byte(c.String()), 0644); err != nil {
+		return false, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,171 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceOrAddEntry(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- 🐛 Fix login for users with long
+  names [severity=high]
+  - Also on mobile
+- Fix typo
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix login
+`
+
+	tests := []struct {
+		name             string
+		section          string
+		match            string
+		newContent       string
+		opts             AddOptions
+		expectedReplaced bool
+		expected         string
+		wantErr          bool
+	}{
+		{
+			name:             "Replace wording, keeping sub-bullets",
+			section:          "Fixed",
+			match:            "Fix login for users with long names",
+			newContent:       "Fix login for users with names over 64 characters [severity=high]",
+			expectedReplaced: true,
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Fix login for users with names over 64 characters [severity=high]
+  - Also on mobile
+- Fix typo
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix login
+`,
+		},
+		{
+			name:             "Replace the same entry with new metadata",
+			section:          "Fixed",
+			match:            "Fix typo [team=docs]",
+			newContent:       "Fix typo [team=docs]",
+			expectedReplaced: true,
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- 🐛 Fix login for users with long
+  names [severity=high]
+  - Also on mobile
+- Fix typo [team=docs]
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix login
+`,
+		},
+		{
+			name:       "Add when nothing matches",
+			section:    "Fixed",
+			match:      "Fix login",
+			newContent: "Fix logout",
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- 🐛 Fix login for users with long
+  names [severity=high]
+  - Also on mobile
+- Fix typo
+- Fix logout
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix login
+`,
+		},
+		{
+			name:             "Wrap the new text",
+			section:          "Fixed",
+			match:            "Fix typo",
+			newContent:       "Fix a typo in the installation guide",
+			opts:             AddOptions{WrapWidth: 24},
+			expectedReplaced: true,
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- 🐛 Fix login for users with long
+  names [severity=high]
+  - Also on mobile
+- Fix a typo in the
+  installation guide
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Fix login
+`,
+		},
+		{
+			name:       "Unknown section",
+			section:    "Notes",
+			match:      "Fix typo",
+			newContent: "Fix typo",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			replaced, err := ReplaceOrAddEntryWithOptions(file, tt.section, tt.match, tt.newContent, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReplaceOrAddEntryWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if replaced != tt.expectedReplaced {
+				t.Errorf("ReplaceOrAddEntryWithOptions() replaced = %v, want %v", replaced, tt.expectedReplaced)
+			}
+			result, _ := os.ReadFile(file)
+			if string(result) != tt.expected {
+				t.Errorf("ReplaceOrAddEntryWithOptions() =\n%s\nwant\n%s", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ReplaceOrAddEntry(filepath.Join(t.TempDir(), "CHANGELOG.md"), "Fixed", "Fix", "Fix"); err == nil {
+		t.Error("Expected an error for a missing changelog")
+	}
+}
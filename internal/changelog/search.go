@@ -0,0 +1,34 @@
+package changelog
+
+import (
+	"regexp"
+)
+
+// Match is a changelog entry found by Grep
+type Match struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`
+	Section string `json:"section"`
+	Entry   string `json:"entry"`
+}
+
+// Grep returns the entries matching the regular expression, in file order.
+// Nested lines are joined to the entry text, so wrapped entries match as a
+// whole. With unreleasedOnly, only the Unreleased version is searched.
+func (c *Changelog) Grep(pattern *regexp.Regexp, unreleasedOnly bool) []Match {
+	matches := []Match{}
+	for _, v := range c.Versions {
+		if unreleasedOnly && v.Name != "Unreleased" {
+			continue
+		}
+		for _, s := range v.Sections {
+			for _, e := range s.Entries {
+				text := e.joinedText()
+				if pattern.MatchString(text) {
+					matches = append(matches, Match{Version: v.Name, Date: v.Date, Section: s.Name, Entry: text})
+				}
+			}
+		}
+	}
+	return matches
+}
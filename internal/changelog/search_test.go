@@ -0,0 +1,82 @@
+package changelog
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGrep(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Crash when the changelog is empty
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Emoji prefixes for entries
+
+### Fixed
+
+- Crash on missing tags, which
+  happened in new repositories
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+	c := Parse(content)
+
+	tests := []struct {
+		name           string
+		pattern        string
+		unreleasedOnly bool
+		expected       []Match
+	}{
+		{
+			name:    "All versions",
+			pattern: `[Cc]rash`,
+			expected: []Match{
+				{Version: "Unreleased", Section: "Fixed", Entry: "Crash when the changelog is empty"},
+				{Version: "1.1.0", Date: "2024-02-01", Section: "Fixed", Entry: "Crash on missing tags, which happened in new repositories"},
+			},
+		},
+		{
+			name:     "Wrapped entry",
+			pattern:  `which happened`,
+			expected: []Match{{Version: "1.1.0", Date: "2024-02-01", Section: "Fixed", Entry: "Crash on missing tags, which happened in new repositories"}},
+		},
+		{
+			name:           "Unreleased only",
+			pattern:        `Crash`,
+			unreleasedOnly: true,
+			expected:       []Match{{Version: "Unreleased", Section: "Fixed", Entry: "Crash when the changelog is empty"}},
+		},
+		{
+			name:     "Case insensitive",
+			pattern:  `(?i)INITIAL`,
+			expected: []Match{{Version: "1.0.0", Date: "2024-01-01", Section: "Added", Entry: "Initial release"}},
+		},
+		{
+			name:     "No matches",
+			pattern:  `INITIAL`,
+			expected: []Match{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := c.Grep(regexp.MustCompile(tt.pattern), tt.unreleasedOnly)
+			if !reflect.DeepEqual(matches, tt.expected) {
+				t.Errorf("Grep(%q) = %v, want %v", tt.pattern, matches, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetSectionEntries replaces the entries of a section of the Unreleased part
+// of the changelog with the given entries
+func SetSectionEntries(changelogFile, section string, entries []string) error {
+	return SetSectionEntriesWithOptions(changelogFile, section, entries, AddOptions{})
+}
+
+// SetSectionEntriesWithOptions replaces the entries of a section of the
+// Unreleased part of the changelog with the given entries, for example to
+// regenerate the section from commits. The section is created at its place in
+// the Keep a Changelog order if it doesn't exist, and removed when entries is
+// empty. The Unreleased part is created if it doesn't exist.
+func SetSectionEntriesWithOptions(changelogFile, section string, entries []string, opts AddOptions) error {
+	name := ""
+	for _, s := range sectionOrder {
+		if strings.EqualFold(s, section) {
+			name = s
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("unknown section %s, expected one of %s", section, strings.Join(sectionOrder, ", "))
+	}
+	for i, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			return fmt.Errorf("entry %d is empty", i+1)
+		}
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return fmt.Errorf("error reading changelog: %w", err)
+	}
+	c := Parse(string(content))
+
+	unreleased := c.Version("Unreleased")
+	if unreleased == nil {
+		unreleased = &Version{Name: "Unreleased", Header: "## [Unreleased]"}
+		c.Versions = append([]*Version{unreleased}, c.Versions...)
+	}
+
+	var sections []*Section
+	for _, s := range unreleased.Sections {
+		if s.Name != name {
+			sections = append(sections, s)
+		}
+	}
+	if len(entries) > 0 {
+		replaced := &Section{Name: name}
+		for _, entry := range entries {
+			lines := wrapEntry(strings.TrimSpace(entry), opts.WrapWidth)
+			replaced.Entries = append(replaced.Entries, &Entry{Text: strings.TrimPrefix(lines[0], "- "), Nested: lines[1:]})
+		}
+
+		// Insert the section before the first section that comes after it in
+		// the canonical order, so the order of the other sections is kept
+		position := len(sections)
+		for i, s := range sections {
+			if sectionRank(s.Name) > sectionRank(name) {
+				position = i
+				break
+			}
+		}
+		sections = append(sections[:position], append([]*Section{replaced}, sections[position:]...)...)
+	}
+	unreleased.Sections = sections
+
+	if err := os.WriteFile(changelogFile, []byte(c.String()), 0644); err != nil {
+		return fmt.Errorf("error writing changelog: %w", err)
+	}
+	return nil
+}
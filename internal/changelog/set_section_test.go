@@ -0,0 +1,189 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetSectionEntries(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature B
+
+### Fixed
+
+- Fix login
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+
+	tests := []struct {
+		name     string
+		content  string
+		section  string
+		entries  []string
+		opts     AddOptions
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:    "Replace existing section",
+			content: content,
+			section: "Added",
+			entries: []string{"Feature C", "Feature A"},
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature C
+- Feature A
+
+### Fixed
+
+- Fix login
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`,
+		},
+		{
+			name:    "Create missing section in canonical order",
+			content: content,
+			section: "changed",
+			entries: []string{"Change defaults"},
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature B
+
+### Changed
+
+- Change defaults
+
+### Fixed
+
+- Fix login
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`,
+		},
+		{
+			name:    "Remove section",
+			content: content,
+			section: "Fixed",
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature B
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`,
+		},
+		{
+			name:    "Create Unreleased",
+			content: "# Changelog\n\n## [1.0.0] - 2024-01-01\n\n### Added\n\n- Initial release\n",
+			section: "Security",
+			entries: []string{"Update TLS defaults to require a recent version"},
+			opts:    AddOptions{WrapWidth: 30},
+			expected: `# Changelog
+
+## [Unreleased]
+
+### Security
+
+- Update TLS defaults to
+  require a recent version
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`,
+		},
+		{
+			name:    "Unknown section",
+			content: content,
+			section: "Notes",
+			entries: []string{"Note"},
+			wantErr: true,
+		},
+		{
+			name:    "Empty entry",
+			content: content,
+			section: "Added",
+			entries: []string{"Feature C", " "},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := SetSectionEntriesWithOptions(file, tt.section, tt.entries, tt.opts)
+			result, _ := os.ReadFile(file)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				if string(result) != tt.content {
+					t.Errorf("Expected the changelog to be unchanged, got:\n%s", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetSectionEntriesWithOptions() error = %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("SetSectionEntriesWithOptions() =\n%s\nwant\n%s", result, tt.expected)
+			}
+
+			// Setting the same entries again changes nothing
+			if err := SetSectionEntriesWithOptions(file, tt.section, tt.entries, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if again, _ := os.ReadFile(file); string(again) != tt.expected {
+				t.Errorf("Second SetSectionEntriesWithOptions() =\n%s\nwant\n%s", again, tt.expected)
+			}
+		})
+	}
+
+	if err := SetSectionEntries(filepath.Join(t.TempDir(), "CHANGELOG.md"), "Added", []string{"Feature"}); err == nil {
+		t.Error("Expected an error for a missing changelog")
+	}
+}
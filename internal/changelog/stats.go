@@ -0,0 +1,42 @@
+package changelog
+
+// VersionStats is the number of entries per section of a version
+type VersionStats struct {
+	Version  string         `json:"version"`
+	Date     string         `json:"date,omitempty"`
+	Sections map[string]int `json:"sections"` // Entries per section, zero for Keep a Changelog sections without entries
+	Total    int            `json:"total"`
+}
+
+// Stats counts the entries per section of the version. All Keep a Changelog
+// sections are included, with zero entries when the version doesn't have them.
+func (v *Version) Stats() VersionStats {
+	stats := VersionStats{Version: v.Name, Date: v.Date, Sections: map[string]int{}}
+	for _, name := range sectionOrder {
+		stats.Sections[name] = 0
+	}
+	for _, s := range v.Sections {
+		stats.Sections[s.Name] += len(s.Entries)
+		stats.Total += len(s.Entries)
+	}
+	return stats
+}
+
+// Stats counts the entries per section of every version, in file order, and
+// sums them up across all versions
+func (c *Changelog) Stats() ([]VersionStats, VersionStats) {
+	versions := []VersionStats{}
+	total := VersionStats{Sections: map[string]int{}}
+	for _, name := range sectionOrder {
+		total.Sections[name] = 0
+	}
+	for _, v := range c.Versions {
+		stats := v.Stats()
+		versions = append(versions, stats)
+		for name, count := range stats.Sections {
+			total.Sections[name] += count
+		}
+		total.Total += stats.Total
+	}
+	return versions, total
+}
@@ -0,0 +1,57 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	c := Parse(`# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Fix C
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature B
+- Feature C
+
+### Fixed
+
+- Fix B
+
+### Internal
+
+- Refactor
+
+## [1.0.0] - 2024-01-01
+`)
+
+	versions, total := c.Stats()
+
+	zero := func(counts map[string]int) map[string]int {
+		sections := map[string]int{"Added": 0, "Changed": 0, "Deprecated": 0, "Removed": 0, "Fixed": 0, "Security": 0}
+		for name, count := range counts {
+			sections[name] = count
+		}
+		return sections
+	}
+	expected := []VersionStats{
+		{Version: "Unreleased", Sections: zero(map[string]int{"Fixed": 1}), Total: 1},
+		{Version: "1.1.0", Date: "2024-02-01", Sections: zero(map[string]int{"Added": 2, "Fixed": 1, "Internal": 1}), Total: 4},
+		{Version: "1.0.0", Date: "2024-01-01", Sections: zero(nil), Total: 0},
+	}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("Stats() versions = %v, want %v", versions, expected)
+	}
+
+	expectedTotal := VersionStats{Sections: zero(map[string]int{"Added": 2, "Fixed": 2, "Internal": 1}), Total: 5}
+	if !reflect.DeepEqual(total, expectedTotal) {
+		t.Errorf("Stats() total = %v, want %v", total, expectedTotal)
+	}
+}
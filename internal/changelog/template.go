@@ -0,0 +1,19 @@
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// validateTemplate parses a template and renders it with sample data to report errors early
+func validateTemplate(name, text string, sample interface{}) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("error rendering %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
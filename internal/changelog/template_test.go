@@ -0,0 +1,32 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	sample := NotesData{Version: "1.0.0", Date: "2006-01-02"}
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{"Valid", "Release {{.Version}} on {{.Date}}", ""},
+		{"Syntax error", "Release {{.Version", "invalid sample template"},
+		{"Unknown field", "Released on {{.Day}}", "error rendering sample template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := validateTemplate("sample", tt.text, sample)
+			if tt.wantErr == "" {
+				if err != nil || tmpl == nil || tmpl.Name() != "sample" {
+					t.Errorf("validateTemplate() = %v, %v, want the parsed template", tmpl, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateTemplate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
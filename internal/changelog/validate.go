@@ -0,0 +1,192 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationIssue describes a problem found in a changelog
+type ValidationIssue struct {
+	Line    int // 1-based line number the issue refers to
+	Message string
+	Rule    string // Keep a Changelog rule that is violated, empty for structural problems
+}
+
+func (i ValidationIssue) String() string {
+	if i.Rule != "" {
+		return fmt.Sprintf("line %d: %s [%s]", i.Line, i.Message, i.Rule)
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// Rules checked by ValidateKeepAChangelog
+const (
+	RuleIntro   = "KAC-INTRO"   // The header explains that notable changes are documented in the file
+	RuleLink    = "KAC-LINK"    // Every version, including Unreleased, has a link
+	RuleDate    = "KAC-DATE"    // Released versions are dated in ISO 8601 format, YYYY-MM-DD
+	RuleSection = "KAC-SECTION" // Changes are grouped in the Keep a Changelog sections only
+	RuleOrder   = "KAC-ORDER"   // Sections are in the order Added, Changed, Deprecated, Removed, Fixed, Security
+)
+
+// ValidateChangelog checks the changelog content for structural problems
+func ValidateChangelog(content string) []ValidationIssue {
+	var issues []ValidationIssue
+	issues = append(issues, checkDuplicateVersions(content)...)
+	return issues
+}
+
+// CheckMaxUnreleased reports the Unreleased version when it has more than
+// max entries across all its sections, a sign that a release is overdue
+func CheckMaxUnreleased(content string, max int) []ValidationIssue {
+	unreleased := Parse(content).Version("Unreleased")
+	if unreleased == nil || unreleased.EntryCount() <= max {
+		return nil
+	}
+	headers, _ := versionHeaderLines(content)
+	return []ValidationIssue{{
+		Line:    headers["Unreleased"][0],
+		Message: fmt.Sprintf("[Unreleased] has %d entries, more than the maximum of %d, consider releasing", unreleased.EntryCount(), max),
+	}}
+}
+
+// CheckLinks reports the version headers, including Unreleased, that have no
+// link reference definition
+func CheckLinks(content string) []ValidationIssue {
+	links := map[string]bool{}
+	for _, link := range LinkReferences(content) {
+		links[link.Label] = true
+	}
+
+	var issues []ValidationIssue
+	headers, order := versionHeaderLines(content)
+	for _, version := range order {
+		if !links[version] {
+			issues = append(issues, ValidationIssue{Line: headers[version][0], Message: fmt.Sprintf("version [%s] has no link", version), Rule: RuleLink})
+		}
+	}
+	return issues
+}
+
+// ValidateKeepAChangelog checks the changelog content for structural problems
+// and for deviations from the Keep a Changelog 1.1.0 rules
+func ValidateKeepAChangelog(content string) []ValidationIssue {
+	issues := ValidateChangelog(content)
+
+	lines := strings.Split(content, "\n")
+	links := map[string]bool{}
+	for _, link := range LinkReferences(content) {
+		links[link.Label] = true
+	}
+
+	hasIntro := false
+	inHeader := true
+	inCodeBlock := false
+	var version string
+	rank := 0
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		if matches := versionHeaderRegex.FindStringSubmatch(trimmedLine); matches != nil {
+			inHeader = false
+			version, rank = matches[1], 0
+			if !links[version] {
+				issues = append(issues, ValidationIssue{Line: i + 1, Message: fmt.Sprintf("version [%s] has no link", version), Rule: RuleLink})
+			}
+			if version != "Unreleased" && !isISODate(strings.TrimSpace(matches[2])) {
+				issues = append(issues, ValidationIssue{Line: i + 1, Message: fmt.Sprintf("version [%s] has no YYYY-MM-DD release date", version), Rule: RuleDate})
+			}
+			continue
+		}
+		if inHeader {
+			hasIntro = hasIntro || trimmedLine == headerIntro
+			continue
+		}
+
+		if strings.HasPrefix(trimmedLine, "### ") && version != "" {
+			section := strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+			sectionRank := -1
+			for r, s := range sectionOrder {
+				if s == section {
+					sectionRank = r
+				}
+			}
+			switch {
+			case sectionRank == -1:
+				issues = append(issues, ValidationIssue{Line: i + 1, Message: fmt.Sprintf("unknown section %q in [%s]", section, version), Rule: RuleSection})
+			case sectionRank < rank:
+				issues = append(issues, ValidationIssue{Line: i + 1, Message: fmt.Sprintf("section %s in [%s] is out of order", section, version), Rule: RuleOrder})
+			default:
+				rank = sectionRank
+			}
+		}
+	}
+
+	if !hasIntro {
+		issues = append([]ValidationIssue{{Line: 1, Message: fmt.Sprintf("the header doesn't have the introduction %q", headerIntro), Rule: RuleIntro}}, issues...)
+	}
+	return issues
+}
+
+// isISODate reports whether date is a valid YYYY-MM-DD date
+func isISODate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+// checkDuplicateVersions reports version headers that appear more than once
+func checkDuplicateVersions(content string) []ValidationIssue {
+	var issues []ValidationIssue
+	headers, order := versionHeaderLines(content)
+	for _, version := range order {
+		lineNumbers := headers[version]
+		if len(lineNumbers) < 2 {
+			continue
+		}
+		issues = append(issues, ValidationIssue{
+			Line:    lineNumbers[1],
+			Message: fmt.Sprintf("duplicate version header [%s] on lines %s", version, joinInts(lineNumbers)),
+		})
+	}
+	return issues
+}
+
+// versionHeaderLines maps each version header name to the line numbers it appears on,
+// and returns the distinct names in file order
+func versionHeaderLines(content string) (map[string][]int, []string) {
+	headers := make(map[string][]int)
+	var order []string
+	inCodeBlock := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if isCodeFence(trimmedLine) {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if matches := versionHeaderRegex.FindStringSubmatch(trimmedLine); matches != nil {
+			if _, ok := headers[matches[1]]; !ok {
+				order = append(order, matches[1])
+			}
+			headers[matches[1]] = append(headers[matches[1]], i+1)
+		}
+	}
+	return headers, order
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(parts, ", ")
+}
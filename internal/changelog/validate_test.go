@@ -0,0 +1,215 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateChangelog(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []ValidationIssue
+	}{
+		{
+			name: "Valid changelog",
+			content: `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2023-02-01
+
+## [1.0.0] - 2023-01-01
+`,
+		},
+		{
+			name: "Duplicate version header",
+			content: `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2023-03-01
+
+## [1.1.0] - 2023-02-01
+
+## [1.2.0] - 2023-03-01
+`,
+			expected: []ValidationIssue{
+				{Line: 9, Message: "duplicate version header [1.2.0] on lines 5, 9"},
+			},
+		},
+		{
+			name:    "Header inside code block is ignored",
+			content: "## [1.0.0] - 2023-01-01\n\n### Added\n\n- Example:\n  ```\n  ## [1.0.0] - 2023-01-01\n  ```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateChangelog(tt.content)
+			if len(issues) != len(tt.expected) {
+				t.Fatalf("Expected %d issues, got %d: %v", len(tt.expected), len(issues), issues)
+			}
+			for i, issue := range issues {
+				if issue != tt.expected[i] {
+					t.Errorf("Expected issue %v, got %v", tt.expected[i], issue)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKeepAChangelog(t *testing.T) {
+	valid := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+
+- Feature B
+
+## [1.0.0] - 2023-01-01
+
+### Added
+
+- Feature A
+
+### Fixed
+
+- Fix A
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []ValidationIssue
+	}{
+		{
+			name:    "Compliant changelog",
+			content: valid,
+		},
+		{
+			name: "All rules",
+			content: `# Changelog
+
+## [Unreleased]
+
+### Improved
+
+- Feature B
+
+## [1.1.0] - 01/02/2023
+
+## [1.0.0] - 2023-01-01
+
+### Fixed
+
+- Fix A
+
+### Added
+
+- Feature A
+
+[1.1.0]: https://github.com/peiman/changie/compare/1.0.0...1.1.0
+[1.0.0]: https://github.com/peiman/changie/releases/tag/1.0.0
+`,
+			expected: []ValidationIssue{
+				{Line: 1, Message: `the header doesn't have the introduction "All notable changes to this project will be documented in this file."`, Rule: RuleIntro},
+				{Line: 3, Message: "version [Unreleased] has no link", Rule: RuleLink},
+				{Line: 5, Message: `unknown section "Improved" in [Unreleased]`, Rule: RuleSection},
+				{Line: 9, Message: "version [1.1.0] has no YYYY-MM-DD release date", Rule: RuleDate},
+				{Line: 17, Message: "section Added in [1.0.0] is out of order", Rule: RuleOrder},
+			},
+		},
+		{
+			name:    "Includes structural problems",
+			content: valid + "\n## [1.0.0] - 2023-01-01\n",
+			expected: []ValidationIssue{
+				{Line: 24, Message: "duplicate version header [1.0.0] on lines 11, 24"},
+			},
+		},
+		{
+			name: "Scheduled Unreleased date is allowed",
+			content: `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased] - 2023-02-01
+
+[Unreleased]: https://github.com/peiman/changie/compare/1.0.0...HEAD
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateKeepAChangelog(tt.content)
+			if len(issues) != len(tt.expected) {
+				t.Fatalf("Expected %d issues, got %d: %v", len(tt.expected), len(issues), issues)
+			}
+			for i, issue := range issues {
+				if issue != tt.expected[i] {
+					t.Errorf("Expected issue %v, got %v", tt.expected[i], issue)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationIssueString(t *testing.T) {
+	issue := ValidationIssue{Line: 3, Message: "version [Unreleased] has no link", Rule: RuleLink}
+	if got := issue.String(); got != "line 3: version [Unreleased] has no link [KAC-LINK]" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestCheckMaxUnreleased(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Feature A
+- Feature B
+
+### Fixed
+
+- Fix A
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+`
+
+	if issues := CheckMaxUnreleased(content, 3); len(issues) != 0 {
+		t.Errorf("Expected no issues at the maximum, got: %v", issues)
+	}
+
+	expected := ValidationIssue{Line: 3, Message: "[Unreleased] has 3 entries, more than the maximum of 2, consider releasing"}
+	if issues := CheckMaxUnreleased(content, 2); len(issues) != 1 || issues[0] != expected {
+		t.Errorf("Expected issue %v, got: %v", expected, issues)
+	}
+
+	if issues := CheckMaxUnreleased("# Changelog\n\n## [1.0.0] - 2024-01-01\n\n- Initial release\n", 0); len(issues) != 0 {
+		t.Errorf("Expected no issues without an Unreleased version, got: %v", issues)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	content := "## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n\n```\n## [0.9.0]\n```\n\n[1.0.0]: https://example.com/1.0.0\n"
+	expected := []ValidationIssue{{Line: 1, Message: "version [Unreleased] has no link", Rule: RuleLink}}
+	if issues := CheckLinks(content); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("CheckLinks() = %v, want %v", issues, expected)
+	}
+	if issues := CheckLinks(content + "[Unreleased]: https://example.com/HEAD\n"); len(issues) != 0 {
+		t.Errorf("CheckLinks() = %v, want no issues", issues)
+	}
+}
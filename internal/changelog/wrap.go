@@ -0,0 +1,123 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapEntry renders entry content as a bullet wrapped at width columns, with
+// continuation lines indented under the bullet text. A width of 0 disables wrapping.
+func wrapEntry(content string, width int) []string {
+	if width <= 0 {
+		return []string{"- " + content}
+	}
+
+	var lines []string
+	prefix := "- "
+	current := ""
+	for _, word := range splitWords(content) {
+		if current != "" && utf8.RuneCountInString(prefix+current+" "+word) > width {
+			lines = append(lines, prefix+current)
+			prefix, current = "  ", ""
+		}
+		if current == "" {
+			current = word
+		} else {
+			current += " " + word
+		}
+	}
+	return append(lines, prefix+current)
+}
+
+// splitWords splits text on whitespace, keeping `code spans` together as one word
+func splitWords(text string) []string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		if n := len(words); n > 0 && strings.Count(words[n-1], "`")%2 == 1 {
+			words[n-1] += " " + field
+			continue
+		}
+		words = append(words, field)
+	}
+	return words
+}
+
+// WrapEntries rewraps the text of every entry in the changelog content at width
+// columns. A width of 0 joins wrapped entries back into single lines.
+func WrapEntries(content string, width int) string {
+	c := Parse(content)
+	for _, v := range c.Versions {
+		for _, s := range v.Sections {
+			for _, e := range s.Entries {
+				e.Rewrap(width)
+			}
+		}
+	}
+	return c.String()
+}
+
+// WrapChangelog rewraps the entries of the changelog file and reports whether
+// anything changed. With check set, the file is left untouched.
+func WrapChangelog(changelogFile string, width int, check bool) (bool, error) {
+	content, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return false, fmt.Errorf("error reading changelog: %w", err)
+	}
+
+	formatted := Parse(string(content)).String()
+	wrapped := WrapEntries(string(content), width)
+	if wrapped == formatted {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+
+	if err := os.WriteFile(changelogFile, []byte(wrapped), 0644); err != nil {
+		return false, fmt.Errorf("error writing changelog: %w", err)
+	}
+	return true, nil
+}
+
+// Rewrap rewraps the entry text at width columns. Sub-bullets and code blocks
+// are kept as they are. Entries with markdown hard line breaks are left untouched.
+func (e *Entry) Rewrap(width int) {
+	text := e.continuedText()
+	continuation := e.continuationLines()
+	if strings.HasSuffix(e.Text, "\\") {
+		return
+	}
+	for _, line := range e.Nested[:continuation] {
+		if strings.HasSuffix(line, "\\") {
+			return
+		}
+	}
+
+	wrapped := wrapEntry(text, width)
+	e.Text = strings.TrimPrefix(wrapped[0], "- ")
+	e.Nested = append(wrapped[1:], e.Nested[continuation:]...)
+}
+
+// continuationLines returns the number of nested lines that continue the entry
+// text, before any sub-bullet or code block
+func (e *Entry) continuationLines() int {
+	for i, line := range e.Nested {
+		trimmedLine := strings.TrimSpace(line)
+		if isEntryLine(trimmedLine) || isCodeFence(trimmedLine) {
+			return i
+		}
+	}
+	return len(e.Nested)
+}
+
+// continuedText returns the entry text joined with its continuation lines,
+// without sub-bullets and code blocks
+func (e *Entry) continuedText() string {
+	text := e.Text
+	for _, line := range e.Nested[:e.continuationLines()] {
+		text += " " + strings.TrimSpace(line)
+	}
+	return text
+}
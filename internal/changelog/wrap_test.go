@@ -0,0 +1,106 @@
+package changelog
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWrapEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		width    int
+		expected []string
+	}{
+		{
+			name:     "No wrapping",
+			content:  "A long entry that is not wrapped",
+			width:    0,
+			expected: []string{"- A long entry that is not wrapped"},
+		},
+		{
+			name:     "Short entry",
+			content:  "Short entry",
+			width:    40,
+			expected: []string{"- Short entry"},
+		},
+		{
+			name:     "Hanging indentation",
+			content:  "Improved error messages for better clarity when Git operations fail",
+			width:    30,
+			expected: []string{"- Improved error messages for", "  better clarity when Git", "  operations fail"},
+		},
+		{
+			name:     "Code span is not split",
+			content:  "Use `changie changelog added` to add entries",
+			width:    20,
+			expected: []string{"- Use", "  `changie changelog added`", "  to add entries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapEntry(tt.content, tt.width)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("wrapEntry(%q, %d) =\n%s\nexpected:\n%s", tt.content, tt.width, strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestWrapEntries(t *testing.T) {
+	content := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n" +
+		"- A long entry that should be wrapped at twenty four columns\n" +
+		"- Entry that was\n  wrapped before\n  - With a sub-bullet\n" +
+		"- Hard break \\\n  kept as is\n\n" +
+		"## [1.0.0] - 2023-01-01\n\n### Fixed\n\n- Use `changie changelog fixed` for fixes\n"
+
+	expected := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n" +
+		"- A long entry that should\n  be wrapped at twenty\n  four columns\n" +
+		"- Entry that was wrapped\n  before\n  - With a sub-bullet\n" +
+		"- Hard break \\\n  kept as is\n\n" +
+		"## [1.0.0] - 2023-01-01\n\n### Fixed\n\n- Use\n  `changie changelog fixed`\n  for fixes\n"
+
+	if got := WrapEntries(content, 26); got != expected {
+		t.Errorf("WrapEntries produced incorrect output.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	// Width 0 joins wrapped entries back into single lines
+	unwrapped := WrapEntries(expected, 0)
+	if !strings.Contains(unwrapped, "- A long entry that should be wrapped at twenty four columns\n") {
+		t.Errorf("Expected entry to be unwrapped, got:\n%s", unwrapped)
+	}
+}
+
+func TestWrapChangelogCheck(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	initialContent := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- An entry that is too long\n"
+	if _, err := tmpfile.Write([]byte(initialContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := WrapChangelog(tmpfile.Name(), 20, true)
+	if err != nil || !changed {
+		t.Errorf("Expected check to report changes, got changed=%v err=%v", changed, err)
+	}
+	content, _ := os.ReadFile(tmpfile.Name())
+	if string(content) != initialContent {
+		t.Error("Expected check mode not to modify the file")
+	}
+
+	changed, err = WrapChangelog(tmpfile.Name(), 20, false)
+	if err != nil || !changed {
+		t.Errorf("Expected changes to be written, got changed=%v err=%v", changed, err)
+	}
+
+	changed, err = WrapChangelog(tmpfile.Name(), 20, true)
+	if err != nil || changed {
+		t.Errorf("Expected wrapped file to pass the check, got changed=%v err=%v", changed, err)
+	}
+}
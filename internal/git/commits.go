@@ -0,0 +1,69 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit is a commit listed for changelog generation
+type Commit struct {
+	Hash    string
+	Subject string
+	Merge   bool // The commit has more than one parent
+}
+
+// GetLastTag returns the most recent tag reachable from HEAD, or an empty
+// string when there are no tags
+func GetLastTag() (string, error) {
+	cmd := ExecCommand("git", "describe", "--tags", "--abbrev=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "No names found") {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting latest tag: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RefExists reports whether ref names a commit, such as a tag, branch or hash
+func RefExists(ref string) bool {
+	cmd := ExecCommand("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	_, err := cmd.CombinedOutput()
+	return err == nil
+}
+
+// GetCommitsSince returns the commits after ref up to HEAD, newest first.
+// With an empty ref, all commits are returned.
+func GetCommitsSince(ref string) ([]Commit, error) {
+	return GetCommitsBetween(ref, "HEAD")
+}
+
+// GetCommitsBetween returns the commits reachable from to but not from from,
+// newest first. With an empty from, all commits up to to are returned.
+func GetCommitsBetween(from, to string) ([]Commit, error) {
+	revisionRange := to
+	if from != "" {
+		revisionRange = from + ".." + to
+	}
+
+	cmd := ExecCommand("git", "log", "--format=%H%x1f%P%x1f%s", revisionRange)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing commits in %s: %s: %w", revisionRange, strings.TrimSpace(string(output)), err)
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Subject: fields[2],
+			Merge:   len(strings.Fields(fields[1])) > 1,
+		})
+	}
+	return commits, nil
+}
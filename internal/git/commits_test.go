@@ -0,0 +1,105 @@
+package git
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetLastTag(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected string
+		wantErr  bool
+	}{
+		{"Tag found", "1.2.0\n", nil, "1.2.0", false},
+		{"No tags", "fatal: No names found, cannot describe anything.", fmt.Errorf("exit status 128"), "", false},
+		{"Git error", "fatal: not a git repository", fmt.Errorf("exit status 128"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecCommand = func(command string, args ...string) Commander {
+				return &mockCmd{output: []byte(tt.output), err: tt.err}
+			}
+
+			tag, err := GetLastTag()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetLastTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tag != tt.expected {
+				t.Errorf("GetLastTag() = %q, want %q", tag, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRefExists(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("0123456789abcdef\n"), err: nil}
+	}
+	if !RefExists("1.0.0") {
+		t.Error("Expected ref to exist")
+	}
+	if executedCommand != "git rev-parse --verify --quiet 1.0.0^{commit}" {
+		t.Errorf("Unexpected command %q", executedCommand)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte(""), err: fmt.Errorf("exit status 1")}
+	}
+	if RefExists("missing") {
+		t.Error("Expected ref not to exist")
+	}
+}
+
+func TestGetCommitsBetween(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("ccc\x1fbbb aaa2\x1fMerge branch 'feature'\nbbb\x1faaa\x1ffeat: add feature\n"), err: nil}
+	}
+
+	commits, err := GetCommitsBetween("1.0.0", "main")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() error = %v", err)
+	}
+	if executedCommand != "git log --format=%H%x1f%P%x1f%s 1.0.0..main" {
+		t.Errorf("Unexpected command %q", executedCommand)
+	}
+	expected := []Commit{
+		{Hash: "ccc", Subject: "Merge branch 'feature'", Merge: true},
+		{Hash: "bbb", Subject: "feat: add feature"},
+	}
+	if !reflect.DeepEqual(commits, expected) {
+		t.Errorf("GetCommitsBetween() = %+v, want %+v", commits, expected)
+	}
+
+	if _, err := GetCommitsSince(""); err != nil {
+		t.Fatalf("GetCommitsSince() error = %v", err)
+	}
+	if executedCommand != "git log --format=%H%x1f%P%x1f%s HEAD" {
+		t.Errorf("Expected all commits up to HEAD, got %q", executedCommand)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("fatal: your current branch 'main' does not have any commits yet"), err: fmt.Errorf("exit status 128")}
+	}
+	if _, err := GetCommitsSince(""); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -1,9 +1,12 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Commander is an interface for command execution
@@ -16,6 +19,13 @@ var ExecCommand = func(command string, args ...string) Commander {
 	return exec.Command(command, args...)
 }
 
+// ExecCommandWithInput is like ExecCommand, with input passed to the command's stdin
+var ExecCommandWithInput = func(input string, command string, args ...string) Commander {
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = strings.NewReader(input)
+	return cmd
+}
+
 // IsInstalled checks if Git is installed
 func IsInstalled() bool {
 	cmd := ExecCommand("git", "--version")
@@ -61,23 +71,90 @@ func GetVersion() (string, error) {
 	return fmt.Sprintf("%s-dev.%s+%s", tag, commitCount, commitHash), nil
 }
 
+// CommitOptions controls how CommitChangelogWithOptions creates the release commit
+type CommitOptions struct {
+	NoVerify    bool     // Skip the pre-commit and commit-msg hooks
+	ExtraFiles  []string // Additional files to stage with the changelog
+	AuthorName  string   // Author and committer name, the git config is used when empty
+	AuthorEmail string   // Author and committer email, the git config is used when empty
+	Sign        bool     // GPG-sign the commit
+	CoAuthors   []string // "Name <email>" of co-authors, added as Co-authored-by trailers
+	AllowEmpty  bool     // Create the commit even if nothing changed
+}
+
+// ErrNothingToCommit is returned when the release commit would be empty
+// because neither the changelog nor the additional files changed
+var ErrNothingToCommit = errors.New("nothing to commit")
+
 // CommitChangelog commits the changelog file
 func CommitChangelog(file, version string) error {
+	return CommitChangelogWithOptions(file, version, CommitOptions{})
+}
+
+// CommitChangelogWithOptions commits the changelog file
+func CommitChangelogWithOptions(file, version string, opts CommitOptions) error {
 	addCmd := ExecCommand("git", "add", file)
 	_, err := addCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error adding changelog to git: %w", err)
 	}
 
-	commitCmd := ExecCommand("git", "commit", "-m", fmt.Sprintf("Update changelog for version %s", version))
-	_, err = commitCmd.CombinedOutput()
+	for _, extraFile := range opts.ExtraFiles {
+		addCmd = ExecCommand("git", "add", extraFile)
+		if _, err := addCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("error adding %s to git: %w", extraFile, err)
+		}
+	}
+
+	var args []string
+	if opts.AuthorName != "" && opts.AuthorEmail != "" {
+		// The committer is set through the config of this command only, so the
+		// commit is attributed without changing the user's git config
+		args = append(args, "-c", "user.name="+opts.AuthorName, "-c", "user.email="+opts.AuthorEmail)
+	}
+	args = append(args, "commit", "-m", fmt.Sprintf("Update changelog for version %s", version))
+	if len(opts.CoAuthors) > 0 {
+		trailers := make([]string, len(opts.CoAuthors))
+		for i, coAuthor := range opts.CoAuthors {
+			trailers[i] = "Co-authored-by: " + coAuthor
+		}
+		args = append(args, "-m", strings.Join(trailers, "\n"))
+	}
+	if opts.AuthorName != "" && opts.AuthorEmail != "" {
+		args = append(args, "--author", fmt.Sprintf("%s <%s>", opts.AuthorName, opts.AuthorEmail))
+	}
+	if opts.Sign {
+		args = append(args, "-S")
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if opts.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	commitCmd := ExecCommand("git", args...)
+	output, err := commitCmd.CombinedOutput()
 	if err != nil {
+		if !opts.AllowEmpty && !hasStagedChanges() {
+			return fmt.Errorf("%w for version %s, %s and the additional files are unchanged", ErrNothingToCommit, version, file)
+		}
+		if opts.Sign {
+			return fmt.Errorf("error creating signed commit, check your GPG signing setup: %s: %w", strings.TrimSpace(string(output)), err)
+		}
 		return fmt.Errorf("error committing changelog: %w", err)
 	}
 
 	return nil
 }
 
+// hasStagedChanges reports whether the index differs from HEAD. git diff
+// --quiet exits with an error when there are differences, and errors such as
+// a missing HEAD also count as changes.
+func hasStagedChanges() bool {
+	_, err := ExecCommand("git", "diff", "--cached", "--quiet").CombinedOutput()
+	return err != nil
+}
+
 // TagVersion creates a new Git tag for the given version
 func TagVersion(version string) error {
 	cmd := ExecCommand("git", "tag", version)
@@ -88,14 +165,92 @@ func TagVersion(version string) error {
 	return nil
 }
 
+// TagVersionWithMessage creates an annotated tag with the given message. The
+// message is passed on stdin, so long release notes don't hit argument limits.
+func TagVersionWithMessage(version, message string) error {
+	cmd := ExecCommandWithInput(message, "git", "tag", "-a", version, "-F", "-")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error creating annotated tag: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 // HasUncommittedChanges checks if there are any uncommitted changes in the repository
 func HasUncommittedChanges() (bool, error) {
-	cmd := ExecCommand("git", "status", "--porcelain")
+	return HasUncommittedChangesExcept(nil)
+}
+
+// HasUncommittedChangesExcept checks if there are any uncommitted changes in the
+// repository, ignoring changes to the given files. The files are relative to
+// the current directory, like the paths given to git add, and their names can
+// be patterns such as CHANGELOG.md.bak-*.
+func HasUncommittedChangesExcept(files []string) (bool, error) {
+	cmd := ExecCommand("git", "status", "--porcelain", "-z")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return false, fmt.Errorf("failed to check git status: %w", err)
 	}
-	return len(output) > 0, nil
+
+	// git status reports paths relative to the repository root
+	root := ""
+	var ignored []string
+	if len(files) > 0 {
+		cmd := ExecCommand("git", "rev-parse", "--show-toplevel")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return false, fmt.Errorf("failed to get the repository root: %w", err)
+		}
+		root = resolvePath(strings.TrimSpace(string(output)))
+		for _, file := range files {
+			ignored = append(ignored, resolvePath(file))
+		}
+	}
+
+	// Entries are "XY path", separated by NUL bytes. Renames and copies are
+	// followed by an extra entry with the original path.
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		if root == "" || !isIgnored(filepath.Join(root, filepath.FromSlash(entry[3:])), ignored) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isIgnored reports whether the path is one of the ignored paths, or matches
+// one of them as a pattern
+func isIgnored(path string, ignored []string) bool {
+	for _, pattern := range ignored {
+		if path == pattern {
+			return true
+		}
+		if matched, err := filepath.Match(pattern, path); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the absolute path of a file with the symbolic links of
+// its directory resolved, so paths of the same file compare equal. The file
+// itself may not exist, e.g. when it was deleted.
+func resolvePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		return filepath.Join(dir, filepath.Base(abs))
+	}
+	return abs
 }
 
 // PushChanges pushes the changes and tags to the remote repository
@@ -107,3 +262,254 @@ func PushChanges() error {
 	}
 	return nil
 }
+
+// PushTag pushes only the given tag to the remote repository
+func PushTag(remote, tag string) error {
+	cmd := ExecCommand("git", "push", remote, "refs/tags/"+tag)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push tag %s: %w\nCommand output: %s", tag, err, string(output))
+	}
+	return nil
+}
+
+// RetryOptions controls how pushes are retried after network failures
+type RetryOptions struct {
+	Retries int                                             // Retries after the first attempt, 0 disables retrying
+	Backoff time.Duration                                   // Delay before the first retry, doubled for every further retry
+	OnRetry func(retry int, delay time.Duration, err error) // Called before each retry
+}
+
+// sleep is a variable so tests don't have to wait for the backoff
+var sleep = time.Sleep
+
+// PushChangesWithRetries pushes like PushChanges, retrying network failures
+func PushChangesWithRetries(opts RetryOptions) error {
+	return withRetries(opts, PushChanges)
+}
+
+// PushTagWithRetries pushes like PushTag, retrying network failures
+func PushTagWithRetries(remote, tag string, opts RetryOptions) error {
+	return withRetries(opts, func() error { return PushTag(remote, tag) })
+}
+
+func withRetries(opts RetryOptions, push func() error) error {
+	err := push()
+	delay := opts.Backoff
+	for retry := 1; err != nil && retry <= opts.Retries && isNetworkError(err); retry++ {
+		if opts.OnRetry != nil {
+			opts.OnRetry(retry, delay, err)
+		}
+		sleep(delay)
+		err = push()
+		delay *= 2
+	}
+	return err
+}
+
+// isNetworkError reports whether a failed push looks like a transient network
+// problem. Rejected credentials are never retried.
+func isNetworkError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, rejected := range []string{"authentication failed", "permission denied", "could not read username", "access denied", "returned error: 401", "returned error: 403"} {
+		if strings.Contains(message, rejected) {
+			return false
+		}
+	}
+	for _, transient := range []string{
+		"could not resolve host", "connection timed out", "operation timed out", "connection reset",
+		"connection refused", "failed to connect", "network is unreachable", "the remote end hung up unexpectedly",
+		"early eof", "returned error: 500", "returned error: 502", "returned error: 503", "returned error: 504",
+	} {
+		if strings.Contains(message, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBranch creates a new branch from HEAD and checks it out
+func CreateBranch(name string) error {
+	cmd := ExecCommand("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	if _, err := cmd.CombinedOutput(); err == nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+
+	cmd = ExecCommand("git", "checkout", "-b", name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error creating branch %s: %w\nCommand output: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// IsDetachedHead checks if HEAD points directly at a commit instead of a branch
+func IsDetachedHead() (bool, error) {
+	cmd := ExecCommand("git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("error getting current branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)) == "HEAD", nil
+}
+
+// IsRepository reports whether the current directory is inside a git working
+// tree. Outside a repository, it returns false without an error.
+func IsRepository() (bool, error) {
+	cmd := ExecCommand("git", "rev-parse", "--is-inside-work-tree")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "not a git repository") {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking for a git repository: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)) == "true", nil
+}
+
+// IsShallow reports whether the repository is a shallow clone, as made by CI
+// checkouts with a limited depth. Tags outside the fetched history are then
+// missing, so the latest tag may not be the latest version.
+func IsShallow() (bool, error) {
+	cmd := ExecCommand("git", "rev-parse", "--is-shallow-repository")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("error checking for a shallow clone: %w", err)
+	}
+	return strings.TrimSpace(string(output)) == "true", nil
+}
+
+// GetHeadCommit returns the full hash of the HEAD commit
+func GetHeadCommit() (string, error) {
+	cmd := ExecCommand("git", "rev-parse", "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting HEAD commit: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListTags returns all tags in the repository
+func ListTags() ([]string, error) {
+	cmd := ExecCommand("git", "tag", "--list")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %w", err)
+	}
+
+	var tags []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if tag := strings.TrimSpace(line); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// TagExists checks if the given tag exists in the local repository
+func TagExists(tag string) (bool, error) {
+	cmd := ExecCommand("git", "tag", "--list", tag)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("error checking tag %s: %w", tag, err)
+	}
+	return strings.TrimSpace(string(output)) == tag, nil
+}
+
+// DeleteTag deletes the local tag and, if remote is not empty, the tag on that remote
+func DeleteTag(tag, remote string) error {
+	cmd := ExecCommand("git", "tag", "-d", tag)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting local tag %s: %w\nCommand output: %s", tag, err, string(output))
+	}
+
+	if remote == "" {
+		return nil
+	}
+
+	cmd = ExecCommand("git", "push", remote, ":refs/tags/"+tag)
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("local tag %s was deleted, but deleting it from %s failed: %w\nCommand output: %s", tag, remote, err, string(output))
+	}
+	return nil
+}
+
+// GetTagDate returns the date of the given tag. Annotated tags use the tagger
+// date, lightweight tags use the author date of the tagged commit.
+func GetTagDate(tag string) (time.Time, error) {
+	cmd := ExecCommand("git", "cat-file", "-t", "refs/tags/"+tag)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("tag %s does not exist", tag)
+	}
+
+	if strings.TrimSpace(string(output)) == "tag" {
+		cmd = ExecCommand("git", "for-each-ref", "--format=%(taggerdate:iso-strict)", "refs/tags/"+tag)
+	} else {
+		cmd = ExecCommand("git", "log", "-1", "--format=%aI", "refs/tags/"+tag)
+	}
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting date of tag %s: %w", tag, err)
+	}
+
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing date of tag %s: %w", tag, err)
+	}
+	return date, nil
+}
+
+// IsTracked reports whether the file is tracked by git. Outside a repository,
+// no file is tracked.
+func IsTracked(file string) bool {
+	cmd := ExecCommand("git", "ls-files", "--error-unmatch", file)
+	_, err := cmd.CombinedOutput()
+	return err == nil
+}
+
+// MoveFile renames a tracked file with git mv, so the rename is staged
+func MoveFile(from, to string) error {
+	cmd := ExecCommand("git", "mv", from, to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error moving %s to %s: %s: %w", from, to, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ErrNotSigned is returned when a tag or commit has no signature to verify
+var ErrNotSigned = errors.New("no signature")
+
+// VerifyTag checks the GPG or SSH signature of the tag with git verify-tag,
+// and reports whether the signature is good together with the output of the
+// check. A tag without a signature, such as a lightweight tag, returns
+// ErrNotSigned.
+func VerifyTag(tag string) (bool, string, error) {
+	return verifySignature("tag "+tag, "verify-tag", tag)
+}
+
+// VerifyCommit checks the signature of the commit that ref points to, e.g.
+// the release commit of a tag. A commit without a signature returns ErrNotSigned.
+func VerifyCommit(ref string) (bool, string, error) {
+	return verifySignature("commit "+ref, "verify-commit", ref+"^{commit}")
+}
+
+// verifySignature runs a git verify command. Unsigned objects fail without
+// output, or with "no signature found" for annotated tags and "cannot verify
+// a non-tag object" for lightweight tags. Any other failure is a signature
+// that doesn't verify, e.g. a bad signature or an unknown key.
+func verifySignature(what, command, ref string) (bool, string, error) {
+	cmd := ExecCommand("git", command, ref)
+	output, err := cmd.CombinedOutput()
+	message := strings.TrimSpace(string(output))
+	switch {
+	case err == nil:
+		return true, message, nil
+	case message == "", strings.Contains(message, "no signature found"), strings.Contains(message, "cannot verify a non-"):
+		return false, message, fmt.Errorf("%w on %s", ErrNotSigned, what)
+	}
+	return false, message, nil
+}
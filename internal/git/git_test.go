@@ -1,9 +1,14 @@
 package git
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 type mockCmd struct {
@@ -138,6 +143,135 @@ func TestCommitChangelog(t *testing.T) {
 	}
 }
 
+func TestCommitChangelogWithOptions(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var commands []string
+	ExecCommand = func(command string, args ...string) Commander {
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	err := CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{NoVerify: true})
+	if err != nil {
+		t.Errorf("CommitChangelogWithOptions failed: %v", err)
+	}
+
+	expected := "git commit -m Update changelog for version 1.0.0 --no-verify"
+	if commands[len(commands)-1] != expected {
+		t.Errorf("Expected commit command %q, got %q", expected, commands[len(commands)-1])
+	}
+
+	commands = nil
+	err = CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{ExtraFiles: []string{"VERSION", "docs/version.txt"}})
+	if err != nil {
+		t.Errorf("CommitChangelogWithOptions failed: %v", err)
+	}
+
+	expectedCommands := []string{
+		"git add CHANGELOG.md",
+		"git add VERSION",
+		"git add docs/version.txt",
+		"git commit -m Update changelog for version 1.0.0",
+	}
+	if strings.Join(commands, ";") != strings.Join(expectedCommands, ";") {
+		t.Errorf("Expected commands %v, got %v", expectedCommands, commands)
+	}
+
+	var commitArgs []string
+	ExecCommand = func(command string, args ...string) Commander {
+		commitArgs = args
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	err = CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{AuthorName: "Release Bot", AuthorEmail: "bot@example.com"})
+	if err != nil {
+		t.Errorf("CommitChangelogWithOptions failed: %v", err)
+	}
+
+	expectedArgs := []string{
+		"-c", "user.name=Release Bot", "-c", "user.email=bot@example.com",
+		"commit", "-m", "Update changelog for version 1.0.0", "--author", "Release Bot <bot@example.com>",
+	}
+	if strings.Join(commitArgs, "|") != strings.Join(expectedArgs, "|") {
+		t.Errorf("Expected commit arguments %q, got %q", expectedArgs, commitArgs)
+	}
+
+	err = CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{AuthorName: "Release Bot", AuthorEmail: "bot@example.com", Sign: true, NoVerify: true})
+	if err != nil {
+		t.Errorf("CommitChangelogWithOptions failed: %v", err)
+	}
+
+	expectedArgs = []string{
+		"-c", "user.name=Release Bot", "-c", "user.email=bot@example.com",
+		"commit", "-m", "Update changelog for version 1.0.0", "--author", "Release Bot <bot@example.com>", "-S", "--no-verify",
+	}
+	if strings.Join(commitArgs, "|") != strings.Join(expectedArgs, "|") {
+		t.Errorf("Expected commit arguments %q, got %q", expectedArgs, commitArgs)
+	}
+
+	err = CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{CoAuthors: []string{"Jane Doe <jane@example.com>", "John Roe <john@example.com>"}, NoVerify: true})
+	if err != nil {
+		t.Errorf("CommitChangelogWithOptions failed: %v", err)
+	}
+
+	expectedArgs = []string{
+		"commit", "-m", "Update changelog for version 1.0.0",
+		"-m", "Co-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: John Roe <john@example.com>", "--no-verify",
+	}
+	if strings.Join(commitArgs, "|") != strings.Join(expectedArgs, "|") {
+		t.Errorf("Expected commit arguments %q, got %q", expectedArgs, commitArgs)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		switch args[0] {
+		case "commit":
+			return &mockCmd{output: []byte("error: gpg failed to sign the data\n"), err: fmt.Errorf("exit status 128")}
+		case "diff":
+			return &mockCmd{output: []byte(""), err: fmt.Errorf("exit status 1")}
+		}
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	err = CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{Sign: true})
+	if err == nil || !strings.Contains(err.Error(), "check your GPG signing setup: error: gpg failed to sign the data") {
+		t.Errorf("Expected signing error, got: %v", err)
+	}
+}
+
+func TestCommitChangelogNothingToCommit(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var commitArgs []string
+	ExecCommand = func(command string, args ...string) Commander {
+		if args[0] == "commit" {
+			commitArgs = args
+			if args[len(args)-1] != "--allow-empty" {
+				return &mockCmd{output: []byte("nothing to commit, working tree clean\n"), err: fmt.Errorf("exit status 1")}
+			}
+		}
+		// git diff --cached --quiet succeeds, so nothing is staged
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	err := CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{})
+	if !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("Expected ErrNothingToCommit, got: %v", err)
+	}
+	if err == nil || err.Error() != "nothing to commit for version 1.0.0, CHANGELOG.md and the additional files are unchanged" {
+		t.Errorf("Expected a specific error, got: %v", err)
+	}
+
+	if err := CommitChangelogWithOptions("CHANGELOG.md", "1.0.0", CommitOptions{AllowEmpty: true}); err != nil {
+		t.Errorf("Expected an empty commit, got: %v", err)
+	}
+	if commitArgs[len(commitArgs)-1] != "--allow-empty" {
+		t.Errorf("Expected --allow-empty, got: %v", commitArgs)
+	}
+}
+
 func TestTagVersion(t *testing.T) {
 	oldExecCommand := ExecCommand
 	defer func() { ExecCommand = oldExecCommand }()
@@ -161,6 +295,37 @@ func TestTagVersion(t *testing.T) {
 	}
 }
 
+func TestTagVersionWithMessage(t *testing.T) {
+	oldExecCommandWithInput := ExecCommandWithInput
+	defer func() { ExecCommandWithInput = oldExecCommandWithInput }()
+
+	var executedCommand, executedInput string
+	ExecCommandWithInput = func(input string, command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		executedInput = input
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	message := "1.1.0\n\n### Added\n\n- Feature A\n"
+	if err := TagVersionWithMessage("1.1.0", message); err != nil {
+		t.Errorf("TagVersionWithMessage failed: %v", err)
+	}
+	if executedCommand != "git tag -a 1.1.0 -F -" {
+		t.Errorf("Expected git tag -a 1.1.0 -F -, got %q", executedCommand)
+	}
+	if executedInput != message {
+		t.Errorf("Expected message %q on stdin, got %q", message, executedInput)
+	}
+
+	ExecCommandWithInput = func(input string, command string, args ...string) Commander {
+		return &mockCmd{output: []byte("fatal: tag '1.1.0' already exists"), err: fmt.Errorf("exit status 128")}
+	}
+	err := TagVersionWithMessage("1.1.0", message)
+	if err == nil || !strings.Contains(err.Error(), "tag '1.1.0' already exists") {
+		t.Errorf("Expected tag error with git output, got: %v", err)
+	}
+}
+
 func TestHasUncommittedChanges(t *testing.T) {
 	oldExecCommand := ExecCommand
 	defer func() { ExecCommand = oldExecCommand }()
@@ -190,6 +355,77 @@ func TestHasUncommittedChanges(t *testing.T) {
 	}
 }
 
+func TestHasUncommittedChangesExcept(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ExecCommand = func(command string, args ...string) Commander {
+		if args[0] == "rev-parse" {
+			return &mockCmd{output: []byte(wd + "\n"), err: nil}
+		}
+		return &mockCmd{output: []byte(" M VERSION\x00?? docs/version.txt\x00R  new name.txt\x00old name.txt\x00?? CHANGELOG.md.bak-20240101-120000.000\x00"), err: nil}
+	}
+
+	hasChanges, err := HasUncommittedChangesExcept([]string{"VERSION", "./docs/version.txt", "new name.txt", "CHANGELOG.md.bak-*"})
+	if err != nil {
+		t.Errorf("HasUncommittedChangesExcept failed: %v", err)
+	}
+	if hasChanges {
+		t.Error("Expected changes to the ignored files not to count")
+	}
+
+	hasChanges, err = HasUncommittedChangesExcept([]string{"VERSION", "docs/version.txt", "new name.txt"})
+	if err != nil {
+		t.Errorf("HasUncommittedChangesExcept failed: %v", err)
+	}
+	if !hasChanges {
+		t.Error("Expected changes to other files to count")
+	}
+}
+
+// TestHasUncommittedChangesExceptInSubdirectory ignores files given relative
+// to a subdirectory of the repository, as --add paths are when changie runs there
+func TestHasUncommittedChangesExceptInSubdirectory(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git is not installed")
+	}
+	chdirTempDir(t)
+
+	runGit(t, "init")
+	runGit(t, "config", "user.name", "Test")
+	runGit(t, "config", "user.email", "test@example.com")
+	runGit(t, "config", "commit.gpgsign", "false")
+	if err := os.Mkdir("sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"VERSION", filepath.Join("sub", "version.txt"), filepath.Join("sub", "n\u00e4me.txt")} {
+		if err := os.WriteFile(file, []byte("1.0.0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-m", "Initial commit")
+	for _, file := range []string{"VERSION", filepath.Join("sub", "version.txt"), filepath.Join("sub", "n\u00e4me.txt")} {
+		if err := os.WriteFile(file, []byte("1.1.0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chdir("sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := HasUncommittedChangesExcept([]string{"../VERSION", "./version.txt", "../sub/n\u00e4me.txt"}); err != nil || changed {
+		t.Errorf("HasUncommittedChangesExcept() = %v, %v, want false, nil", changed, err)
+	}
+	if changed, err := HasUncommittedChangesExcept([]string{"../VERSION", "version.txt"}); err != nil || !changed {
+		t.Errorf("HasUncommittedChangesExcept() without the quoted name = %v, %v, want true, nil", changed, err)
+	}
+}
+
 func TestPushChanges(t *testing.T) {
 	oldExecCommand := ExecCommand
 	defer func() { ExecCommand = oldExecCommand }()
@@ -212,3 +448,696 @@ func TestPushChanges(t *testing.T) {
 		t.Error("PushChanges should have failed, but didn't")
 	}
 }
+
+func TestPushTag(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var cmdString string
+	ExecCommand = func(command string, args ...string) Commander {
+		cmdString = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	if err := PushTag("origin", "1.2.0"); err != nil {
+		t.Errorf("PushTag failed: %v", err)
+	}
+	if cmdString != "git push origin refs/tags/1.2.0" {
+		t.Errorf("Unexpected command: %s", cmdString)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte(""), err: fmt.Errorf("git error")}
+	}
+
+	if err := PushTag("origin", "1.2.0"); err == nil {
+		t.Error("PushTag should have failed, but didn't")
+	}
+}
+
+func TestPushChangesWithRetries(t *testing.T) {
+	oldExecCommand := ExecCommand
+	oldSleep := sleep
+	defer func() {
+		ExecCommand = oldExecCommand
+		sleep = oldSleep
+	}()
+
+	var delays []time.Duration
+	sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	tests := []struct {
+		name     string
+		outputs  []string // Output of each push attempt, empty for success
+		retries  int
+		attempts int
+		wantErr  bool
+	}{
+		{"Success", []string{""}, 3, 1, false},
+		{"Recovers after network errors", []string{"fatal: unable to access 'https://github.com/peiman/changie.git/': Could not resolve host: github.com", "fatal: the remote end hung up unexpectedly", ""}, 3, 3, false},
+		{"Gives up after retries", []string{"ssh: connect to host github.com port 22: Connection timed out", "ssh: connect to host github.com port 22: Connection timed out", "ssh: connect to host github.com port 22: Connection timed out"}, 2, 3, true},
+		{"No retries by default", []string{"fatal: Could not resolve host: github.com"}, 0, 1, true},
+		{"Authentication is not retried", []string{"remote: Permission denied to bot.\nfatal: unable to access: The requested URL returned error: 403"}, 3, 1, true},
+		{"Rejected push is not retried", []string{"! [rejected] main -> main (fetch first)"}, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays = nil
+			attempts := 0
+			ExecCommand = func(command string, args ...string) Commander {
+				output := tt.outputs[attempts]
+				attempts++
+				if output == "" {
+					return &mockCmd{output: []byte(""), err: nil}
+				}
+				return &mockCmd{output: []byte(output), err: fmt.Errorf("exit status 128")}
+			}
+
+			var reported []int
+			err := PushChangesWithRetries(RetryOptions{
+				Retries: tt.retries,
+				Backoff: time.Second,
+				OnRetry: func(retry int, delay time.Duration, err error) { reported = append(reported, retry) },
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PushChangesWithRetries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, attempts)
+			}
+			if len(reported) != tt.attempts-1 {
+				t.Errorf("Expected %d reported retries, got %v", tt.attempts-1, reported)
+			}
+			for i, delay := range delays {
+				if expected := time.Second << i; delay != expected {
+					t.Errorf("Expected delay %s before retry %d, got %s", expected, i+1, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestPushTagWithRetries(t *testing.T) {
+	oldExecCommand := ExecCommand
+	oldSleep := sleep
+	defer func() {
+		ExecCommand = oldExecCommand
+		sleep = oldSleep
+	}()
+	sleep = func(time.Duration) {}
+
+	var commands []string
+	ExecCommand = func(command string, args ...string) Commander {
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		if len(commands) == 1 {
+			return &mockCmd{output: []byte("fatal: Connection reset by peer"), err: fmt.Errorf("exit status 128")}
+		}
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	if err := PushTagWithRetries("origin", "1.2.0", RetryOptions{Retries: 1}); err != nil {
+		t.Errorf("PushTagWithRetries failed: %v", err)
+	}
+	if len(commands) != 2 || commands[1] != "git push origin refs/tags/1.2.0" {
+		t.Errorf("Expected the tag push to be retried, got %v", commands)
+	}
+}
+
+func TestCreateBranch(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var commands []string
+	ExecCommand = func(command string, args ...string) Commander {
+		cmdString := command + " " + strings.Join(args, " ")
+		commands = append(commands, cmdString)
+		if strings.HasPrefix(cmdString, "git rev-parse") {
+			return &mockCmd{output: []byte(""), err: fmt.Errorf("exit status 1")}
+		}
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	if err := CreateBranch("release/1.2.0"); err != nil {
+		t.Errorf("CreateBranch failed: %v", err)
+	}
+	if commands[len(commands)-1] != "git checkout -b release/1.2.0" {
+		t.Errorf("Expected branch to be created with checkout -b, got: %v", commands)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("abc1234"), err: nil}
+	}
+
+	err := CreateBranch("release/1.2.0")
+	if err == nil || !strings.Contains(err.Error(), "branch release/1.2.0 already exists") {
+		t.Errorf("Expected already exists error, got: %v", err)
+	}
+}
+
+func TestIsDetachedHead(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected bool
+		wantErr  bool
+	}{
+		{"On a branch", "main\n", nil, false, false},
+		{"Detached HEAD", "HEAD\n", nil, true, false},
+		{"Git error", "fatal: not a git repository", fmt.Errorf("exit status 128"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecCommand = func(command string, args ...string) Commander {
+				return &mockCmd{output: []byte(tt.output), err: tt.err}
+			}
+
+			detached, err := IsDetachedHead()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsDetachedHead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if detached != tt.expected {
+				t.Errorf("IsDetachedHead() = %v, want %v", detached, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsRepository(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected bool
+		wantErr  bool
+	}{
+		{"Work tree", "true\n", nil, true, false},
+		{"Inside .git", "false\n", nil, false, false},
+		{"No repository", "fatal: not a git repository (or any of the parent directories): .git", fmt.Errorf("exit status 128"), false, false},
+		{"Git error", "fatal: detected dubious ownership in repository", fmt.Errorf("exit status 128"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecCommand = func(command string, args ...string) Commander {
+				return &mockCmd{output: []byte(tt.output), err: tt.err}
+			}
+
+			inRepository, err := IsRepository()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if inRepository != tt.expected {
+				t.Errorf("IsRepository() = %v, want %v", inRepository, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsRepositoryInTempDir(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// Don't look for a repository above the temp dir
+	oldCeiling, hadCeiling := os.LookupEnv("GIT_CEILING_DIRECTORIES")
+	defer func() {
+		if hadCeiling {
+			os.Setenv("GIT_CEILING_DIRECTORIES", oldCeiling)
+		} else {
+			os.Unsetenv("GIT_CEILING_DIRECTORIES")
+		}
+	}()
+	os.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	if inRepository, err := IsRepository(); err != nil || inRepository {
+		t.Errorf("IsRepository() outside a repository = %v, %v, want false, nil", inRepository, err)
+	}
+
+	if output, err := exec.Command("git", "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, output)
+	}
+	if inRepository, err := IsRepository(); err != nil || !inRepository {
+		t.Errorf("IsRepository() in a new repository = %v, %v, want true, nil", inRepository, err)
+	}
+}
+
+// TestReleaseInLinkedWorktree releases from a worktree added with git worktree
+// add, where .git is a file pointing into the main repository
+func TestReleaseInLinkedWorktree(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git is not installed")
+	}
+	dir := chdirTempDir(t)
+
+	runGit(t, "init", "main")
+	if err := os.Chdir(filepath.Join(dir, "main")); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "config", "user.name", "Test")
+	runGit(t, "config", "user.email", "test@example.com")
+	runGit(t, "config", "commit.gpgsign", "false")
+	runGit(t, "config", "tag.gpgsign", "false")
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "add", "CHANGELOG.md")
+	runGit(t, "commit", "-m", "Initial commit")
+	runGit(t, "tag", "1.0.0")
+	runGit(t, "worktree", "add", "-b", "feature", filepath.Join(dir, "linked"))
+	if err := os.Chdir(filepath.Join(dir, "linked")); err != nil {
+		t.Fatal(err)
+	}
+
+	if inRepository, err := IsRepository(); err != nil || !inRepository {
+		t.Errorf("IsRepository() = %v, %v, want true, nil", inRepository, err)
+	}
+	if detached, err := IsDetachedHead(); err != nil || detached {
+		t.Errorf("IsDetachedHead() = %v, %v, want false, nil", detached, err)
+	}
+	if version, err := GetVersion(); err != nil || version != "1.0.0" {
+		t.Errorf("GetVersion() = %q, %v, want 1.0.0", version, err)
+	}
+	if changed, err := HasUncommittedChanges(); err != nil || changed {
+		t.Errorf("HasUncommittedChanges() = %v, %v, want false, nil", changed, err)
+	}
+
+	if err := os.WriteFile("CHANGELOG.md", []byte("# Changelog\n\n## [1.1.0] - 2024-01-01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := HasUncommittedChanges(); err != nil || !changed {
+		t.Errorf("HasUncommittedChanges() after editing the changelog = %v, %v, want true, nil", changed, err)
+	}
+	if err := CommitChangelog("CHANGELOG.md", "1.1.0"); err != nil {
+		t.Fatalf("CommitChangelog() error = %v", err)
+	}
+	if err := TagVersion("1.1.0"); err != nil {
+		t.Fatalf("TagVersion() error = %v", err)
+	}
+	if version, err := GetVersion(); err != nil || version != "1.1.0" {
+		t.Errorf("GetVersion() after the release = %q, %v, want 1.1.0", version, err)
+	}
+
+	// The release commit is on the branch of the worktree, the main working
+	// tree is unchanged, and the tag is shared by both
+	head, err := GetHeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := runGit(t, "rev-parse", "feature"); output != head {
+		t.Errorf("feature = %s, want the release commit %s", output, head)
+	}
+	if output := runGit(t, "rev-parse", "1.1.0^{commit}"); output != head {
+		t.Errorf("Tag 1.1.0 points at %s, want the HEAD of the worktree %s", output, head)
+	}
+	if err := os.Chdir(filepath.Join(dir, "main")); err != nil {
+		t.Fatal(err)
+	}
+	if output := runGit(t, "rev-parse", "HEAD"); output == head {
+		t.Error("Expected the release commit not to be on the branch of the main working tree")
+	}
+	if changed, err := HasUncommittedChanges(); err != nil || changed {
+		t.Errorf("HasUncommittedChanges() in the main working tree = %v, %v, want false, nil", changed, err)
+	}
+	if exists, err := TagExists("1.1.0"); err != nil || !exists {
+		t.Errorf("TagExists(1.1.0) in the main working tree = %v, %v, want true, nil", exists, err)
+	}
+}
+
+// chdirTempDir changes to a new temp dir for the rest of the test and returns
+// it. Git doesn't look for a repository above the temp dir.
+func chdirTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	oldCeiling, hadCeiling := os.LookupEnv("GIT_CEILING_DIRECTORIES")
+	os.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	t.Cleanup(func() {
+		if hadCeiling {
+			os.Setenv("GIT_CEILING_DIRECTORIES", oldCeiling)
+		} else {
+			os.Unsetenv("GIT_CEILING_DIRECTORIES")
+		}
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return dir
+}
+
+// runGit runs git in the current directory and returns its trimmed output
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestIsShallow(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected bool
+		wantErr  bool
+	}{
+		{"Full clone", "false\n", nil, false, false},
+		{"Shallow clone", "true\n", nil, true, false},
+		{"Git error", "fatal: not a git repository", fmt.Errorf("exit status 128"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecCommand = func(command string, args ...string) Commander {
+				return &mockCmd{output: []byte(tt.output), err: tt.err}
+			}
+
+			shallow, err := IsShallow()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsShallow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if shallow != tt.expected {
+				t.Errorf("IsShallow() = %v, want %v", shallow, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetHeadCommit(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("970cce5a1b2c3d4e5f60718293a4b5c6d7e8f901\n"), err: nil}
+	}
+
+	commit, err := GetHeadCommit()
+	if err != nil {
+		t.Fatalf("GetHeadCommit() error = %v", err)
+	}
+	if commit != "970cce5a1b2c3d4e5f60718293a4b5c6d7e8f901" {
+		t.Errorf("GetHeadCommit() = %q", commit)
+	}
+	if executedCommand != "git rev-parse HEAD" {
+		t.Errorf("Expected git rev-parse HEAD, got %q", executedCommand)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("fatal: ambiguous argument 'HEAD'"), err: fmt.Errorf("exit status 128")}
+	}
+	if _, err := GetHeadCommit(); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestListTags(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("0.10.0\n0.9.0\nv1.0.0\n\n"), err: nil}
+	}
+
+	tags, err := ListTags()
+	if err != nil {
+		t.Errorf("ListTags failed: %v", err)
+	}
+	expected := []string{"0.10.0", "0.9.0", "v1.0.0"}
+	if strings.Join(tags, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte(""), err: fmt.Errorf("git error")}
+	}
+
+	if _, err := ListTags(); err == nil {
+		t.Error("ListTags should have failed, but didn't")
+	}
+}
+
+func TestTagExists(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("1.0.0\n"), err: nil}
+	}
+
+	exists, err := TagExists("1.0.0")
+	if err != nil || !exists {
+		t.Errorf("Expected tag to exist, got exists=%v err=%v", exists, err)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	exists, err = TagExists("2.0.0")
+	if err != nil || exists {
+		t.Errorf("Expected tag not to exist, got exists=%v err=%v", exists, err)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var commands []string
+	ExecCommand = func(command string, args ...string) Commander {
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	if err := DeleteTag("1.0.0", ""); err != nil {
+		t.Errorf("DeleteTag failed: %v", err)
+	}
+	if strings.Join(commands, ";") != "git tag -d 1.0.0" {
+		t.Errorf("Unexpected commands for local delete: %v", commands)
+	}
+
+	commands = nil
+	if err := DeleteTag("1.0.0", "origin"); err != nil {
+		t.Errorf("DeleteTag failed: %v", err)
+	}
+	if strings.Join(commands, ";") != "git tag -d 1.0.0;git push origin :refs/tags/1.0.0" {
+		t.Errorf("Unexpected commands for remote delete: %v", commands)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		if args[0] == "push" {
+			return &mockCmd{output: []byte("rejected"), err: fmt.Errorf("exit status 1")}
+		}
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+
+	err := DeleteTag("1.0.0", "origin")
+	if err == nil || !strings.Contains(err.Error(), "local tag 1.0.0 was deleted, but deleting it from origin failed") {
+		t.Errorf("Expected remote delete error, got: %v", err)
+	}
+}
+
+func TestGetTagDate(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name        string
+		mockOutputs map[string][]byte
+		mockErrors  map[string]error
+		expected    time.Time
+		expectError string
+	}{
+		{
+			name: "Annotated tag",
+			mockOutputs: map[string][]byte{
+				"git cat-file -t refs/tags/1.0.0":                                    []byte("tag\n"),
+				"git for-each-ref --format=%(taggerdate:iso-strict) refs/tags/1.0.0": []byte("2024-06-28T10:00:00+02:00\n"),
+			},
+			expected: time.Date(2024, 6, 28, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Lightweight tag",
+			mockOutputs: map[string][]byte{
+				"git cat-file -t refs/tags/1.0.0":         []byte("commit\n"),
+				"git log -1 --format=%aI refs/tags/1.0.0": []byte("2024-06-27T12:30:00Z\n"),
+			},
+			expected: time.Date(2024, 6, 27, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "Missing tag",
+			mockErrors: map[string]error{
+				"git cat-file -t refs/tags/1.0.0": fmt.Errorf("exit status 128"),
+			},
+			expectError: "tag 1.0.0 does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecCommand = func(command string, args ...string) Commander {
+				cmdString := command + " " + strings.Join(args, " ")
+				return &mockCmd{
+					output: tt.mockOutputs[cmdString],
+					err:    tt.mockErrors[cmdString],
+				}
+			}
+
+			date, err := GetTagDate("1.0.0")
+			if tt.expectError != "" {
+				if err == nil || err.Error() != tt.expectError {
+					t.Errorf("Expected error %q, got: %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !date.Equal(tt.expected) {
+				t.Errorf("Expected date %v, got %v", tt.expected, date)
+			}
+		})
+	}
+}
+
+func TestIsTracked(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("CHANGELOG.md\n"), err: nil}
+	}
+	if !IsTracked("CHANGELOG.md") {
+		t.Error("Expected CHANGELOG.md to be tracked")
+	}
+	if executedCommand != "git ls-files --error-unmatch CHANGELOG.md" {
+		t.Errorf("Expected git ls-files --error-unmatch CHANGELOG.md, got %q", executedCommand)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("error: pathspec 'CHANGELOG.md' did not match any file(s) known to git"), err: fmt.Errorf("exit status 1")}
+	}
+	if IsTracked("CHANGELOG.md") {
+		t.Error("Expected CHANGELOG.md not to be tracked")
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte(""), err: nil}
+	}
+	if err := MoveFile("CHANGELOG.md", "HISTORY.md"); err != nil {
+		t.Errorf("MoveFile() error = %v", err)
+	}
+	if executedCommand != "git mv CHANGELOG.md HISTORY.md" {
+		t.Errorf("Expected git mv CHANGELOG.md HISTORY.md, got %q", executedCommand)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("fatal: destination exists"), err: fmt.Errorf("exit status 128")}
+	}
+	if err := MoveFile("CHANGELOG.md", "HISTORY.md"); err == nil || !strings.Contains(err.Error(), "destination exists") {
+		t.Errorf("Expected error with the git output, got %v", err)
+	}
+}
+
+func TestVerifyTag(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	tests := []struct {
+		name          string
+		output        string
+		err           error
+		expectedValid bool
+		expectedMsg   string
+		notSigned     bool
+	}{
+		{"Good signature", "Good \"git\" signature for dev@example.com with ED25519 key SHA256:abc\n", nil, true, "Good \"git\" signature for dev@example.com with ED25519 key SHA256:abc", false},
+		{"Bad signature", "gpg: BAD signature from \"Dev <dev@example.com>\"\n", fmt.Errorf("exit status 1"), false, "gpg: BAD signature from \"Dev <dev@example.com>\"", false},
+		{"Unknown key", "Good \"git\" signature with ED25519 key SHA256:abc\nNo principal matched.\n", fmt.Errorf("exit status 1"), false, "Good \"git\" signature with ED25519 key SHA256:abc\nNo principal matched.", false},
+		{"Unsigned annotated tag", "error: no signature found\n", fmt.Errorf("exit status 1"), false, "error: no signature found", true},
+		{"Lightweight tag", "error: 1.0.0: cannot verify a non-tag object of type commit.\n", fmt.Errorf("exit status 1"), false, "error: 1.0.0: cannot verify a non-tag object of type commit.", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executedCommand string
+			ExecCommand = func(command string, args ...string) Commander {
+				executedCommand = command + " " + strings.Join(args, " ")
+				return &mockCmd{output: []byte(tt.output), err: tt.err}
+			}
+
+			valid, message, err := VerifyTag("1.0.0")
+			if executedCommand != "git verify-tag 1.0.0" {
+				t.Errorf("Expected git verify-tag 1.0.0, got %s", executedCommand)
+			}
+			if errors.Is(err, ErrNotSigned) != tt.notSigned || (err != nil && !tt.notSigned) {
+				t.Errorf("VerifyTag() error = %v, want not signed %v", err, tt.notSigned)
+			}
+			if valid != tt.expectedValid || message != tt.expectedMsg {
+				t.Errorf("VerifyTag() = %v, %q, want %v, %q", valid, message, tt.expectedValid, tt.expectedMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyCommit(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executedCommand string
+	ExecCommand = func(command string, args ...string) Commander {
+		executedCommand = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: nil, err: fmt.Errorf("exit status 1")}
+	}
+
+	valid, _, err := VerifyCommit("1.0.0")
+	if executedCommand != "git verify-commit 1.0.0^{commit}" {
+		t.Errorf("Expected git verify-commit 1.0.0^{commit}, got %s", executedCommand)
+	}
+	if valid || !errors.Is(err, ErrNotSigned) {
+		t.Errorf("VerifyCommit() of an unsigned commit = %v, %v, want false, ErrNotSigned", valid, err)
+	}
+}
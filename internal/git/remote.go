@@ -0,0 +1,164 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Repository describes a remote repository parsed from its URL
+type Repository struct {
+	Provider string // "github", "bitbucket", "gitlab", "azure", or empty when unknown
+	Host     string // Web host, e.g. github.com or dev.azure.com
+	Owner    string // Owner or group path, or the organization on Azure DevOps
+	Project  string // Project on Azure DevOps, empty for other providers
+	Name     string // Repository name without the .git suffix
+}
+
+// GetRemoteURL returns the URL of the given remote
+func GetRemoteURL(remote string) (string, error) {
+	cmd := ExecCommand("git", "remote", "get-url", remote)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DefaultBranch returns the default branch of the remote, as recorded in
+// refs/remotes/<remote>/HEAD by git clone or git remote set-head --auto
+func DefaultBranch(remote string) (string, error) {
+	cmd := ExecCommand("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting default branch of remote %s: %w", remote, err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), remote+"/"), nil
+}
+
+// ParseRepositoryURL parses an HTTPS, ssh:// or scp-like SSH remote URL,
+// including the Azure DevOps forms, into its host, owner and repository name
+func ParseRepositoryURL(url string) (*Repository, error) {
+	url = strings.TrimSpace(url)
+	var host, path string
+
+	switch {
+	case strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://"):
+		rest := url[strings.Index(url, "://")+3:]
+		slash := strings.Index(rest, "/")
+		if slash == -1 {
+			return nil, fmt.Errorf("invalid repository URL: %s", url)
+		}
+		host, path = rest[:slash], rest[slash+1:]
+		if at := strings.LastIndex(host, "@"); at != -1 {
+			host = host[at+1:]
+		}
+	case strings.HasPrefix(url, "ssh://"):
+		// ssh://[user@]host[:port]/owner/repo.git, the port is only used by SSH
+		rest := strings.TrimPrefix(url, "ssh://")
+		slash := strings.Index(rest, "/")
+		if slash == -1 {
+			return nil, fmt.Errorf("invalid repository URL: %s", url)
+		}
+		host, path = rest[:slash], rest[slash+1:]
+		if at := strings.LastIndex(host, "@"); at != -1 {
+			host = host[at+1:]
+		}
+		if colon := strings.Index(host, ":"); colon != -1 {
+			host = host[:colon]
+		}
+	case !strings.Contains(url, "://") && strings.Contains(url, ":"):
+		// scp-like syntax, e.g. git@github.com:owner/repo.git
+		colon := strings.Index(url, ":")
+		host, path = url[:colon], url[colon+1:]
+		if at := strings.LastIndex(host, "@"); at != -1 {
+			host = host[at+1:]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported repository URL: %s", url)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid repository URL: %s", url)
+		}
+	}
+
+	if isAzureHost(host) {
+		return parseAzurePath(url, parts)
+	}
+
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid repository URL: %s", url)
+	}
+	return &Repository{
+		Provider: providerForHost(host),
+		Host:     host,
+		Owner:    strings.Join(parts[:len(parts)-1], "/"),
+		Name:     parts[len(parts)-1],
+	}, nil
+}
+
+// WebURL returns the URL of the repository's web interface
+func (r *Repository) WebURL() string {
+	if r.Provider == "azure" {
+		return fmt.Sprintf("https://dev.azure.com/%s/%s/_git/%s", r.Owner, r.Project, r.Name)
+	}
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Name)
+}
+
+// SameRepository reports whether two remote URLs point to the same repository.
+// The scheme, user, port, trailing slash, .git suffix and case are ignored,
+// so the HTTPS and SSH URLs of a repository match.
+func SameRepository(url1, url2 string) bool {
+	repo1, err1 := ParseRepositoryURL(url1)
+	repo2, err2 := ParseRepositoryURL(url2)
+	if err1 == nil && err2 == nil {
+		return strings.EqualFold(repo1.WebURL(), repo2.WebURL())
+	}
+	return strings.EqualFold(normalizeURL(url1), normalizeURL(url2))
+}
+
+// normalizeURL strips the scheme, trailing slash and .git suffix of a URL
+// that can't be parsed as a repository URL, such as a local path
+func normalizeURL(url string) string {
+	url = strings.TrimSpace(url)
+	if i := strings.Index(url, "://"); i != -1 {
+		url = url[i+3:]
+	}
+	return strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+}
+
+// parseAzurePath handles the Azure DevOps path forms,
+// org/project/_git/repo over HTTPS and v3/org/project/repo over SSH
+func parseAzurePath(url string, parts []string) (*Repository, error) {
+	repo := &Repository{Provider: "azure", Host: "dev.azure.com"}
+
+	switch {
+	case len(parts) == 4 && parts[0] == "v3":
+		repo.Owner, repo.Project, repo.Name = parts[1], parts[2], parts[3]
+	case len(parts) == 4 && parts[2] == "_git":
+		repo.Owner, repo.Project, repo.Name = parts[0], parts[1], parts[3]
+	default:
+		return nil, fmt.Errorf("invalid Azure DevOps repository URL: %s", url)
+	}
+	return repo, nil
+}
+
+func isAzureHost(host string) bool {
+	return host == "dev.azure.com" || host == "ssh.dev.azure.com"
+}
+
+func providerForHost(host string) string {
+	switch {
+	case host == "github.com":
+		return "github"
+	case host == "bitbucket.org":
+		return "bitbucket"
+	case strings.Contains(host, "gitlab"):
+		return "gitlab"
+	default:
+		return ""
+	}
+}
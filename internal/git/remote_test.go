@@ -0,0 +1,209 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseRepositoryURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected Repository
+		webURL   string
+		wantErr  bool
+	}{
+		{
+			name:     "GitHub HTTPS",
+			url:      "https://github.com/peiman/changie.git",
+			expected: Repository{Provider: "github", Host: "github.com", Owner: "peiman", Name: "changie"},
+			webURL:   "https://github.com/peiman/changie",
+		},
+		{
+			name:     "GitHub SSH",
+			url:      "git@github.com:peiman/changie.git",
+			expected: Repository{Provider: "github", Host: "github.com", Owner: "peiman", Name: "changie"},
+			webURL:   "https://github.com/peiman/changie",
+		},
+		{
+			name:     "Bitbucket HTTPS with user",
+			url:      "https://peiman@bitbucket.org/peiman/changie.git",
+			expected: Repository{Provider: "bitbucket", Host: "bitbucket.org", Owner: "peiman", Name: "changie"},
+			webURL:   "https://bitbucket.org/peiman/changie",
+		},
+		{
+			name:     "GitLab subgroup",
+			url:      "git@gitlab.com:group/subgroup/repo.git",
+			expected: Repository{Provider: "gitlab", Host: "gitlab.com", Owner: "group/subgroup", Name: "repo"},
+			webURL:   "https://gitlab.com/group/subgroup/repo",
+		},
+		{
+			name:     "Unknown host",
+			url:      "https://git.example.com/team/repo",
+			expected: Repository{Provider: "", Host: "git.example.com", Owner: "team", Name: "repo"},
+			webURL:   "https://git.example.com/team/repo",
+		},
+		{
+			name:     "Azure DevOps HTTPS",
+			url:      "https://dev.azure.com/org/project/_git/repo",
+			expected: Repository{Provider: "azure", Host: "dev.azure.com", Owner: "org", Project: "project", Name: "repo"},
+			webURL:   "https://dev.azure.com/org/project/_git/repo",
+		},
+		{
+			name:     "Azure DevOps HTTPS with user",
+			url:      "https://org@dev.azure.com/org/project/_git/repo",
+			expected: Repository{Provider: "azure", Host: "dev.azure.com", Owner: "org", Project: "project", Name: "repo"},
+			webURL:   "https://dev.azure.com/org/project/_git/repo",
+		},
+		{
+			name:     "Azure DevOps SSH",
+			url:      "git@ssh.dev.azure.com:v3/org/project/repo",
+			expected: Repository{Provider: "azure", Host: "dev.azure.com", Owner: "org", Project: "project", Name: "repo"},
+			webURL:   "https://dev.azure.com/org/project/_git/repo",
+		},
+		{
+			name:     "SSH scheme with port",
+			url:      "ssh://git@git.example.com:2222/group/repo.git",
+			expected: Repository{Provider: "", Host: "git.example.com", Owner: "group", Name: "repo"},
+			webURL:   "https://git.example.com/group/repo",
+		},
+		{
+			name:     "SSH scheme without port",
+			url:      "ssh://git@gitlab.example.com/group/subgroup/repo.git",
+			expected: Repository{Provider: "gitlab", Host: "gitlab.example.com", Owner: "group/subgroup", Name: "repo"},
+			webURL:   "https://gitlab.example.com/group/subgroup/repo",
+		},
+		{
+			name:     "SSH scheme without user",
+			url:      "ssh://github.com/peiman/changie.git",
+			expected: Repository{Provider: "github", Host: "github.com", Owner: "peiman", Name: "changie"},
+			webURL:   "https://github.com/peiman/changie",
+		},
+		{
+			name:     "Azure DevOps SSH scheme",
+			url:      "ssh://git@ssh.dev.azure.com/v3/org/project/repo",
+			expected: Repository{Provider: "azure", Host: "dev.azure.com", Owner: "org", Project: "project", Name: "repo"},
+			webURL:   "https://dev.azure.com/org/project/_git/repo",
+		},
+		{
+			name:    "SSH scheme without path",
+			url:     "ssh://git@git.example.com:2222",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Azure DevOps path",
+			url:     "https://dev.azure.com/org/project",
+			wantErr: true,
+		},
+		{
+			name:    "Missing owner",
+			url:     "https://github.com/changie",
+			wantErr: true,
+		},
+		{
+			name:    "Local path",
+			url:     "/srv/git/changie.git",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := ParseRepositoryURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepositoryURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *repo != tt.expected {
+				t.Errorf("ParseRepositoryURL(%q) = %+v, want %+v", tt.url, *repo, tt.expected)
+			}
+			if repo.WebURL() != tt.webURL {
+				t.Errorf("WebURL() = %q, want %q", repo.WebURL(), tt.webURL)
+			}
+		})
+	}
+}
+
+func TestGetRemoteURL(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executed string
+	ExecCommand = func(command string, args ...string) Commander {
+		executed = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("git@github.com:peiman/changie.git\n"), err: nil}
+	}
+
+	url, err := GetRemoteURL("origin")
+	if err != nil {
+		t.Errorf("GetRemoteURL failed: %v", err)
+	}
+	if url != "git@github.com:peiman/changie.git" {
+		t.Errorf("Expected remote URL, got %q", url)
+	}
+	if executed != "git remote get-url origin" {
+		t.Errorf("Unexpected command: %s", executed)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("error: No such remote 'origin'"), err: fmt.Errorf("exit status 2")}
+	}
+
+	if _, err := GetRemoteURL("origin"); err == nil {
+		t.Error("GetRemoteURL should have failed, but didn't")
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var executed string
+	ExecCommand = func(command string, args ...string) Commander {
+		executed = command + " " + strings.Join(args, " ")
+		return &mockCmd{output: []byte("origin/master\n"), err: nil}
+	}
+
+	branch, err := DefaultBranch("origin")
+	if err != nil {
+		t.Errorf("DefaultBranch failed: %v", err)
+	}
+	if branch != "master" {
+		t.Errorf("Expected master, got %q", branch)
+	}
+	if executed != "git symbolic-ref --short refs/remotes/origin/HEAD" {
+		t.Errorf("Unexpected command: %s", executed)
+	}
+
+	ExecCommand = func(command string, args ...string) Commander {
+		return &mockCmd{output: []byte("fatal: ref refs/remotes/origin/HEAD is not a symbolic ref"), err: fmt.Errorf("exit status 128")}
+	}
+
+	if _, err := DefaultBranch("origin"); err == nil {
+		t.Error("DefaultBranch should have failed, but didn't")
+	}
+}
+
+func TestSameRepository(t *testing.T) {
+	tests := []struct {
+		url1, url2 string
+		expected   bool
+	}{
+		{"git@github.com:peiman/changie.git", "https://github.com/peiman/changie", true},
+		{"ssh://git@github.com:22/peiman/changie.git", "https://github.com/Peiman/changie.git/", true},
+		{"https://dev.azure.com/org/project/_git/repo", "git@ssh.dev.azure.com:v3/org/project/repo", true},
+		{"git@github.com:peiman/changie.git", "git@github.com:someone/changie.git", false},
+		{"git@github.com:peiman/changie.git", "git@gitlab.com:peiman/changie.git", false},
+		{"file:///srv/git/changie.git", "/srv/git/changie", true},
+		{"/srv/git/changie.git", "/srv/git/other.git", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameRepository(tt.url1, tt.url2); got != tt.expected {
+			t.Errorf("SameRepository(%q, %q) = %v, want %v", tt.url1, tt.url2, got, tt.expected)
+		}
+	}
+}
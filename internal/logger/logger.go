@@ -17,7 +17,8 @@ const (
 	ERROR
 )
 
-var logger *zap.Logger
+// logger discards everything until Init is called
+var logger = zap.NewNop()
 
 // Init initializes the logger
 func Init(level LogLevel, output io.Writer) {
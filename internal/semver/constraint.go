@@ -0,0 +1,144 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a version range such as ">=2.0.0 <3.0.0". Comparisons
+// separated by spaces must all match, and ranges separated by "||" are
+// alternatives.
+type Constraint struct {
+	ranges [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version [3]int
+}
+
+// ParseConstraint parses a version range. Supported operators are
+// =, >, >=, < and <=; a version without an operator must match exactly.
+func ParseConstraint(constraint string) (*Constraint, error) {
+	c := &Constraint{}
+	for _, part := range strings.Split(constraint, "||") {
+		var comparisons []comparison
+		tokens := strings.Fields(part)
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			// Allow a space between the operator and the version, e.g. ">= 2.0.0"
+			if strings.TrimLeft(token, "<>=") == "" && i+1 < len(tokens) {
+				i++
+				token += tokens[i]
+			}
+
+			op := token[:len(token)-len(strings.TrimLeft(token, "<>="))]
+			switch op {
+			case "":
+				op = "="
+			case "=", ">", ">=", "<", "<=":
+			default:
+				return nil, fmt.Errorf("invalid operator %q in constraint %q", op, constraint)
+			}
+
+			v, err := ParseVersion(strings.TrimLeft(token, "<>="))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+			}
+			comparisons = append(comparisons, comparison{op: op, version: v})
+		}
+		if len(comparisons) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty range", constraint)
+		}
+		c.ranges = append(c.ranges, comparisons)
+	}
+	return c, nil
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c *Constraint) Check(version string) (bool, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return c.check(v), nil
+}
+
+func (c *Constraint) check(v [3]int) bool {
+	for _, r := range c.ranges {
+		matches := true
+		for _, cmp := range r {
+			result := compareParts(v, cmp.version)
+			switch cmp.op {
+			case "=":
+				matches = matches && result == 0
+			case ">":
+				matches = matches && result > 0
+			case ">=":
+				matches = matches && result >= 0
+			case "<":
+				matches = matches && result < 0
+			case "<=":
+				matches = matches && result <= 0
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// BumpToConstraint returns the smallest version greater than the given version
+// that satisfies the constraint.
+func BumpToConstraint(version, constraint string) (string, error) {
+	current, err := ParseVersion(version)
+	if err != nil {
+		return "", err
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	// The smallest match is either the next patch version or a lower bound
+	candidates := [][3]int{{current[0], current[1], current[2] + 1}}
+	for _, r := range c.ranges {
+		for _, cmp := range r {
+			switch cmp.op {
+			case "=", ">=":
+				candidates = append(candidates, cmp.version)
+			case ">":
+				candidates = append(candidates, [3]int{cmp.version[0], cmp.version[1], cmp.version[2] + 1})
+			}
+		}
+	}
+
+	var best *[3]int
+	for i := range candidates {
+		candidate := candidates[i]
+		if compareParts(candidate, current) <= 0 || !c.check(candidate) {
+			continue
+		}
+		if best == nil || compareParts(candidate, *best) < 0 {
+			best = &candidate
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf("no version greater than %s satisfies %q", version, constraint)
+	}
+	return formatVersion(*best), nil
+}
+
+// compareParts returns -1, 0 or 1 when v1 is lower than, equal to or greater than v2.
+func compareParts(v1, v2 [3]int) int {
+	for i := 0; i < 3; i++ {
+		if v1[i] > v2[i] {
+			return 1
+		}
+		if v1[i] < v2[i] {
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=2.0.0 <3.0.0", "2.5.1", true},
+		{">=2.0.0 <3.0.0", "3.0.0", false},
+		{">=2.0.0 <3.0.0", "1.9.9", false},
+		{">= 2.0.0", "2.0.0", true},
+		{">1.2.3", "1.2.3", false},
+		{"<=1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.3", true},
+		{"=1.2.3", "v1.2.3", true},
+		{"<1.0.0 || >=2.0.0", "1.5.0", false},
+		{"<1.0.0 || >=2.0.0", "2.1.0", true},
+	}
+
+	for _, test := range tests {
+		c, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) returned an error: %v", test.constraint, err)
+		}
+		result, err := c.Check(test.version)
+		if err != nil {
+			t.Errorf("Check(%s) returned an error: %v", test.version, err)
+		}
+		if result != test.expected {
+			t.Errorf("%q.Check(%s) = %v, expected %v", test.constraint, test.version, result, test.expected)
+		}
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", "~1.2.3", ">=2.0", "=>1.0.0", ">=1.0.0 ||"} {
+		if _, err := ParseConstraint(constraint); err == nil {
+			t.Errorf("ParseConstraint(%q) should have failed, but didn't", constraint)
+		}
+	}
+}
+
+func TestBumpToConstraint(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		expected   string
+		wantErr    bool
+	}{
+		{"1.4.2", ">=2.0.0 <3.0.0", "2.0.0", false},
+		{"2.1.0", ">=2.0.0 <3.0.0", "2.1.1", false},
+		{"1.4.2", ">1.9.0", "1.9.1", false},
+		{"1.4.2", "1.6.0", "1.6.0", false},
+		{"1.4.2", "<1.0.0 || >=3.0.0", "3.0.0", false},
+		{"2.9.9", "<2.9.10", "", true},
+		{"3.0.0", ">=2.0.0 <3.0.0", "", true},
+		{"1.0.0", "invalid", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := BumpToConstraint(test.version, test.constraint)
+		if (err != nil) != test.wantErr {
+			t.Errorf("BumpToConstraint(%s, %q) error = %v, wantErr %v", test.version, test.constraint, err, test.wantErr)
+		}
+		if result != test.expected {
+			t.Errorf("BumpToConstraint(%s, %q) = %s, expected %s", test.version, test.constraint, result, test.expected)
+		}
+	}
+}
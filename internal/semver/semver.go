@@ -9,7 +9,7 @@ import (
 
 // BumpMajor increases the major version number and resets minor and patch to 0.
 func BumpMajor(version string) (string, error) {
-	v, err := parseVersion(version)
+	v, err := ParseVersion(version)
 	if err != nil {
 		return "", err
 	}
@@ -21,7 +21,7 @@ func BumpMajor(version string) (string, error) {
 
 // BumpMinor increases the minor version number and resets patch to 0.
 func BumpMinor(version string) (string, error) {
-	v, err := parseVersion(version)
+	v, err := ParseVersion(version)
 	if err != nil {
 		return "", err
 	}
@@ -32,7 +32,7 @@ func BumpMinor(version string) (string, error) {
 
 // BumpPatch increases the patch version number.
 func BumpPatch(version string) (string, error) {
-	v, err := parseVersion(version)
+	v, err := ParseVersion(version)
 	if err != nil {
 		return "", err
 	}
@@ -43,28 +43,20 @@ func BumpPatch(version string) (string, error) {
 // Compare compares two version strings.
 // It returns -1 if v1 < v2, 0 if v1 == v2, and 1 if v1 > v2.
 func Compare(v1, v2 string) (int, error) {
-	ver1, err := parseVersion(v1)
+	ver1, err := ParseVersion(v1)
 	if err != nil {
 		return 0, err
 	}
-	ver2, err := parseVersion(v2)
+	ver2, err := ParseVersion(v2)
 	if err != nil {
 		return 0, err
 	}
 
-	for i := 0; i < 3; i++ {
-		if ver1[i] > ver2[i] {
-			return 1, nil
-		}
-		if ver1[i] < ver2[i] {
-			return -1, nil
-		}
-	}
-	return 0, nil
+	return compareParts(ver1, ver2), nil
 }
 
-// parseVersion converts a version string to an array of integers.
-func parseVersion(version string) ([3]int, error) {
+// ParseVersion converts a version string to an array of integers.
+func ParseVersion(version string) ([3]int, error) {
 	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
 	if len(parts) != 3 {
 		return [3]int{}, fmt.Errorf("invalid version format: %s", version)
@@ -81,6 +73,24 @@ func parseVersion(version string) ([3]int, error) {
 	return v, nil
 }
 
+// VersionTags maps the version of each valid version tag, without a "v"
+// prefix, to the tag. Repositories that switched between prefixed and
+// unprefixed tags can have both, e.g. 1.0.0 and v1.1.0. When a version is
+// tagged both ways, the unprefixed tag is used.
+func VersionTags(tags []string) map[string]string {
+	versions := map[string]string{}
+	for _, tag := range tags {
+		if _, err := ParseVersion(tag); err != nil {
+			continue
+		}
+		version := strings.TrimPrefix(tag, "v")
+		if _, ok := versions[version]; !ok || tag == version {
+			versions[version] = tag
+		}
+	}
+	return versions
+}
+
 // formatVersion converts an array of integers to a version string.
 func formatVersion(v [3]int) string {
 	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
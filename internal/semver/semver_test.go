@@ -1,6 +1,7 @@
 package semver
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -92,3 +93,42 @@ func TestCompare(t *testing.T) {
 		}
 	}
 }
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    [3]int
+		expectError bool
+	}{
+		{"1.2.3", [3]int{1, 2, 3}, false},
+		{"v0.10.1", [3]int{0, 10, 1}, false},
+		{"1.2", [3]int{}, true},
+		{"release-1", [3]int{}, true},
+		{"1.x.3", [3]int{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseVersion(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("ParseVersion(%s) expected an error, got none", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseVersion(%s) returned an error: %v", test.input, err)
+		}
+		if result != test.expected {
+			t.Errorf("ParseVersion(%s) = %v, expected %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestVersionTags(t *testing.T) {
+	tags := []string{"0.9.0", "1.0.0", "nightly", "v1.0.0", "v1.1.0", "v1.2", "1.2.0"}
+	expected := map[string]string{"0.9.0": "0.9.0", "1.0.0": "1.0.0", "1.1.0": "v1.1.0", "1.2.0": "1.2.0"}
+
+	if result := VersionTags(tags); !reflect.DeepEqual(result, expected) {
+		t.Errorf("VersionTags(%v) = %v, expected %v", tags, result, expected)
+	}
+}